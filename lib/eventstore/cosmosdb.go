@@ -0,0 +1,1285 @@
+package eventstore
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// CosmosDBConfig defines the configuration to connect to cosmos db using its NoSQL API
+type CosmosDBConfig struct {
+	Endpoint string `mapstructure:"endpoint" validate:"required,url"`
+	Key      string `mapstructure:"key" validate:"required"`
+
+	Database  string `mapstructure:"database" validate:"required"`
+	Container string `mapstructure:"container" validate:"required"`
+
+	// StreamExtension is the extension attribute used to group events into streams.
+	// Events are grouped by their subject if it is not set.
+	StreamExtension string `mapstructure:"stream_extension" validate:"omitempty,alphanum,lowercase"`
+
+	// InsecureSkipVerify disables verification of the server certificate, e.g. to use the cosmos db emulator
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+
+	// Outbox records every appended event as pending dispatch until it is marked as dispatched.
+	// The marker is a property of the log entry of the event, so it is created along with the entry.
+	Outbox bool `mapstructure:"outbox"`
+}
+
+// Validate ensures cosmos db config is correct
+func (c *CosmosDBConfig) Validate() error {
+	return validator.New().Struct(c)
+}
+
+// The cosmos db container is partitioned by the partitionKey property of its documents. Events are kept in the
+// logical partition of their stream, "stream#<stream>", or of their source, "source#<source>", if they do not
+// belong to a stream, so that the events of an append are written in a single transactional batch along with:
+//
+//   - a marker document for the position of each event, which commits the position.
+//   - the head document of the stream holding its version, which is replaced conditionally on its etag,
+//     so concurrent appends to a stream are kept in order.
+//
+// The document id of an event is derived from its source and id, so events are unique by their id and source
+// within a partition, and a guard document in the partition "event#<source>#<id>" holds the partition each
+// event was appended to, so events are unique by their id and source across partitions.
+//
+// Positions are allocated by incrementing a single counter document, which cosmos db applies one after another
+// rather than rejecting concurrent increments, so appends never conflict over positions. Every append does
+// increment it though, so appends to the container are limited by the throughput of the logical partition
+// holding the counter, which is at most 10,000 RU/s, however many events each append holds.
+//
+// The log is kept in buckets of cosmosLogBucketSize consecutive positions, each in a partition "log#<bucket>",
+// which hold an entry for every position with a copy of its event, the attributes of the event filtered on by
+// Iterate and the state of its append. An append creates the entries of its positions as pending before it
+// writes its events, and marks them as committed or aborted afterwards. Positions can therefore be committed
+// out of order, or never be if an append fails part way through, so readers of the log stop at the first
+// position which is still pending. Once a position has been pending for longer than cosmosAppendLease, readers
+// resolve it themselves by creating its marker as aborted if its append has not committed it, which makes the
+// batch of the append fail.
+//
+// A checkpoint document for each consumer group, partitioned by the name
+// of the group, holds the position the group has committed.
+const (
+	cosmosPartitionKeyPath = "/partitionKey"
+	cosmosMaxBatchItems    = 100
+	cosmosLogBucketSize    = 1000
+	cosmosLogPartition     = "log#"
+	cosmosStreamPartition  = "stream#"
+	cosmosSourcePartition  = "source#"
+	cosmosEventPartition   = "event#"
+	cosmosCounterPartition = "counter"
+	cosmosCounterID        = "counter"
+	cosmosHeadID           = "head"
+	cosmosGuardID          = "guard"
+	cosmosMarkerPrefix     = "position#"
+	cosmosCheckpointPrefix = "checkpoint#"
+	cosmosCheckpointID     = "checkpoint"
+
+	// cosmosAppendLease is how long readers wait for an append to commit or abort a position before aborting it
+	cosmosAppendLease = 30 * time.Second
+
+	// cosmosTimeLayout formats times in UTC with a fixed width, so that they are in order when compared as strings
+	cosmosTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+	cosmosKindEvent      = "event"
+	cosmosKindEntry      = "entry"
+	cosmosKindMarker     = "marker"
+	cosmosKindHead       = "head"
+	cosmosKindGuard      = "guard"
+	cosmosKindCounter    = "counter"
+	cosmosKindCheckpoint = "checkpoint"
+
+	cosmosStatePending   = "pending"
+	cosmosStateCommitted = "committed"
+	cosmosStateAborted   = "aborted"
+)
+
+// errCosmosRetry is returned when a transactional batch was rejected because of a concurrent append
+var errCosmosRetry = errors.New("transactional batch conflicted with a concurrent append")
+
+// cosmosDocument is the union of the kinds of document stored in the container
+type cosmosDocument struct {
+	ID           string `json:"id"`
+	PartitionKey string `json:"partitionKey"`
+	Kind         string `json:"kind"`
+
+	Position  uint64 `json:"position,omitempty"`
+	Stream    string `json:"stream,omitempty"`
+	Version   uint64 `json:"version,omitempty"`
+	Partition string `json:"partition,omitempty"`
+	State     string `json:"state,omitempty"`
+	Pending   bool   `json:"pending,omitempty"`
+
+	// the attributes of an event which are filtered on by Iterate
+	Type       string   `json:"type,omitempty"`
+	Source     string   `json:"source,omitempty"`
+	Subject    *string  `json:"subject,omitempty"`
+	Time       string   `json:"time,omitempty"`
+	Extensions []string `json:"extensions,omitempty"`
+
+	Event json.RawMessage `json:"event,omitempty"`
+
+	// Timestamp is set by cosmos db to when the document was last written, in seconds since the unix epoch
+	Timestamp int64 `json:"_ts,omitempty"`
+}
+
+// newCosmosEntry returns the pending log entry of an event, which is marshaled to json as raw,
+// without the position it is assigned once it is appended
+func newCosmosEntry(ev *event.Event, raw []byte, pending bool) (cosmosDocument, error) {
+	subject := ev.Subject()
+	doc := cosmosDocument{
+		Kind:    cosmosKindEntry,
+		State:   cosmosStatePending,
+		Pending: pending,
+		Type:    ev.Type(),
+		Source:  ev.Source(),
+		Subject: &subject,
+		Event:   raw,
+	}
+	if t := ev.Time(); !t.IsZero() {
+		doc.Time = t.UTC().Format(cosmosTimeLayout)
+	}
+	for name, v := range ev.Extensions() {
+		s, err := types.Format(v)
+		if err != nil {
+			return cosmosDocument{}, NewMarshalError("extension", "string", err)
+		}
+		doc.Extensions = append(doc.Extensions, cosmosExtension(name, s))
+	}
+	sort.Strings(doc.Extensions)
+	return doc, nil
+}
+
+// cosmosExtension returns how an extension attribute is stored on the log entry of an event. Extension
+// names can only hold lowercase letters and digits, so the name and value are always separable.
+func cosmosExtension(name, value string) string {
+	return name + "=" + value
+}
+
+// cosmosPartitionOf returns the partition an event is appended to, which is the partition
+// of its stream, or of its source if it does not belong to a stream
+func cosmosPartitionOf(stream, source string) string {
+	if stream != "" {
+		return cosmosStreamPartition + HashKey(stream)
+	}
+	return cosmosSourcePartition + HashKey(source)
+}
+
+// cosmosLogPartitionFor returns the partition of the bucket of the log holding the entry of a position
+func cosmosLogPartitionFor(position uint64) string {
+	return cosmosLogPartition + strconv.FormatUint(position/cosmosLogBucketSize, 10)
+}
+
+// cosmosBucketEnd returns the last position held by the bucket of the log holding the entry of a position
+func cosmosBucketEnd(position uint64) uint64 {
+	return (position/cosmosLogBucketSize+1)*cosmosLogBucketSize - 1
+}
+
+// cosmosBuckets splits log entries, which are in the order of their positions, by the bucket holding them
+func cosmosBuckets(entries []cosmosDocument) [][]cosmosDocument {
+	var buckets [][]cosmosDocument
+	for i := 0; i < len(entries); {
+		j := i + 1
+		for j < len(entries) && entries[j].PartitionKey == entries[i].PartitionKey {
+			j++
+		}
+		buckets = append(buckets, entries[i:j])
+		i = j
+	}
+	return buckets
+}
+
+// CosmosDB is the event store implementation for cosmos db
+type CosmosDB struct {
+	config    CosmosDBConfig
+	logger    *zap.Logger
+	container *azcosmos.ContainerClient
+
+	// missing holds when positions were first found to have been allocated without a log entry
+	mu      sync.Mutex
+	missing map[uint64]time.Time
+
+	// dispatched is a position up to which every event is known to have been dispatched
+	dispatched atomic.Uint64
+}
+
+// NewCosmosDB constructs and initializes a *CosmosDB, creating its database and container if they do not exist
+func NewCosmosDB(ctx context.Context, config CosmosDBConfig) (*CosmosDB, error) {
+	if ctx == nil {
+		return nil, errors.New("context can not be nil")
+	}
+
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config, %w", err)
+	}
+
+	impl := &CosmosDB{
+		config:  config,
+		logger:  zap.L().With(zap.String("source", "CosmosDBEventStoreImpl")),
+		missing: make(map[uint64]time.Time),
+	}
+
+	err = impl.init(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init, %w", err)
+	}
+
+	return impl, nil
+}
+
+func (c *CosmosDB) init(ctx context.Context) error {
+	cred, err := azcosmos.NewKeyCredential(c.config.Key)
+	if err != nil {
+		c.logger.Error("invalid cosmos db key", zap.Error(err))
+		return NewConnectionError("cosmosdb", err)
+	}
+
+	var opts azcosmos.ClientOptions
+	if c.config.InsecureSkipVerify {
+		opts.Transport = &http.Client{
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+		}
+	}
+	client, err := azcosmos.NewClientWithKey(c.config.Endpoint, cred, &opts)
+	if err != nil {
+		c.logger.Error("failed to create cosmos db client", zap.Error(err))
+		return NewConnectionError("cosmosdb", err)
+	}
+
+	c.logger.Debug("attempting to create database")
+	_, err = client.CreateDatabase(ctx, azcosmos.DatabaseProperties{ID: c.config.Database}, nil)
+	if err != nil && cosmosStatus(err) != http.StatusConflict {
+		c.logger.Error("failed to create database", zap.Error(err))
+		return NewConnectionError("cosmosdb", err)
+	}
+
+	db, err := client.NewDatabase(c.config.Database)
+	if err != nil {
+		return NewConnectionError("cosmosdb", err)
+	}
+
+	c.logger.Debug("attempting to create container")
+	_, err = db.CreateContainer(ctx, azcosmos.ContainerProperties{
+		ID: c.config.Container,
+		PartitionKeyDefinition: azcosmos.PartitionKeyDefinition{
+			Paths: []string{cosmosPartitionKeyPath},
+		},
+	}, nil)
+	if err != nil && cosmosStatus(err) != http.StatusConflict {
+		c.logger.Error("failed to create container", zap.Error(err))
+		return NewConnectionError("cosmosdb", err)
+	}
+
+	c.container, err = db.NewContainer(c.config.Container)
+	if err != nil {
+		return NewConnectionError("cosmosdb", err)
+	}
+	c.logger.Debug("successfully created container")
+
+	return nil
+}
+
+// cosmosStatus returns the http status code of a failed cosmos db request, or 0 if the request was not sent
+func cosmosStatus(err error) int {
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode
+	}
+	return 0
+}
+
+func (c *CosmosDB) readDocument(ctx context.Context, partition, id string) (*cosmosDocument, azcore.ETag, error) {
+	resp, err := c.container.ReadItem(ctx, azcosmos.NewPartitionKeyString(partition), id, nil)
+	if cosmosStatus(err) == http.StatusNotFound {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	var doc cosmosDocument
+	err = json.Unmarshal(resp.Value, &doc)
+	if err != nil {
+		return nil, "", NewMarshalError("json", "cosmos document", err)
+	}
+	return &doc, resp.ETag, nil
+}
+
+// createDocument creates the document, returning false if it already exists
+func (c *CosmosDB) createDocument(ctx context.Context, doc cosmosDocument) (bool, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return false, NewMarshalError("cosmos document", "json", err)
+	}
+	_, err = c.container.CreateItem(ctx, azcosmos.NewPartitionKeyString(doc.PartitionKey), b, nil)
+	if cosmosStatus(err) == http.StatusConflict {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// batchError returns the error of a transactional batch which did not succeed, which is errCosmosRetry
+// if it was rejected because of a concurrent write
+func (c *CosmosDB) batchError(resp azcosmos.TransactionalBatchResponse, what string) error {
+	for _, result := range resp.OperationResults {
+		switch result.StatusCode {
+		case http.StatusFailedDependency:
+			continue
+		case http.StatusConflict, http.StatusPreconditionFailed:
+			return errCosmosRetry
+		}
+		c.logger.Error("transactional batch failed", zap.String("document", what), zap.Int32("status_code", result.StatusCode))
+		return NewPutError("cosmosdb", what, fmt.Errorf("transactional batch failed with status code %d", result.StatusCode))
+	}
+	return NewPutError("cosmosdb", what, errors.New("transactional batch failed"))
+}
+
+// Append puts an event into cosmos db, returning its position in the log, and implements the interface AppendOnly.
+func (c *CosmosDB) Append(ctx context.Context, event *event.Event, expectedVersion uint64) (uint64, error) {
+	positions, err := c.append(ctx, expectedVersion, event)
+	if err != nil {
+		return 0, err
+	}
+	return positions[0], nil
+}
+
+// AppendBatch puts events into cosmos db within a single transactional batch, so either all or none of them
+// are stored, and implements the interface BatchAppendOnly. The positions assigned to the events are returned
+// in the same order as the events. Since a transactional batch is limited to a single partition, every event
+// must belong to the same stream, or come from the same source if they do not belong to a stream, and a batch
+// can hold at most 49 events, otherwise a *UnbatchableError is returned.
+func (c *CosmosDB) AppendBatch(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+	positions, err := c.append(ctx, expectedVersion, events...)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Info("successfully inserted batch", zap.Int("events", len(events)))
+	return positions, nil
+}
+
+func (c *CosmosDB) append(ctx context.Context, expectedVersion uint64, events ...*event.Event) ([]uint64, error) {
+	stream := StreamOf(events[0], c.config.StreamExtension)
+	partition := cosmosPartitionOf(stream, events[0].Source())
+	entries := make([]cosmosDocument, len(events))
+	for i, ev := range events {
+		if cosmosPartitionOf(StreamOf(ev, c.config.StreamExtension), ev.Source()) != partition {
+			return nil, NewUnbatchableError("cosmosdb", "its events belong to different streams, or come from different sources without a stream")
+		}
+
+		b, err := ev.MarshalJSON()
+		if err != nil {
+			c.logger.Error("failed to marshal event to json",
+				zap.Error(err),
+				zap.String("event_id", ev.ID()),
+				zap.String("event_type", ev.Type()),
+				zap.String("event_source", ev.Source()),
+				zap.String("event_subject", ev.Subject()),
+			)
+			return nil, NewMarshalError("*event.Event", "json", err)
+		}
+		entries[i], err = newCosmosEntry(ev, b, c.config.Outbox)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var positions []uint64
+	err := retryAppend(ctx, "cosmosdb", func() (bool, error) {
+		var err error
+		positions, err = c.insert(ctx, expectedVersion, stream, partition, events, entries)
+		if err == errCosmosRetry {
+			// either the same event or another event in the stream
+			// was inserted concurrently, so run the checks again
+			c.logger.Debug("append conflicted with a concurrently inserted event", zap.Int("events", len(events)))
+			return true, err
+		}
+		return false, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// insert puts the events into their partition in a single transactional batch after checking they have not
+// already been inserted and that their stream is at the expected version. errCosmosRetry is returned if the
+// batch is rejected because of a concurrent append.
+func (c *CosmosDB) insert(ctx context.Context, expectedVersion uint64, stream, partition string, events []*event.Event, entries []cosmosDocument) ([]uint64, error) {
+	positions := make([]uint64, len(events))
+
+	var pending []int
+	batched := make(map[string]int)
+	aliases := make(map[int]int)
+	for i, ev := range events {
+		existing, err := c.find(ctx, partition, ev)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			positions[i] = existing.Position
+			continue
+		}
+
+		key := HashKey(ev.Source(), ev.ID())
+		if j, ok := batched[key]; ok {
+			if !sameEvent(events[j], ev) {
+				return nil, NewDuplicateEventError(ev.Source(), ev.ID())
+			}
+			aliases[i] = j
+			continue
+		}
+		batched[key] = i
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return positions, nil
+	}
+	if limit := (cosmosMaxBatchItems - 1) / 2; len(pending) > limit {
+		return nil, NewUnbatchableError("cosmosdb", fmt.Sprintf("it holds %d events but at most %d fit in a transactional batch", len(pending), limit))
+	}
+
+	var version uint64
+	var head *cosmosDocument
+	var etag azcore.ETag
+	if stream != "" {
+		var err error
+		head, etag, err = c.readDocument(ctx, partition, cosmosHeadID)
+		if err != nil {
+			return nil, NewGetError("cosmosdb", "stream version", err)
+		}
+		if head != nil {
+			version = head.Version
+		}
+	}
+	if expectedVersion != AnyVersion && version != expectedVersion {
+		c.logger.Warn("stream is not at expected version",
+			zap.String("stream", stream),
+			zap.Uint64("expected_version", expectedVersion),
+			zap.Uint64("actual_version", version),
+		)
+		return nil, NewVersionConflictError(stream, expectedVersion, version)
+	}
+
+	for _, i := range pending {
+		err := c.guard(ctx, partition, events[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	last, err := c.allocate(ctx, len(pending))
+	if err != nil {
+		c.logger.Error("failed to allocate positions", zap.Error(err))
+		return nil, NewPutError("cosmosdb", "position", err)
+	}
+
+	allocated := make([]cosmosDocument, len(pending))
+	for n, i := range pending {
+		positions[i] = last - uint64(len(pending)-n) + 1
+
+		entry := entries[i]
+		entry.ID = strconv.FormatUint(positions[i], 10)
+		entry.PartitionKey = cosmosLogPartitionFor(positions[i])
+		entry.Position = positions[i]
+		entry.Partition = partition
+		allocated[n] = entry
+	}
+	err = c.createEntries(ctx, allocated)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := c.container.NewTransactionalBatch(azcosmos.NewPartitionKeyString(partition))
+	for n, i := range pending {
+		doc := cosmosDocument{
+			ID:           HashKey(events[i].Source(), events[i].ID()),
+			PartitionKey: partition,
+			Kind:         cosmosKindEvent,
+			Position:     positions[i],
+			Stream:       stream,
+			Event:        entries[i].Event,
+		}
+		if stream != "" {
+			doc.Version = version + uint64(n) + 1
+		}
+		marker := cosmosDocument{
+			ID:           cosmosMarkerPrefix + allocated[n].ID,
+			PartitionKey: partition,
+			Kind:         cosmosKindMarker,
+			Position:     positions[i],
+			State:        cosmosStateCommitted,
+		}
+		for _, doc := range []cosmosDocument{doc, marker} {
+			b, err := json.Marshal(doc)
+			if err != nil {
+				return nil, NewMarshalError("cosmos document", "json", err)
+			}
+			batch.CreateItem(b, nil)
+		}
+	}
+	if stream != "" {
+		b, err := json.Marshal(cosmosDocument{
+			ID:           cosmosHeadID,
+			PartitionKey: partition,
+			Kind:         cosmosKindHead,
+			Stream:       stream,
+			Version:      version + uint64(len(pending)),
+		})
+		if err != nil {
+			return nil, NewMarshalError("cosmos document", "json", err)
+		}
+		if head == nil {
+			batch.CreateItem(b, nil)
+		} else {
+			batch.ReplaceItem(cosmosHeadID, b, &azcosmos.TransactionalBatchItemOptions{IfMatchETag: &etag})
+		}
+	}
+
+	resp, err := c.container.ExecuteTransactionalBatch(ctx, batch, nil)
+	if err != nil {
+		// the batch may have been committed, so its entries are left pending for readers to resolve
+		c.logger.Error("failed to insert events", zap.Error(err))
+		return nil, NewPutError("cosmosdb", "event", err)
+	}
+	if !resp.Success {
+		c.setState(ctx, allocated, cosmosStateAborted)
+		return nil, c.batchError(resp, "event")
+	}
+	c.setState(ctx, allocated, cosmosStateCommitted)
+
+	for i, j := range aliases {
+		positions[i] = positions[j]
+	}
+	for _, i := range pending {
+		c.logger.Info("successfully inserted event",
+			zap.Uint64("position", positions[i]),
+			zap.String("event_id", events[i].ID()),
+			zap.String("event_type", events[i].Type()),
+			zap.String("event_source", events[i].Source()),
+			zap.String("event_subject", events[i].Subject()),
+		)
+	}
+	return positions, nil
+}
+
+// find returns the event with the same source and id as the given event, or nil if it does not exist.
+// A *DuplicateEventError is returned if the event exists with different content or in another partition.
+func (c *CosmosDB) find(ctx context.Context, partition string, ev *event.Event) (*Record, error) {
+	key := HashKey(ev.Source(), ev.ID())
+	doc, _, err := c.readDocument(ctx, partition, key)
+	if err != nil {
+		return nil, NewGetError("cosmosdb", "event", err)
+	}
+	if doc == nil {
+		// the event may be in another partition if its stream is different
+		guard, _, err := c.readDocument(ctx, cosmosEventPartition+key, cosmosGuardID)
+		if err != nil {
+			return nil, NewGetError("cosmosdb", "event", err)
+		}
+		if guard != nil && guard.Partition != partition {
+			return nil, NewDuplicateEventError(ev.Source(), ev.ID())
+		}
+		return nil, nil
+	}
+
+	rec, err := decodeCosmosRecord(doc)
+	if err != nil {
+		return nil, err
+	}
+	if !sameEvent(rec.Event, ev) {
+		c.logger.Warn("event already exists with different content",
+			zap.Uint64("position", rec.Position),
+			zap.String("event_id", ev.ID()),
+			zap.String("event_type", ev.Type()),
+			zap.String("event_source", ev.Source()),
+			zap.String("event_subject", ev.Subject()),
+		)
+		return nil, NewDuplicateEventError(ev.Source(), ev.ID())
+	}
+	return rec, nil
+}
+
+// guard claims the source and id of an event for the partition it is being inserted into. Events in the
+// same partition are kept unique by their document id, so the guard only needs to be claimed once and is
+// left in place if the append fails.
+func (c *CosmosDB) guard(ctx context.Context, partition string, ev *event.Event) error {
+	key := HashKey(ev.Source(), ev.ID())
+	created, err := c.createDocument(ctx, cosmosDocument{
+		ID:           cosmosGuardID,
+		PartitionKey: cosmosEventPartition + key,
+		Kind:         cosmosKindGuard,
+		Partition:    partition,
+	})
+	if err != nil {
+		c.logger.Error("failed to create guard", zap.Error(err))
+		return NewPutError("cosmosdb", "guard", err)
+	}
+	if created {
+		return nil
+	}
+
+	guard, _, err := c.readDocument(ctx, cosmosEventPartition+key, cosmosGuardID)
+	if err != nil {
+		return NewGetError("cosmosdb", "guard", err)
+	}
+	if guard != nil && guard.Partition != partition {
+		return NewDuplicateEventError(ev.Source(), ev.ID())
+	}
+	return nil
+}
+
+// allocate reserves n positions by incrementing the counter, returning the last of them
+func (c *CosmosDB) allocate(ctx context.Context, n int) (uint64, error) {
+	var ops azcosmos.PatchOperations
+	ops.AppendIncrement("/position", int64(n))
+
+	for {
+		resp, err := c.container.PatchItem(ctx, azcosmos.NewPartitionKeyString(cosmosCounterPartition), cosmosCounterID, ops, &azcosmos.ItemOptions{EnableContentResponseOnWrite: true})
+		if cosmosStatus(err) == http.StatusNotFound {
+			_, err = c.createDocument(ctx, cosmosDocument{
+				ID:           cosmosCounterID,
+				PartitionKey: cosmosCounterPartition,
+				Kind:         cosmosKindCounter,
+			})
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		var counter cosmosDocument
+		err = json.Unmarshal(resp.Value, &counter)
+		if err != nil {
+			return 0, NewMarshalError("json", "cosmos document", err)
+		}
+		return counter.Position, nil
+	}
+}
+
+// lastPosition returns the last position which has been allocated
+func (c *CosmosDB) lastPosition(ctx context.Context) (uint64, error) {
+	counter, _, err := c.readDocument(ctx, cosmosCounterPartition, cosmosCounterID)
+	if err != nil {
+		c.logger.Error("failed to get last position", zap.Error(err))
+		return 0, NewGetError("cosmosdb", "position", err)
+	}
+	if counter == nil {
+		return 0, nil
+	}
+	return counter.Position, nil
+}
+
+// createEntries creates the pending log entries of an append. errCosmosRetry is returned if an entry already
+// exists, since a reader aborts a position whose entry has not been created within cosmosAppendLease.
+func (c *CosmosDB) createEntries(ctx context.Context, entries []cosmosDocument) error {
+	var created int
+	for _, bucket := range cosmosBuckets(entries) {
+		batch := c.container.NewTransactionalBatch(azcosmos.NewPartitionKeyString(bucket[0].PartitionKey))
+		for _, entry := range bucket {
+			b, err := json.Marshal(entry)
+			if err != nil {
+				return NewMarshalError("cosmos document", "json", err)
+			}
+			batch.CreateItem(b, nil)
+		}
+
+		resp, err := c.container.ExecuteTransactionalBatch(ctx, batch, nil)
+		if err == nil && resp.Success {
+			created += len(bucket)
+			continue
+		}
+
+		// the append will not commit the entries which were created in earlier buckets
+		c.setState(ctx, entries[:created], cosmosStateAborted)
+		if err != nil {
+			c.logger.Error("failed to create log entries", zap.Error(err))
+			return NewPutError("cosmosdb", "log entry", err)
+		}
+		return c.batchError(resp, "log entry")
+	}
+	return nil
+}
+
+// setState sets the state of the log entries of an append. Failures are only logged, since entries
+// which are left pending are resolved by readers once they have been pending for cosmosAppendLease.
+func (c *CosmosDB) setState(ctx context.Context, entries []cosmosDocument, state string) {
+	var ops azcosmos.PatchOperations
+	ops.AppendSet("/state", state)
+	for _, bucket := range cosmosBuckets(entries) {
+		batch := c.container.NewTransactionalBatch(azcosmos.NewPartitionKeyString(bucket[0].PartitionKey))
+		for _, entry := range bucket {
+			batch.PatchItem(entry.ID, ops, nil)
+		}
+
+		resp, err := c.container.ExecuteTransactionalBatch(ctx, batch, nil)
+		if err == nil && !resp.Success {
+			err = errors.New("transactional batch failed")
+		}
+		if err != nil {
+			c.logger.Warn("failed to set state of log entries", zap.Error(err), zap.String("state", state), zap.Uint64("position", bucket[0].Position))
+		}
+	}
+}
+
+// expired returns whether a position whose log entry is missing has been allocated for longer than
+// cosmosAppendLease, counting from when this store first found the entry to be missing
+func (c *CosmosDB) expired(position uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	first, ok := c.missing[position]
+	if !ok {
+		c.missing[position] = time.Now()
+		return false
+	}
+	return time.Since(first) >= cosmosAppendLease
+}
+
+// found stops tracking a position whose log entry was missing
+func (c *CosmosDB) found(position uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.missing, position)
+}
+
+// resolve returns the state of the append a position was allocated to, given the log entry of the position,
+// which is nil if it is missing. A position which has been pending for longer than cosmosAppendLease is
+// aborted by creating its marker, unless its append has already committed it by creating the marker.
+func (c *CosmosDB) resolve(ctx context.Context, position uint64, entry *cosmosDocument) (string, error) {
+	id := strconv.FormatUint(position, 10)
+	if entry == nil {
+		if !c.expired(position) {
+			return cosmosStatePending, nil
+		}
+
+		created, err := c.createDocument(ctx, cosmosDocument{
+			ID:           id,
+			PartitionKey: cosmosLogPartitionFor(position),
+			Kind:         cosmosKindEntry,
+			Position:     position,
+			State:        cosmosStateAborted,
+		})
+		if err != nil {
+			c.logger.Error("failed to abort position", zap.Error(err), zap.Uint64("position", position))
+			return "", NewPutError("cosmosdb", "log entry", err)
+		}
+		if created {
+			c.logger.Warn("aborted position which was never written", zap.Uint64("position", position))
+			c.found(position)
+			return cosmosStateAborted, nil
+		}
+
+		entry, _, err = c.readDocument(ctx, cosmosLogPartitionFor(position), id)
+		if err != nil || entry == nil {
+			return cosmosStatePending, err
+		}
+	}
+	c.found(position)
+	if entry.State != cosmosStatePending {
+		return entry.State, nil
+	}
+
+	marker, _, err := c.readDocument(ctx, entry.Partition, cosmosMarkerPrefix+id)
+	if err != nil {
+		c.logger.Error("failed to read position marker", zap.Error(err), zap.Uint64("position", position))
+		return "", NewGetError("cosmosdb", "position", err)
+	}
+	if marker == nil {
+		if time.Since(time.Unix(entry.Timestamp, 0)) < cosmosAppendLease {
+			return cosmosStatePending, nil
+		}
+
+		created, err := c.createDocument(ctx, cosmosDocument{
+			ID:           cosmosMarkerPrefix + id,
+			PartitionKey: entry.Partition,
+			Kind:         cosmosKindMarker,
+			Position:     position,
+			State:        cosmosStateAborted,
+		})
+		if err != nil {
+			c.logger.Error("failed to abort position", zap.Error(err), zap.Uint64("position", position))
+			return "", NewPutError("cosmosdb", "position", err)
+		}
+		if created {
+			c.logger.Warn("aborted position which was never committed", zap.Uint64("position", position))
+			marker = &cosmosDocument{State: cosmosStateAborted}
+		} else {
+			// either the append committed the position after all, or another reader aborted it
+			marker, _, err = c.readDocument(ctx, entry.Partition, cosmosMarkerPrefix+id)
+			if err != nil || marker == nil {
+				return cosmosStatePending, err
+			}
+		}
+	}
+
+	entry.PartitionKey = cosmosLogPartitionFor(position)
+	c.setState(ctx, []cosmosDocument{*entry}, marker.State)
+	return marker.State, nil
+}
+
+// resolved returns the last position up to end such that every position after the given position up to it has
+// been committed or aborted, which must all be in the same bucket of the log. Positions which have been pending
+// for longer than cosmosAppendLease are resolved by resolve.
+func (c *CosmosDB) resolved(ctx context.Context, after, end uint64) (uint64, error) {
+	pager := c.container.NewQueryItemsPager(
+		"SELECT c.id, c.position, c.partition, c.state, c._ts FROM c WHERE c.kind = @kind AND c.position > @after AND c.position <= @end ORDER BY c.position",
+		azcosmos.NewPartitionKeyString(cosmosLogPartitionFor(after+1)),
+		&azcosmos.QueryOptions{QueryParameters: []azcosmos.QueryParameter{
+			{Name: "@kind", Value: cosmosKindEntry},
+			{Name: "@after", Value: after},
+			{Name: "@end", Value: end},
+		}},
+	)
+
+	next := after + 1
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("failed to read log entries", zap.Error(err))
+			return 0, NewGetError("cosmosdb", "log entry", err)
+		}
+		for _, item := range resp.Items {
+			var entry cosmosDocument
+			err = json.Unmarshal(item, &entry)
+			if err != nil {
+				return 0, NewMarshalError("json", "cosmos document", err)
+			}
+
+			for ; next <= entry.Position; next++ {
+				var e *cosmosDocument
+				if next == entry.Position {
+					e = &entry
+				}
+				state, err := c.resolve(ctx, next, e)
+				if err != nil {
+					return 0, err
+				}
+				if state == cosmosStatePending {
+					return next - 1, nil
+				}
+			}
+		}
+	}
+	for ; next <= end; next++ {
+		state, err := c.resolve(ctx, next, nil)
+		if err != nil {
+			return 0, err
+		}
+		if state == cosmosStatePending {
+			return next - 1, nil
+		}
+	}
+	return end, nil
+}
+
+func decodeCosmosRecord(doc *cosmosDocument) (*Record, error) {
+	var ev event.Event
+	err := ev.UnmarshalJSON(doc.Event)
+	if err != nil {
+		return nil, NewMarshalError("json", "*event.Event", err)
+	}
+	return &Record{
+		Position: doc.Position,
+		Event:    &ev,
+	}, nil
+}
+
+// Get returns the event with the given source and id by reading its document from the partition
+// held by its guard, and implements the interface Gettable
+func (c *CosmosDB) Get(ctx context.Context, source, id string) (*Record, error) {
+	key := HashKey(source, id)
+	guard, _, err := c.readDocument(ctx, cosmosEventPartition+key, cosmosGuardID)
+	if err != nil {
+		c.logger.Error("failed to read guard", zap.Error(err), zap.String("event_id", id), zap.String("event_source", source))
+		return nil, NewGetError("cosmosdb", "event", err)
+	}
+	if guard == nil {
+		return nil, NewEventNotFoundError(source, id)
+	}
+
+	doc, _, err := c.readDocument(ctx, guard.Partition, key)
+	if err != nil {
+		c.logger.Error("failed to read event", zap.Error(err), zap.String("event_id", id), zap.String("event_source", source))
+		return nil, NewGetError("cosmosdb", "event", err)
+	}
+	if doc == nil {
+		return nil, NewEventNotFoundError(source, id)
+	}
+	return decodeCosmosRecord(doc)
+}
+
+// Checkpoint returns the position a consumer group has committed and implements the interface Checkpointer
+func (c *CosmosDB) Checkpoint(ctx context.Context, group string) (uint64, error) {
+	doc, _, err := c.readDocument(ctx, cosmosCheckpointPrefix+group, cosmosCheckpointID)
+	if err != nil {
+		c.logger.Error("failed to get checkpoint", zap.Error(err), zap.String("group", group))
+		return 0, NewGetError("cosmosdb", "checkpoint", err)
+	}
+	if doc == nil {
+		return 0, nil
+	}
+	return doc.Position, nil
+}
+
+// Commit moves the checkpoint of a consumer group forward to the position and implements the interface Checkpointer.
+// The checkpoint document is replaced conditionally on its etag, so concurrent commits can not move it back.
+func (c *CosmosDB) Commit(ctx context.Context, group string, position uint64) error {
+	partition := cosmosCheckpointPrefix + group
+	checkpoint := cosmosDocument{
+		ID:           cosmosCheckpointID,
+		PartitionKey: partition,
+		Kind:         cosmosKindCheckpoint,
+		Position:     position,
+	}
+	b, err := json.Marshal(checkpoint)
+	if err != nil {
+		return NewMarshalError("cosmos document", "json", err)
+	}
+
+	for {
+		doc, etag, err := c.readDocument(ctx, partition, cosmosCheckpointID)
+		if err != nil {
+			c.logger.Error("failed to get checkpoint", zap.Error(err), zap.String("group", group))
+			return NewPutError("cosmosdb", "checkpoint", err)
+		}
+		if doc != nil && doc.Position >= position {
+			return nil
+		}
+
+		if doc == nil {
+			var created bool
+			created, err = c.createDocument(ctx, checkpoint)
+			if err == nil && !created {
+				continue
+			}
+		} else {
+			_, err = c.container.ReplaceItem(ctx, azcosmos.NewPartitionKeyString(partition), cosmosCheckpointID, b, &azcosmos.ItemOptions{IfMatchEtag: &etag})
+			if cosmosStatus(err) == http.StatusPreconditionFailed {
+				continue
+			}
+		}
+		if err != nil {
+			c.logger.Error("failed to commit checkpoint", zap.Error(err), zap.String("group", group), zap.Uint64("position", position))
+			return NewPutError("cosmosdb", "checkpoint", err)
+		}
+		return nil
+	}
+}
+
+// Iterate returns an Iterator over the events in cosmos db which match the filter in the order they were appended
+// and implements the interface Iterable. The log is read a bucket at a time with a query which applies the filter,
+// up to the first position which is still pending. Events appended after Iterate is called are not returned.
+func (c *CosmosDB) Iterate(ctx context.Context, filter Filter) (Iterator, error) {
+	last, err := c.lastPosition(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &cosmosIterator{
+		store:  c,
+		filter: filter,
+		after:  filter.AfterPosition,
+		last:   last,
+	}, nil
+}
+
+// newCosmosQuery returns the query, and its parameters, of the committed events in the
+// log after one position up to another which match the filter, in the order of their positions
+func newCosmosQuery(filter Filter, after, last uint64) (string, []azcosmos.QueryParameter) {
+	conds := []string{"c.kind = @kind", "c.state = @state", "c.position > @after", "c.position <= @last"}
+	params := []azcosmos.QueryParameter{
+		{Name: "@kind", Value: cosmosKindEntry},
+		{Name: "@state", Value: cosmosStateCommitted},
+		{Name: "@after", Value: after},
+		{Name: "@last", Value: last},
+	}
+	if len(filter.Types) > 0 {
+		conds = append(conds, "ARRAY_CONTAINS(@types, c.type)")
+		params = append(params, azcosmos.QueryParameter{Name: "@types", Value: filter.Types})
+	}
+	if len(filter.Sources) > 0 {
+		conds = append(conds, "ARRAY_CONTAINS(@sources, c.source)")
+		params = append(params, azcosmos.QueryParameter{Name: "@sources", Value: filter.Sources})
+	}
+	if len(filter.Subjects) > 0 {
+		conds = append(conds, "ARRAY_CONTAINS(@subjects, c.subject)")
+		params = append(params, azcosmos.QueryParameter{Name: "@subjects", Value: filter.Subjects})
+	}
+	if !filter.StartTime.IsZero() {
+		conds = append(conds, "c.time >= @start")
+		params = append(params, azcosmos.QueryParameter{Name: "@start", Value: filter.StartTime.UTC().Format(cosmosTimeLayout)})
+	}
+	if !filter.EndTime.IsZero() {
+		conds = append(conds, "c.time < @end")
+		params = append(params, azcosmos.QueryParameter{Name: "@end", Value: filter.EndTime.UTC().Format(cosmosTimeLayout)})
+	}
+
+	names := make([]string, 0, len(filter.Extensions))
+	for name := range filter.Extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		param := "@extension" + strconv.Itoa(i)
+		conds = append(conds, "ARRAY_CONTAINS(c.extensions, "+param+")")
+		params = append(params, azcosmos.QueryParameter{Name: param, Value: cosmosExtension(name, filter.Extensions[name])})
+	}
+
+	return "SELECT * FROM c WHERE " + strings.Join(conds, " AND ") + " ORDER BY c.position", params
+}
+
+// ReadStream returns an Iterator over the events of the stream in the order of their versions and implements the
+// interface StreamReader. The events are read from the partition of the stream with a single query.
+func (c *CosmosDB) ReadStream(ctx context.Context, stream string, opts ReadStreamOptions) (Iterator, error) {
+	if stream == "" {
+		return &recordsIterator{}, nil
+	}
+
+	top, cond, order := "", "c.version >= @from", "c.version"
+	if opts.Backward {
+		cond, order = "c.version <= @from", "c.version DESC"
+	}
+	params := []azcosmos.QueryParameter{
+		{Name: "@kind", Value: cosmosKindEvent},
+		{Name: "@stream", Value: stream},
+		{Name: "@from", Value: opts.from()},
+	}
+	if limit := opts.limit(); limit > 0 {
+		top = "TOP @limit "
+		params = append(params, azcosmos.QueryParameter{Name: "@limit", Value: limit})
+	}
+	query := "SELECT " + top + "* FROM c WHERE c.kind = @kind AND c.stream = @stream AND " + cond + " ORDER BY " + order
+
+	pager := c.container.NewQueryItemsPager(
+		query,
+		azcosmos.NewPartitionKeyString(cosmosPartitionOf(stream, "")),
+		&azcosmos.QueryOptions{QueryParameters: params},
+	)
+
+	var records []*Record
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("failed to read stream events", zap.Error(err), zap.String("stream", stream))
+			return nil, NewGetError("cosmosdb", "event", err)
+		}
+		for _, item := range resp.Items {
+			var doc cosmosDocument
+			err = json.Unmarshal(item, &doc)
+			if err != nil {
+				return nil, NewMarshalError("json", "cosmos document", err)
+			}
+			rec, err := decodeCosmosRecord(&doc)
+			if err != nil {
+				return nil, err
+			}
+			rec.Version = doc.Version
+			records = append(records, rec)
+		}
+	}
+	return &recordsIterator{records: records}, nil
+}
+
+// Pending returns up to limit events which are pending dispatch, in the order they were appended, and
+// implements the interface Outbox. The log is read a bucket at a time up to the first position which is
+// still pending, starting after the last position every event up to is known to have been dispatched.
+func (c *CosmosDB) Pending(ctx context.Context, limit int) ([]*Record, error) {
+	if !c.config.Outbox {
+		return nil, NewOutboxDisabledError("cosmosdb")
+	}
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	last, err := c.lastPosition(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*Record
+	after := c.dispatched.Load()
+	for after < last && len(records) < limit {
+		end := cosmosBucketEnd(after + 1)
+		if end > last {
+			end = last
+		}
+		resolved, err := c.resolved(ctx, after, end)
+		if err != nil {
+			return nil, err
+		}
+		if resolved == after {
+			break
+		}
+
+		it := &cosmosIterator{
+			store: c,
+			pager: c.container.NewQueryItemsPager(
+				"SELECT TOP @limit * FROM c WHERE c.kind = @kind AND c.state = @state AND c.pending = true AND c.position > @after AND c.position <= @last ORDER BY c.position",
+				azcosmos.NewPartitionKeyString(cosmosLogPartitionFor(after+1)),
+				&azcosmos.QueryOptions{QueryParameters: []azcosmos.QueryParameter{
+					{Name: "@limit", Value: limit - len(records)},
+					{Name: "@kind", Value: cosmosKindEntry},
+					{Name: "@state", Value: cosmosStateCommitted},
+					{Name: "@after", Value: after},
+					{Name: "@last", Value: resolved},
+				}},
+			),
+		}
+		found := len(records)
+		for {
+			rec, err := it.Next(ctx)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, rec)
+		}
+		if found == 0 && len(records) == 0 {
+			c.dispatchedUpTo(resolved)
+		}
+
+		if resolved < end {
+			break
+		}
+		after = end
+	}
+	return records, nil
+}
+
+// dispatchedUpTo records that every event up to the position has been dispatched
+func (c *CosmosDB) dispatchedUpTo(position uint64) {
+	for {
+		current := c.dispatched.Load()
+		if current >= position || c.dispatched.CompareAndSwap(current, position) {
+			return
+		}
+	}
+}
+
+// Dispatched clears the pending property of the log entries of the events and implements the interface Outbox
+func (c *CosmosDB) Dispatched(ctx context.Context, records ...*Record) error {
+	if !c.config.Outbox {
+		return NewOutboxDisabledError("cosmosdb")
+	}
+
+	var ops azcosmos.PatchOperations
+	ops.AppendSet("/pending", false)
+	for _, rec := range records {
+		id := strconv.FormatUint(rec.Position, 10)
+		_, err := c.container.PatchItem(ctx, azcosmos.NewPartitionKeyString(cosmosLogPartitionFor(rec.Position)), id, ops, nil)
+		if cosmosStatus(err) == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			c.logger.Error("failed to mark event as dispatched", zap.Error(err), zap.Uint64("position", rec.Position))
+			return NewPutError("cosmosdb", "outbox", err)
+		}
+	}
+	return nil
+}
+
+// cosmosIterator reads the committed events of the log which match a filter, a bucket at a time and a
+// page at a time, up to the first position which is still pending
+type cosmosIterator struct {
+	store  *CosmosDB
+	filter Filter
+
+	// after is the position the log has been queried up to, and last is the position to stop at
+	after uint64
+	last  uint64
+
+	pager   *runtime.Pager[azcosmos.QueryItemsResponse]
+	records []*Record
+}
+
+// Next returns the next event in the log and implements the interface Iterator
+func (it *cosmosIterator) Next(ctx context.Context) (*Record, error) {
+	for len(it.records) == 0 {
+		if it.pager != nil && it.pager.More() {
+			resp, err := it.pager.NextPage(ctx)
+			if err != nil {
+				it.store.logger.Error("failed to read events", zap.Error(err))
+				return nil, NewGetError("cosmosdb", "event", err)
+			}
+			for _, item := range resp.Items {
+				var doc cosmosDocument
+				err = json.Unmarshal(item, &doc)
+				if err != nil {
+					return nil, NewMarshalError("json", "cosmos document", err)
+				}
+				rec, err := decodeCosmosRecord(&doc)
+				if err != nil {
+					return nil, err
+				}
+				it.records = append(it.records, rec)
+			}
+			continue
+		}
+		if it.after >= it.last {
+			return nil, io.EOF
+		}
+
+		end := cosmosBucketEnd(it.after + 1)
+		if end > it.last {
+			end = it.last
+		}
+		resolved, err := it.store.resolved(ctx, it.after, end)
+		if err != nil {
+			return nil, err
+		}
+		if resolved < end {
+			// the events after a pending position are not returned until it has been resolved
+			it.last = resolved
+		}
+		if resolved == it.after {
+			continue
+		}
+
+		query, params := newCosmosQuery(it.filter, it.after, resolved)
+		it.pager = it.store.container.NewQueryItemsPager(
+			query,
+			azcosmos.NewPartitionKeyString(cosmosLogPartitionFor(it.after+1)),
+			&azcosmos.QueryOptions{QueryParameters: params},
+		)
+		it.after = resolved
+	}
+
+	rec := it.records[0]
+	it.records = it.records[1:]
+	return rec, nil
+}
+
+// Close implements the interface Iterator
+func (it *cosmosIterator) Close(ctx context.Context) error {
+	it.pager = nil
+	it.records = nil
+	it.last = it.after
+	return nil
+}
@@ -0,0 +1,389 @@
+package eventstore
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// cosmosEmulatorKey is the well known key of the cosmos db emulator
+const cosmosEmulatorKey = "C2y6yDjf5/R+ob0N8A7Cgv30VRDJIWEHLM+4QDU5DE2nQ9nDuVTqobD4b8mGGyPMbIZnqyMsEcaGQy67XIw/Jw=="
+
+func TestCosmosDBConfig_Validate(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config - no endpoint", func(t *testing.T) {
+		conf := CosmosDBConfig{Key: cosmosEmulatorKey, Database: "evrys", Container: "events"}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - no key", func(t *testing.T) {
+		conf := CosmosDBConfig{Endpoint: "https://localhost:8081", Database: "evrys", Container: "events"}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - no container", func(t *testing.T) {
+		conf := CosmosDBConfig{Endpoint: "https://localhost:8081", Key: cosmosEmulatorKey, Database: "evrys"}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		conf := CosmosDBConfig{
+			Endpoint:        "https://localhost:8081",
+			Key:             cosmosEmulatorKey,
+			Database:        "evrys",
+			Container:       "events",
+			StreamExtension: "aggregateid",
+		}
+		req.NoError(conf.Validate(), "config should have validated")
+	})
+}
+
+func TestNewCosmosDB(t *testing.T) {
+	req := require.New(t)
+	t.Run("nil ctx", func(t *testing.T) {
+		_, err := NewCosmosDB(nil, CosmosDBConfig{})
+		req.ErrorContains(err, "context can not be nil", "error is not target error")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewCosmosDB(context.TODO(), CosmosDBConfig{Endpoint: "https://localhost:8081"})
+		req.ErrorAs(err, &ValidationErrors, "expected validation error")
+	})
+
+	t.Run("cosmos db connection error", func(t *testing.T) {
+		conf := CosmosDBConfig{
+			Endpoint:  "https://localhost:1",
+			Key:       cosmosEmulatorKey,
+			Database:  "evrys",
+			Container: "events",
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, err := NewCosmosDB(ctx, conf)
+		var connErr *ConnectionError
+		req.ErrorAs(err, &connErr, "expected connection error")
+	})
+}
+
+func TestNewCosmosEntry(t *testing.T) {
+	req := require.New(t)
+
+	ev := newMemoryTestEvent("1", "")
+	ev.SetTime(time.Date(2023, 1, 2, 3, 4, 5, 60, time.FixedZone("test", 3600)))
+	ev.SetExtension("tenant", "acme")
+	ev.SetExtension("attempt", 2)
+
+	doc, err := newCosmosEntry(ev, []byte(`{}`), true)
+	req.NoError(err, "failed to create log entry")
+	req.Equal(cosmosKindEntry, doc.Kind, "kind not expected value")
+	req.Equal(cosmosStatePending, doc.State, "an entry should be pending until its append commits it")
+	req.Equal(ev.Type(), doc.Type, "type not expected value")
+	req.Equal(ev.Source(), doc.Source, "source not expected value")
+	req.NotNil(doc.Subject, "an event without a subject should be stored with an empty subject")
+	req.Empty(*doc.Subject, "subject not expected value")
+	req.Equal("2023-01-02T02:04:05.000000060Z", doc.Time, "time should be formatted in utc with a fixed width")
+	req.Equal([]string{"attempt=2", "tenant=acme"}, doc.Extensions, "extensions not expected value")
+	req.True(doc.Pending, "event should be pending")
+}
+
+func TestCosmosPartitionOf(t *testing.T) {
+	req := require.New(t)
+
+	req.Equal(cosmosStreamPartition+HashKey("a"), cosmosPartitionOf("a", "test"), "events should be partitioned by their stream")
+	req.Equal(cosmosPartitionOf("a", "test"), cosmosPartitionOf("a", "other"), "events of a stream should share a partition")
+	req.Equal(cosmosSourcePartition+HashKey("test"), cosmosPartitionOf("", "test"), "events without a stream should be partitioned by their source")
+	req.NotEqual(cosmosPartitionOf("", "test"), cosmosPartitionOf("", "other"), "events from different sources should not share a partition")
+}
+
+func TestCosmosBuckets(t *testing.T) {
+	req := require.New(t)
+
+	req.Equal("log#0", cosmosLogPartitionFor(999), "partition not expected value")
+	req.Equal("log#1", cosmosLogPartitionFor(1000), "partition not expected value")
+	req.Equal(uint64(999), cosmosBucketEnd(1), "bucket end not expected value")
+	req.Equal(uint64(1999), cosmosBucketEnd(1000), "bucket end not expected value")
+
+	var entries []cosmosDocument
+	for _, position := range []uint64{998, 999, 1000} {
+		entries = append(entries, cosmosDocument{Position: position, PartitionKey: cosmosLogPartitionFor(position)})
+	}
+	buckets := cosmosBuckets(entries)
+	req.Equal([][]cosmosDocument{entries[:2], entries[2:]}, buckets, "entries should be split by bucket")
+	req.Empty(cosmosBuckets(nil), "no entries should have no buckets")
+}
+
+func TestNewCosmosQuery(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("no filter", func(t *testing.T) {
+		query, params := newCosmosQuery(Filter{}, 2, 10)
+		req.Equal("SELECT * FROM c WHERE c.kind = @kind AND c.state = @state AND c.position > @after AND c.position <= @last ORDER BY c.position", query, "query not expected value")
+		req.Equal([]azcosmos.QueryParameter{
+			{Name: "@kind", Value: cosmosKindEntry},
+			{Name: "@state", Value: cosmosStateCommitted},
+			{Name: "@after", Value: uint64(2)},
+			{Name: "@last", Value: uint64(10)},
+		}, params, "params not expected value")
+	})
+
+	t.Run("every filter", func(t *testing.T) {
+		start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		query, params := newCosmosQuery(Filter{
+			Types:      []string{"a"},
+			Sources:    []string{"b"},
+			Subjects:   []string{"c", ""},
+			StartTime:  start,
+			EndTime:    start.Add(time.Hour),
+			Extensions: map[string]string{"tenant": "acme", "attempt": "2"},
+		}, 0, 10)
+		req.Equal("SELECT * FROM c WHERE c.kind = @kind AND c.state = @state AND c.position > @after AND c.position <= @last"+
+			" AND ARRAY_CONTAINS(@types, c.type) AND ARRAY_CONTAINS(@sources, c.source) AND ARRAY_CONTAINS(@subjects, c.subject)"+
+			" AND c.time >= @start AND c.time < @end"+
+			" AND ARRAY_CONTAINS(c.extensions, @extension0) AND ARRAY_CONTAINS(c.extensions, @extension1)"+
+			" ORDER BY c.position", query, "query not expected value")
+		req.Equal([]azcosmos.QueryParameter{
+			{Name: "@kind", Value: cosmosKindEntry},
+			{Name: "@state", Value: cosmosStateCommitted},
+			{Name: "@after", Value: uint64(0)},
+			{Name: "@last", Value: uint64(10)},
+			{Name: "@types", Value: []string{"a"}},
+			{Name: "@sources", Value: []string{"b"}},
+			{Name: "@subjects", Value: []string{"c", ""}},
+			{Name: "@start", Value: "2023-01-01T00:00:00.000000000Z"},
+			{Name: "@end", Value: "2023-01-01T01:00:00.000000000Z"},
+			{Name: "@extension0", Value: "attempt=2"},
+			{Name: "@extension1", Value: "tenant=acme"},
+		}, params, "params not expected value")
+	})
+}
+
+func TestCosmosDBIntegration(t *testing.T) {
+	// setup
+	req := require.New(t)
+	ctx := context.Background()
+
+	// container init
+	contReq := testcontainers.ContainerRequest{
+		Image: "mcr.microsoft.com/cosmosdb/linux/azure-cosmos-emulator:latest",
+		Env: map[string]string{
+			"AZURE_COSMOS_EMULATOR_PARTITION_COUNT":         "3",
+			"AZURE_COSMOS_EMULATOR_ENABLE_DATA_PERSISTENCE": "false",
+		},
+		ExposedPorts: []string{"8081:8081", "10251:10251", "10252:10252", "10253:10253", "10254:10254"},
+		WaitingFor:   wait.ForLog("Started").WithStartupTimeout(5 * time.Minute),
+	}
+	cosmosC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: contReq,
+		Started:          true,
+	})
+	req.NoError(err, "failed to create cosmos db container")
+	defer cosmosC.Terminate(ctx)
+
+	// impl setup
+	config := CosmosDBConfig{
+		Endpoint:           "https://localhost:8081",
+		Key:                cosmosEmulatorKey,
+		Database:           "evrys",
+		Container:          "events",
+		InsecureSkipVerify: true,
+		Outbox:             true,
+	}
+
+	cosmosImpl, err := NewCosmosDB(ctx, config)
+	req.NoError(err, "failed to create cosmos db event store")
+
+	_, err = NewCosmosDB(ctx, config)
+	req.NoError(err, "creating the container again should succeed")
+
+	// data setup
+	_event := event.New()
+	id := "some_random_id"
+	curTime := time.Now().UTC()
+	_event.SetID(id)
+	_event.SetSubject("test")
+	_event.SetSource("cosmosdb_test")
+	_event.SetTime(curTime)
+	_event.SetSpecVersion(event.CloudEventsVersionV1)
+	_event.SetType("test")
+	_event.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": "world"})
+
+	// actual test
+	position, err := cosmosImpl.Append(ctx, &_event, AnyVersion)
+	req.NoError(err, "failed to put event")
+	req.Equal(uint64(1), position, "position not expected value")
+
+	// cosmos db verification
+	doc, _, err := cosmosImpl.readDocument(ctx, cosmosPartitionOf("test", ""), HashKey("cosmosdb_test", id))
+	req.NoError(err, "failed to read event document")
+	req.NotNil(doc, "event should be stored in the partition of its stream")
+	req.Equal(uint64(1), doc.Version, "version not expected value")
+
+	entry, _, err := cosmosImpl.readDocument(ctx, cosmosLogPartitionFor(position), "1")
+	req.NoError(err, "failed to read log entry")
+	req.NotNil(entry, "event should be stored in the log")
+	req.Equal(cosmosStateCommitted, entry.State, "state not expected value")
+	req.Equal(curTime.Format(cosmosTimeLayout), entry.Time, "time not expected value")
+
+	// iterate
+	iter, err := cosmosImpl.Iterate(ctx, Filter{})
+	req.NoError(err, "failed to iterate events")
+	defer iter.Close(ctx)
+
+	rec, err := iter.Next(ctx)
+	req.NoError(err, "failed to read event")
+	req.Equal(position, rec.Position, "position not expected value")
+
+	ev := rec.Event
+	req.Equal(id, ev.ID(), "id not expected value")
+	req.Equal("cosmosdb_test", ev.Source(), "source not expected value")
+	req.Equal("test", ev.Type(), "type not expected value")
+	req.Equal("test", ev.Subject(), "subject not expected value")
+	req.Equal(curTime, ev.Time(), "time is not equal")
+	req.JSONEq(`{"hello":"world"}`, string(ev.Data()), "data not expected value")
+
+	_, err = iter.Next(ctx)
+	req.ErrorIs(err, io.EOF, "expected no more events")
+
+	// get
+	got, err := cosmosImpl.Get(ctx, "cosmosdb_test", id)
+	req.NoError(err, "failed to get event")
+	req.Equal(position, got.Position, "position not expected value")
+	req.Equal(curTime, got.Event.Time(), "time is not equal")
+	req.JSONEq(`{"hello":"world"}`, string(got.Event.Data()), "data not expected value")
+
+	_, err = cosmosImpl.Get(ctx, "cosmosdb_test", "missing_id")
+	var notFoundErr *EventNotFoundError
+	req.ErrorAs(err, &notFoundErr, "expected event not found error")
+
+	// outbox
+	pending, err := cosmosImpl.Pending(ctx, 10)
+	req.NoError(err, "failed to get pending events")
+	req.Len(pending, 1, "unexpected number of pending events")
+	req.Equal(position, pending[0].Position, "position not expected value")
+
+	req.NoError(cosmosImpl.Dispatched(ctx, pending...), "failed to mark events as dispatched")
+	pending, err = cosmosImpl.Pending(ctx, 10)
+	req.NoError(err, "failed to get pending events")
+	req.Empty(pending, "every event should have been dispatched")
+
+	// checkpoints
+	checkpoint, err := cosmosImpl.Checkpoint(ctx, "projector")
+	req.NoError(err, "failed to get checkpoint")
+	req.Zero(checkpoint, "a group which has not committed should be at position 0")
+
+	req.NoError(cosmosImpl.Commit(ctx, "projector", 5), "failed to commit checkpoint")
+	req.NoError(cosmosImpl.Commit(ctx, "projector", 3), "failed to commit earlier checkpoint")
+	checkpoint, err = cosmosImpl.Checkpoint(ctx, "projector")
+	req.NoError(err, "failed to get checkpoint")
+	req.Equal(uint64(5), checkpoint, "checkpoint should not have moved back")
+
+	// idempotency
+	samePosition, err := cosmosImpl.Append(ctx, &_event, AnyVersion)
+	req.NoError(err, "re-appending the same event should succeed")
+	req.Equal(position, samePosition, "position not expected value")
+
+	conflicting := _event.Clone()
+	conflicting.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": "there"})
+	_, err = cosmosImpl.Append(ctx, &conflicting, AnyVersion)
+	var dupErr *DuplicateEventError
+	req.ErrorAs(err, &dupErr, "expected duplicate event error")
+
+	otherStream := _event.Clone()
+	otherStream.SetSubject("other")
+	_, err = cosmosImpl.Append(ctx, &otherStream, AnyVersion)
+	req.ErrorAs(err, &dupErr, "expected duplicate event error across streams")
+
+	// stream versions
+	_event.SetID("stale_random_id")
+	_, err = cosmosImpl.Append(ctx, &_event, 0)
+	var conflictErr *VersionConflictError
+	req.ErrorAs(err, &conflictErr, "expected version conflict error")
+	req.Equal("test", conflictErr.Stream, "stream not expected value")
+	req.Equal(uint64(1), conflictErr.Actual, "actual version not expected value")
+
+	nextPosition, err := cosmosImpl.Append(ctx, &_event, 1)
+	req.NoError(err, "failed to put event at expected version")
+	req.Greater(nextPosition, position, "position not expected value")
+
+	// batches
+	newEvent := func(id, subject string) *event.Event {
+		ev := event.New()
+		ev.SetID(id)
+		ev.SetSubject(subject)
+		ev.SetSource("cosmosdb_test")
+		ev.SetSpecVersion(event.CloudEventsVersionV1)
+		ev.SetType("test")
+		return &ev
+	}
+
+	positions, err := cosmosImpl.AppendBatch(ctx, []*event.Event{newEvent("1", "batch"), newEvent("2", "batch")}, 0)
+	req.NoError(err, "failed to put batch")
+	req.Len(positions, 2, "unexpected number of positions")
+
+	samePositions, err := cosmosImpl.AppendBatch(ctx, []*event.Event{newEvent("1", "batch"), newEvent("2", "batch")}, 0)
+	req.NoError(err, "re-appending the same batch should succeed")
+	req.Equal(positions, samePositions, "positions not expected value")
+
+	_, err = cosmosImpl.AppendBatch(ctx, []*event.Event{newEvent("3", "batch"), newEvent("4", "batch")}, 0)
+	req.ErrorAs(err, &conflictErr, "expected version conflict error")
+
+	_, err = cosmosImpl.AppendBatch(ctx, []*event.Event{newEvent("5", "batch"), newEvent("6", "other")}, AnyVersion)
+	var unbatchableErr *UnbatchableError
+	req.ErrorAs(err, &unbatchableErr, "a batch across streams should not be appended")
+
+	positions, err = cosmosImpl.AppendBatch(ctx, []*event.Event{newEvent("5", ""), newEvent("6", "")}, AnyVersion)
+	req.NoError(err, "failed to put batch of events without a stream from the same source")
+	req.Equal([]uint64{positions[0], positions[0] + 1}, positions, "positions should not have gaps")
+
+	_, err = cosmosImpl.Append(ctx, newEvent("7", "test"), AnyVersion)
+	req.NoError(err, "failed to put event")
+
+	// events are returned in order
+	resumed, err := cosmosImpl.Iterate(ctx, Filter{AfterPosition: position})
+	req.NoError(err, "failed to iterate events after position")
+	defer resumed.Close(ctx)
+
+	var ids []string
+	for {
+		rec, err := resumed.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		req.NoError(err, "failed to read event")
+		ids = append(ids, rec.Event.ID())
+	}
+	req.Equal([]string{"stale_random_id", "1", "2", "5", "6", "7"}, ids, "failed batches should not have been stored")
+
+	// the filter is applied by the query
+	filtered, err := cosmosImpl.Iterate(ctx, Filter{Subjects: []string{"batch", ""}, Sources: []string{"cosmosdb_test"}})
+	req.NoError(err, "failed to iterate filtered events")
+	defer filtered.Close(ctx)
+
+	ids = nil
+	for {
+		rec, err := filtered.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		req.NoError(err, "failed to read event")
+		ids = append(ids, rec.Event.ID())
+	}
+	req.Equal([]string{"1", "2", "5", "6"}, ids, "filtered events not expected value")
+
+	// read stream
+	appendStreamTestEvents(t, cosmosImpl)
+	testReadStream(t, cosmosImpl)
+
+	// concurrent appends
+	testSubscribeConcurrentAppends(t, cosmosImpl)
+	testIterateConcurrentAppends(t, cosmosImpl)
+}
@@ -0,0 +1,924 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// DynamoDBConfig defines the configuration to connect to dynamodb. Credentials are
+// loaded from the default AWS credential chain, e.g. the AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY environment variables.
+type DynamoDBConfig struct {
+	Region string `mapstructure:"region" validate:"required"`
+	// Endpoint overrides the dynamodb endpoint, e.g. to use DynamoDB Local
+	Endpoint string `mapstructure:"endpoint" validate:"omitempty,url"`
+
+	Table string `mapstructure:"table" validate:"required"`
+
+	// StreamExtension is the extension attribute used to group events into streams.
+	// Events are grouped by their subject if it is not set.
+	StreamExtension string `mapstructure:"stream_extension" validate:"omitempty,alphanum,lowercase"`
+
+	// Outbox records every appended event as pending dispatch until it is marked as dispatched.
+	// Pending events are kept as outbox items, which are put in the same transaction as the events.
+	Outbox bool `mapstructure:"outbox"`
+}
+
+// Validate ensures dynamodb config is correct
+func (d *DynamoDBConfig) Validate() error {
+	return validator.New().Struct(d)
+}
+
+// The dynamodb table holds several kinds of item, all keyed by a string partition key
+// and a numeric sort key:
+//
+//   - events are keyed by "log#<bucket>" and their position, where a bucket holds
+//     dynamoLogBucketSize consecutive positions, so the log can be read in order
+//     without putting every event in a single partition.
+//   - a guard item keyed by "event#<source>#<id>" holds the position of each event,
+//     so conditional writes on it keep events unique by id and source.
+//   - a head item keyed by "stream#<stream>" and 0 holds the version of each stream, so
+//     conditional writes on it keep stream versions gap-free.
+//   - each event of a stream is also kept in the partition of its stream head, keyed by
+//     "stream#<stream>" and its version, so a stream can be read in order with a strongly
+//     consistent query.
+//   - a single counter item holds the position of the latest event. It is moved on
+//     in the same transaction as the events are put, so every position up to it
+//     has an event.
+//   - when the outbox is enabled, an outbox item keyed by "outbox" and the position
+//     of each event is kept until the event has been dispatched.
+//   - a checkpoint item keyed by "checkpoint#<group>" holds the position each
+//     consumer group has committed.
+//
+// Every append writes the counter item, and writes to a single item are limited to 1,000 write capacity
+// units a second, of which a transactional write uses two, so the table takes at most around 500 appends
+// a second however many events each append holds. Appends from the same *DynamoDB are queued rather than
+// conflicting over the counter, and an append whose position was taken by another process is retried
+// until it succeeds rather than a limited number of times.
+const (
+	dynamoPartitionKey      = "pk"
+	dynamoSortKey           = "sk"
+	dynamoLogBucketSize     = 1000
+	dynamoMaxTransactItems  = 100
+	dynamoConditionFailed   = "ConditionalCheckFailed"
+	dynamoTransactConflict  = "TransactionConflict"
+	dynamoCounterPartition  = "counter"
+	dynamoLogPartition      = "log#"
+	dynamoEventPartition    = "event#"
+	dynamoStreamPartition   = "stream#"
+	dynamoOutboxPartition   = "outbox"
+	dynamoCheckpointPrefix  = "checkpoint#"
+	dynamoMaxBatchGetKeys   = 100
+	dynamoMaxBatchWrites    = 25
+	dynamoTableActiveWait   = 5 * time.Minute
+	dynamoTableActivePoll   = time.Second
+	dynamoLogQueryPageLimit = 100
+)
+
+// errDynamoRetry is returned when a transaction was cancelled by a concurrent append
+var errDynamoRetry = errors.New("transaction conflicted with a concurrent append")
+
+// DynamoDB is the event store implementation for dynamodb
+type DynamoDB struct {
+	config DynamoDBConfig
+	logger *zap.Logger
+	client *dynamodb.Client
+
+	// mu queues appends, since every append writes the counter item
+	mu sync.Mutex
+}
+
+// NewDynamoDB constructs and initializes a *DynamoDB, creating its table if it does not exist
+func NewDynamoDB(ctx context.Context, config DynamoDBConfig) (*DynamoDB, error) {
+	if ctx == nil {
+		return nil, errors.New("context can not be nil")
+	}
+
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config, %w", err)
+	}
+
+	impl := &DynamoDB{
+		config: config,
+		logger: zap.L().With(zap.String("source", "DynamoDBEventStoreImpl")),
+	}
+
+	err = impl.init(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init, %w", err)
+	}
+
+	return impl, nil
+}
+
+func (d *DynamoDB) init(ctx context.Context) error {
+	d.logger.Debug("attempting to load aws config")
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(d.config.Region))
+	if err != nil {
+		d.logger.Error("failed to load aws config", zap.Error(err))
+		return NewConnectionError("dynamodb", err)
+	}
+
+	d.client = dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		if d.config.Endpoint != "" {
+			o.EndpointResolver = dynamodb.EndpointResolverFromURL(d.config.Endpoint)
+		}
+	})
+
+	d.logger.Debug("attempting to create table")
+	err = d.createTable(ctx)
+	if err != nil {
+		d.logger.Error("failed to create table", zap.Error(err))
+		return NewConnectionError("dynamodb", err)
+	}
+	d.logger.Debug("successfully created table")
+
+	return nil
+}
+
+func (d *DynamoDB) createTable(ctx context.Context) error {
+	_, err := d.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(d.config.Table)})
+	if err == nil {
+		return nil
+	}
+	var notFound *types.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		return err
+	}
+
+	_, err = d.client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:   aws.String(d.config.Table),
+		BillingMode: types.BillingModePayPerRequest,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String(dynamoPartitionKey), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String(dynamoSortKey), AttributeType: types.ScalarAttributeTypeN},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String(dynamoPartitionKey), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String(dynamoSortKey), KeyType: types.KeyTypeRange},
+		},
+	})
+	var inUse *types.ResourceInUseException
+	if err != nil && !errors.As(err, &inUse) {
+		return err
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(d.client, func(o *dynamodb.TableExistsWaiterOptions) {
+		o.MinDelay = dynamoTableActivePoll
+	})
+	return waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(d.config.Table)}, dynamoTableActiveWait)
+}
+
+func dynamoKey(partition string, sort uint64) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		dynamoPartitionKey: &types.AttributeValueMemberS{Value: partition},
+		dynamoSortKey:      dynamoNumber(sort),
+	}
+}
+
+func dynamoNumber(n uint64) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatUint(n, 10)}
+}
+
+func dynamoLogPartitionFor(position uint64) string {
+	return dynamoLogPartition + strconv.FormatUint(position/dynamoLogBucketSize, 10)
+}
+
+func dynamoLogKey(position uint64) map[string]types.AttributeValue {
+	return dynamoKey(dynamoLogPartitionFor(position), position)
+}
+
+// dynamoEventKey returns the key of the guard item of an event. The length of the source
+// is included so that no two pairs of source and id can produce the same key.
+func dynamoEventKey(source, id string) map[string]types.AttributeValue {
+	return dynamoKey(fmt.Sprintf("%s%d#%s#%s", dynamoEventPartition, len(source), source, id), 0)
+}
+
+func dynamoStreamKey(stream string) map[string]types.AttributeValue {
+	return dynamoKey(dynamoStreamPartition+stream, 0)
+}
+
+func dynamoStreamEventKey(stream string, version uint64) map[string]types.AttributeValue {
+	return dynamoKey(dynamoStreamPartition+stream, version)
+}
+
+func dynamoCounterKey() map[string]types.AttributeValue {
+	return dynamoKey(dynamoCounterPartition, 0)
+}
+
+func dynamoOutboxKey(position uint64) map[string]types.AttributeValue {
+	return dynamoKey(dynamoOutboxPartition, position)
+}
+
+func dynamoCheckpointKey(group string) map[string]types.AttributeValue {
+	return dynamoKey(dynamoCheckpointPrefix+group, 0)
+}
+
+// Append puts an event into dynamodb, returning its position in the log, and implements the interface AppendOnly.
+// Positions are taken from a counter item which is updated in the same transaction as the events are put,
+// so positions are committed in order without gaps.
+func (d *DynamoDB) Append(ctx context.Context, event *event.Event, expectedVersion uint64) (uint64, error) {
+	positions, err := d.append(ctx, expectedVersion, event)
+	if err != nil {
+		return 0, err
+	}
+	return positions[0], nil
+}
+
+// AppendBatch puts events into dynamodb within a single transaction, so either all or none of them are
+// stored, and implements the interface BatchAppendOnly. The positions assigned to the events are
+// returned in the same order as the events. A dynamodb transaction is limited to 100 items, and each
+// event takes two of them, plus one if it belongs to a stream and one when the outbox is enabled, along
+// with one for each stream in the batch and one for the counter item.
+func (d *DynamoDB) AppendBatch(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+	positions, err := d.append(ctx, expectedVersion, events...)
+	if err != nil {
+		return nil, err
+	}
+	d.logger.Info("successfully inserted batch", zap.Int("events", len(events)))
+	return positions, nil
+}
+
+func (d *DynamoDB) append(ctx context.Context, expectedVersion uint64, events ...*event.Event) ([]uint64, error) {
+	data := make([]string, len(events))
+	for i, ev := range events {
+		b, err := ev.MarshalJSON()
+		if err != nil {
+			d.logger.Error("failed to marshal event to json",
+				zap.Error(err),
+				zap.String("event_id", ev.ID()),
+				zap.String("event_type", ev.Type()),
+				zap.String("event_source", ev.Source()),
+				zap.String("event_subject", ev.Subject()),
+			)
+			return nil, NewMarshalError("*event.Event", "json", err)
+		}
+		data[i] = string(b)
+	}
+
+	var positions []uint64
+	err := retryAppend(ctx, "dynamodb", func() (bool, error) {
+		var err error
+		positions, err = d.insert(ctx, events, data, expectedVersion)
+		if err == errPositionTaken {
+			d.logger.Debug("positions were taken by a concurrently inserted event", zap.Int("events", len(events)))
+			return true, err
+		}
+		if err == errDynamoRetry {
+			// either the same event or another event in one of the streams
+			// was inserted concurrently, so run the checks again
+			d.logger.Debug("append conflicted with a concurrently inserted event", zap.Int("events", len(events)))
+			return true, err
+		}
+		return false, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// insert puts the events into dynamodb in a single transaction after checking they have not already
+// been inserted and that their streams are at the expected version. errPositionTaken is returned if
+// the transaction is cancelled because a concurrent append moved the counter on, and errDynamoRetry
+// if it is cancelled by a concurrent append to the same events or streams.
+func (d *DynamoDB) insert(ctx context.Context, events []*event.Event, data []string, expectedVersion uint64) ([]uint64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	positions := make([]uint64, len(events))
+
+	var pending []int
+	batched := make(map[eventKey]int)
+	aliases := make(map[int]int)
+	for i, ev := range events {
+		existing, err := d.find(ctx, ev.Source(), ev.ID())
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			if !sameEvent(existing.Event, ev) {
+				d.logger.Warn("event already exists with different content",
+					zap.Uint64("position", existing.Position),
+					zap.String("event_id", ev.ID()),
+					zap.String("event_type", ev.Type()),
+					zap.String("event_source", ev.Source()),
+					zap.String("event_subject", ev.Subject()),
+				)
+				return nil, NewDuplicateEventError(ev.Source(), ev.ID())
+			}
+			positions[i] = existing.Position
+			continue
+		}
+
+		key := eventKey{source: ev.Source(), id: ev.ID()}
+		if j, ok := batched[key]; ok {
+			if !sameEvent(events[j], ev) {
+				return nil, NewDuplicateEventError(ev.Source(), ev.ID())
+			}
+			aliases[i] = j
+			continue
+		}
+		batched[key] = i
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return positions, nil
+	}
+
+	current := make(map[string]uint64)
+	for _, ev := range events {
+		stream := StreamOf(ev, d.config.StreamExtension)
+		if _, ok := current[stream]; ok {
+			continue
+		}
+		version, err := d.streamVersion(ctx, stream)
+		if err != nil {
+			return nil, NewGetError("dynamodb", "stream version", err)
+		}
+		if expectedVersion != AnyVersion && version != expectedVersion {
+			d.logger.Warn("stream is not at expected version",
+				zap.String("stream", stream),
+				zap.Uint64("expected_version", expectedVersion),
+				zap.Uint64("actual_version", version),
+			)
+			return nil, NewVersionConflictError(stream, expectedVersion, version)
+		}
+		current[stream] = version
+	}
+
+	last, err := d.lastPosition(ctx)
+	if err != nil {
+		d.logger.Error("failed to get last position", zap.Error(err))
+		return nil, NewGetError("dynamodb", "position", err)
+	}
+
+	versions := make(map[string]uint64)
+	items := make([]types.TransactWriteItem, 0, 4*len(pending)+len(current)+1)
+	for n, i := range pending {
+		ev := events[i]
+		positions[i] = last + uint64(n) + 1
+
+		item := dynamoLogKey(positions[i])
+		item["id"] = &types.AttributeValueMemberS{Value: ev.ID()}
+		item["source"] = &types.AttributeValueMemberS{Value: ev.Source()}
+		item["type"] = &types.AttributeValueMemberS{Value: ev.Type()}
+		item["data"] = &types.AttributeValueMemberS{Value: data[i]}
+
+		guard := dynamoEventKey(ev.Source(), ev.ID())
+		guard["position"] = dynamoNumber(positions[i])
+
+		items = append(items,
+			types.TransactWriteItem{Put: &types.Put{
+				TableName:           aws.String(d.config.Table),
+				Item:                item,
+				ConditionExpression: aws.String("attribute_not_exists(pk)"),
+			}},
+			types.TransactWriteItem{Put: &types.Put{
+				TableName:           aws.String(d.config.Table),
+				Item:                guard,
+				ConditionExpression: aws.String("attribute_not_exists(pk)"),
+			}},
+		)
+		if stream := StreamOf(ev, d.config.StreamExtension); stream != "" {
+			if _, ok := versions[stream]; !ok {
+				versions[stream] = current[stream]
+			}
+			versions[stream]++
+
+			streamItem := dynamoStreamEventKey(stream, versions[stream])
+			streamItem["position"] = dynamoNumber(positions[i])
+			streamItem["data"] = &types.AttributeValueMemberS{Value: data[i]}
+			items = append(items, types.TransactWriteItem{Put: &types.Put{
+				TableName:           aws.String(d.config.Table),
+				Item:                streamItem,
+				ConditionExpression: aws.String("attribute_not_exists(pk)"),
+			}})
+		}
+		if d.config.Outbox {
+			items = append(items, types.TransactWriteItem{Put: &types.Put{
+				TableName: aws.String(d.config.Table),
+				Item:      dynamoOutboxKey(positions[i]),
+			}})
+		}
+	}
+	for stream, version := range versions {
+		head := dynamoStreamKey(stream)
+		head["version"] = dynamoNumber(version)
+
+		put := &types.Put{
+			TableName:           aws.String(d.config.Table),
+			Item:                head,
+			ConditionExpression: aws.String("attribute_not_exists(pk)"),
+		}
+		if v := current[stream]; v > 0 {
+			put.ConditionExpression = aws.String("version = :version")
+			put.ExpressionAttributeValues = map[string]types.AttributeValue{":version": dynamoNumber(v)}
+		}
+		items = append(items, types.TransactWriteItem{Put: put})
+	}
+
+	// the counter is only moved on from the position read above, so a concurrent
+	// append which took the same positions cancels this transaction
+	counter := dynamoCounterKey()
+	counter["position"] = dynamoNumber(last + uint64(len(pending)))
+	put := &types.Put{
+		TableName:           aws.String(d.config.Table),
+		Item:                counter,
+		ConditionExpression: aws.String("attribute_not_exists(pk)"),
+	}
+	if last > 0 {
+		put.ConditionExpression = aws.String("#position = :position")
+		put.ExpressionAttributeNames = map[string]string{"#position": "position"}
+		put.ExpressionAttributeValues = map[string]types.AttributeValue{":position": dynamoNumber(last)}
+	}
+	items = append(items, types.TransactWriteItem{Put: put})
+	if len(items) > dynamoMaxTransactItems {
+		return nil, NewUnbatchableError("dynamodb", fmt.Sprintf("it needs %d transaction items but at most %d are allowed", len(items), dynamoMaxTransactItems))
+	}
+
+	_, err = d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	var cancelled *types.TransactionCanceledException
+	if errors.As(err, &cancelled) {
+		if retryErr := retryableCancellation(cancelled, len(items)-1); retryErr != nil {
+			return nil, retryErr
+		}
+	}
+	if err != nil {
+		d.logger.Error("failed to insert events", zap.Error(err))
+		return nil, NewPutError("dynamodb", "event", err)
+	}
+
+	for i, j := range aliases {
+		positions[i] = positions[j]
+	}
+	for _, i := range pending {
+		d.logger.Info("successfully inserted event",
+			zap.Uint64("position", positions[i]),
+			zap.String("event_id", events[i].ID()),
+			zap.String("event_type", events[i].Type()),
+			zap.String("event_source", events[i].Source()),
+			zap.String("event_subject", events[i].Subject()),
+		)
+	}
+	return positions, nil
+}
+
+// retryableCancellation returns the error to retry a transaction with if it was only cancelled by concurrent
+// writes, or nil otherwise. errPositionTaken is returned if only the counter, the item at the given index of
+// the transaction, was written concurrently.
+func retryableCancellation(err *types.TransactionCanceledException, counter int) error {
+	var retryErr error
+	for i, reason := range err.CancellationReasons {
+		switch aws.ToString(reason.Code) {
+		case "", "None":
+		case dynamoConditionFailed, dynamoTransactConflict:
+			if i != counter {
+				retryErr = errDynamoRetry
+			} else if retryErr == nil {
+				retryErr = errPositionTaken
+			}
+		default:
+			return nil
+		}
+	}
+	return retryErr
+}
+
+// lastPosition returns the position of the latest event
+func (d *DynamoDB) lastPosition(ctx context.Context) (uint64, error) {
+	out, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(d.config.Table),
+		Key:            dynamoCounterKey(),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if out.Item == nil {
+		return 0, nil
+	}
+	return dynamoUint(out.Item, "position")
+}
+
+func dynamoUint(item map[string]types.AttributeValue, name string) (uint64, error) {
+	n, ok := item[name].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("attribute %s is not a number", name)
+	}
+	return strconv.ParseUint(n.Value, 10, 64)
+}
+
+// find returns the event with the given source and id, or nil if it does not exist
+func (d *DynamoDB) find(ctx context.Context, source, id string) (*Record, error) {
+	out, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(d.config.Table),
+		Key:            dynamoEventKey(source, id),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, NewGetError("dynamodb", "event", err)
+	}
+	if out.Item == nil {
+		return nil, nil
+	}
+	position, err := dynamoUint(out.Item, "position")
+	if err != nil {
+		return nil, NewMarshalError("dynamodb", "position", err)
+	}
+
+	// the guard and event are written in the same transaction so the event must exist
+	out, err = d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(d.config.Table),
+		Key:            dynamoLogKey(position),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, NewGetError("dynamodb", "event", err)
+	}
+	if out.Item == nil {
+		return nil, NewGetError("dynamodb", "event", fmt.Errorf("no event at position %d", position))
+	}
+	return decodeDynamoRecord(out.Item)
+}
+
+// streamVersion returns the version of the latest event in the stream
+func (d *DynamoDB) streamVersion(ctx context.Context, stream string) (uint64, error) {
+	if stream == "" {
+		return 0, nil
+	}
+
+	out, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(d.config.Table),
+		Key:            dynamoStreamKey(stream),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if out.Item == nil {
+		return 0, nil
+	}
+	return dynamoUint(out.Item, "version")
+}
+
+func decodeDynamoRecord(item map[string]types.AttributeValue) (*Record, error) {
+	position, err := dynamoUint(item, dynamoSortKey)
+	if err != nil {
+		return nil, NewMarshalError("dynamodb", "position", err)
+	}
+	data, ok := item["data"].(*types.AttributeValueMemberS)
+	if !ok {
+		return nil, NewMarshalError("dynamodb", "json", errors.New("attribute data is not a string"))
+	}
+
+	var ev event.Event
+	err = ev.UnmarshalJSON([]byte(data.Value))
+	if err != nil {
+		return nil, NewMarshalError("json", "*event.Event", err)
+	}
+	return &Record{
+		Position: position,
+		Event:    &ev,
+	}, nil
+}
+
+// decodeDynamoStreamRecord decodes an event kept in the partition of its stream, which is keyed by its version
+func decodeDynamoStreamRecord(item map[string]types.AttributeValue) (*Record, error) {
+	rec, err := decodeDynamoRecord(item)
+	if err != nil {
+		return nil, err
+	}
+	rec.Version = rec.Position
+	rec.Position, err = dynamoUint(item, "position")
+	if err != nil {
+		return nil, NewMarshalError("dynamodb", "position", err)
+	}
+	return rec, nil
+}
+
+// Get returns the event with the given source and id by reading the guard item holding its position,
+// and implements the interface Gettable
+func (d *DynamoDB) Get(ctx context.Context, source, id string) (*Record, error) {
+	rec, err := d.find(ctx, source, id)
+	if err != nil {
+		d.logger.Error("failed to find event", zap.Error(err), zap.String("event_id", id), zap.String("event_source", source))
+		return nil, err
+	}
+	if rec == nil {
+		return nil, NewEventNotFoundError(source, id)
+	}
+	return rec, nil
+}
+
+// ReadStream returns an Iterator over the events of the stream in the order of their versions and implements the
+// interface StreamReader. The events are read with a strongly consistent query of the partition of the stream, so
+// every event appended to the stream before ReadStream is called is returned.
+func (d *DynamoDB) ReadStream(ctx context.Context, stream string, opts ReadStreamOptions) (Iterator, error) {
+	if stream == "" {
+		return &recordsIterator{}, nil
+	}
+
+	// the head of the stream is at sort key 0, so the versions read start at 1
+	from := uint64(opts.from())
+	if from == 0 {
+		from = 1
+	}
+	cond := "pk = :pk AND sk >= :from"
+	values := map[string]types.AttributeValue{
+		":pk":   &types.AttributeValueMemberS{Value: dynamoStreamPartition + stream},
+		":from": dynamoNumber(from),
+	}
+	if opts.Backward {
+		cond = "pk = :pk AND sk BETWEEN :first AND :from"
+		values[":first"] = dynamoNumber(1)
+	}
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(d.config.Table),
+		KeyConditionExpression:    aws.String(cond),
+		ExpressionAttributeValues: values,
+		ConsistentRead:            aws.Bool(true),
+		ScanIndexForward:          aws.Bool(!opts.Backward),
+	}
+
+	var records []*Record
+	limit := opts.limit()
+	for {
+		if limit > 0 {
+			remaining := limit - int64(len(records))
+			if remaining > math.MaxInt32 {
+				remaining = math.MaxInt32
+			}
+			input.Limit = aws.Int32(int32(remaining))
+		}
+
+		out, err := d.client.Query(ctx, input)
+		if err != nil {
+			d.logger.Error("failed to query stream", zap.Error(err), zap.String("stream", stream))
+			return nil, NewGetError("dynamodb", "event", err)
+		}
+		for _, item := range out.Items {
+			rec, err := decodeDynamoStreamRecord(item)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, rec)
+		}
+
+		if out.LastEvaluatedKey == nil || (limit > 0 && int64(len(records)) >= limit) {
+			return &recordsIterator{records: records}, nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+// Pending returns up to limit events which are pending dispatch, in the order they were appended, by querying
+// the outbox items and then reading the events they refer to, and implements the interface Outbox
+func (d *DynamoDB) Pending(ctx context.Context, limit int) ([]*Record, error) {
+	if !d.config.Outbox {
+		return nil, NewOutboxDisabledError("dynamodb")
+	}
+	if limit <= 0 {
+		return nil, nil
+	}
+	if limit > dynamoMaxBatchGetKeys {
+		limit = dynamoMaxBatchGetKeys
+	}
+
+	out, err := d.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(d.config.Table),
+		KeyConditionExpression:    aws.String("pk = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":pk": &types.AttributeValueMemberS{Value: dynamoOutboxPartition}},
+		ConsistentRead:            aws.Bool(true),
+		Limit:                     aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		d.logger.Error("failed to query outbox", zap.Error(err))
+		return nil, NewGetError("dynamodb", "outbox", err)
+	}
+	if len(out.Items) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]map[string]types.AttributeValue, len(out.Items))
+	for i, item := range out.Items {
+		position, err := dynamoUint(item, dynamoSortKey)
+		if err != nil {
+			return nil, NewMarshalError("dynamodb", "position", err)
+		}
+		keys[i] = dynamoLogKey(position)
+	}
+
+	records := make([]*Record, 0, len(keys))
+	for len(keys) > 0 {
+		got, err := d.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]types.KeysAndAttributes{
+				d.config.Table: {Keys: keys, ConsistentRead: aws.Bool(true)},
+			},
+		})
+		if err != nil {
+			d.logger.Error("failed to get pending events", zap.Error(err))
+			return nil, NewGetError("dynamodb", "event", err)
+		}
+		for _, item := range got.Responses[d.config.Table] {
+			rec, err := decodeDynamoRecord(item)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, rec)
+		}
+		keys = got.UnprocessedKeys[d.config.Table].Keys
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Position < records[j].Position
+	})
+	return records, nil
+}
+
+// Dispatched deletes the outbox items of the events and implements the interface Outbox
+func (d *DynamoDB) Dispatched(ctx context.Context, records ...*Record) error {
+	if !d.config.Outbox {
+		return NewOutboxDisabledError("dynamodb")
+	}
+
+	requests := make([]types.WriteRequest, len(records))
+	for i, rec := range records {
+		requests[i] = types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: dynamoOutboxKey(rec.Position)}}
+	}
+	for len(requests) > 0 {
+		n := len(requests)
+		if n > dynamoMaxBatchWrites {
+			n = dynamoMaxBatchWrites
+		}
+		out, err := d.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{d.config.Table: requests[:n]},
+		})
+		if err != nil {
+			d.logger.Error("failed to delete outbox items", zap.Error(err))
+			return NewPutError("dynamodb", "outbox", err)
+		}
+		requests = append(out.UnprocessedItems[d.config.Table], requests[n:]...)
+	}
+	return nil
+}
+
+// Checkpoint returns the position a consumer group has committed and implements the interface Checkpointer
+func (d *DynamoDB) Checkpoint(ctx context.Context, group string) (uint64, error) {
+	out, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(d.config.Table),
+		Key:            dynamoCheckpointKey(group),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		d.logger.Error("failed to get checkpoint", zap.Error(err), zap.String("group", group))
+		return 0, NewGetError("dynamodb", "checkpoint", err)
+	}
+	if out.Item == nil {
+		return 0, nil
+	}
+
+	position, err := dynamoUint(out.Item, "position")
+	if err != nil {
+		return 0, NewMarshalError("dynamodb", "position", err)
+	}
+	return position, nil
+}
+
+// Commit moves the checkpoint of a consumer group forward to the position and implements the interface Checkpointer.
+// The checkpoint item is only updated if the position is after the current one.
+func (d *DynamoDB) Commit(ctx context.Context, group string, position uint64) error {
+	_, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(d.config.Table),
+		Key:                       dynamoCheckpointKey(group),
+		UpdateExpression:          aws.String("SET #position = :position"),
+		ConditionExpression:       aws.String("attribute_not_exists(#position) OR #position < :position"),
+		ExpressionAttributeNames:  map[string]string{"#position": "position"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":position": dynamoNumber(position)},
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return nil
+	}
+	if err != nil {
+		d.logger.Error("failed to commit checkpoint", zap.Error(err), zap.String("group", group), zap.Uint64("position", position))
+		return NewPutError("dynamodb", "checkpoint", err)
+	}
+	return nil
+}
+
+// Iterate returns an Iterator over the events in dynamodb which match the filter in the order they were appended
+// and implements the interface Iterable. Events appended after Iterate is called are not returned.
+func (d *DynamoDB) Iterate(ctx context.Context, filter Filter) (Iterator, error) {
+	last, err := d.lastPosition(ctx)
+	if err != nil {
+		d.logger.Error("failed to get last position", zap.Error(err))
+		return nil, NewGetError("dynamodb", "position", err)
+	}
+
+	next := filter.AfterPosition + 1
+	if filter.AfterPosition >= last {
+		next = last + 1
+	}
+	return &dynamoIterator{
+		logger: d.logger,
+		client: d.client,
+		table:  d.config.Table,
+		filter: filter,
+		next:   next,
+		last:   last,
+	}, nil
+}
+
+type dynamoIterator struct {
+	logger  *zap.Logger
+	client  *dynamodb.Client
+	table   string
+	filter  Filter
+	next    uint64
+	last    uint64
+	records []*Record
+}
+
+// Next returns the next matching event, reading the log a page at a time, and implements the interface Iterator
+func (it *dynamoIterator) Next(ctx context.Context) (*Record, error) {
+	for {
+		for len(it.records) > 0 {
+			rec := it.records[0]
+			it.records = it.records[1:]
+			if it.filter.match(rec) {
+				return rec, nil
+			}
+		}
+		if it.next > it.last {
+			return nil, io.EOF
+		}
+
+		err := it.fetch(ctx)
+		if err != nil {
+			it.logger.Error("failed to read events", zap.Error(err))
+			return nil, err
+		}
+	}
+}
+
+// fetch reads the next page of events from the bucket holding the next position
+func (it *dynamoIterator) fetch(ctx context.Context) error {
+	to := (it.next/dynamoLogBucketSize+1)*dynamoLogBucketSize - 1
+	if to > it.last {
+		to = it.last
+	}
+
+	out, err := it.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(it.table),
+		KeyConditionExpression: aws.String("pk = :pk AND sk BETWEEN :from AND :to"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":   &types.AttributeValueMemberS{Value: dynamoLogPartitionFor(it.next)},
+			":from": dynamoNumber(it.next),
+			":to":   dynamoNumber(to),
+		},
+		ConsistentRead: aws.Bool(true),
+		Limit:          aws.Int32(dynamoLogQueryPageLimit),
+	})
+	if err != nil {
+		return NewGetError("dynamodb", "event", err)
+	}
+
+	for _, item := range out.Items {
+		rec, err := decodeDynamoRecord(item)
+		if err != nil {
+			return err
+		}
+		it.records = append(it.records, rec)
+	}
+
+	// every position up to the last has an event, so the
+	// bucket is finished once a page is not truncated
+	it.next = to + 1
+	if out.LastEvaluatedKey != nil && len(it.records) > 0 {
+		it.next = it.records[len(it.records)-1].Position + 1
+	}
+	return nil
+}
+
+// Close implements the interface Iterator
+func (it *dynamoIterator) Close(ctx context.Context) error {
+	it.records = nil
+	it.next = it.last + 1
+	return nil
+}
@@ -0,0 +1,279 @@
+package eventstore
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestDynamoDBConfig_Validate(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config - no region", func(t *testing.T) {
+		conf := DynamoDBConfig{Table: "events"}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - no table", func(t *testing.T) {
+		conf := DynamoDBConfig{Region: "us-east-1"}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - endpoint", func(t *testing.T) {
+		conf := DynamoDBConfig{Region: "us-east-1", Table: "events", Endpoint: "not a url"}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		conf := DynamoDBConfig{Region: "us-east-1", Table: "events", Endpoint: "http://localhost:8000"}
+		req.NoError(conf.Validate(), "config should have validated")
+	})
+}
+
+func TestNewDynamoDB(t *testing.T) {
+	req := require.New(t)
+	t.Run("nil ctx", func(t *testing.T) {
+		_, err := NewDynamoDB(nil, DynamoDBConfig{})
+		req.ErrorContains(err, "context can not be nil", "error is not target error")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewDynamoDB(context.TODO(), DynamoDBConfig{Region: "us-east-1"})
+		req.ErrorAs(err, &ValidationErrors, "expected validation error")
+	})
+
+	t.Run("dynamodb connection error", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "local")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "local")
+
+		conf := DynamoDBConfig{
+			Region:   "us-east-1",
+			Endpoint: "http://localhost:1",
+			Table:    "events",
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, err := NewDynamoDB(ctx, conf)
+		var connErr *ConnectionError
+		req.ErrorAs(err, &connErr, "expected connection error")
+	})
+}
+
+func TestRetryableCancellation(t *testing.T) {
+	req := require.New(t)
+
+	cancelled := func(codes ...string) *types.TransactionCanceledException {
+		var reasons []types.CancellationReason
+		for _, code := range codes {
+			reasons = append(reasons, types.CancellationReason{Code: aws.String(code)})
+		}
+		return &types.TransactionCanceledException{CancellationReasons: reasons}
+	}
+
+	req.Equal(errDynamoRetry, retryableCancellation(cancelled("ConditionalCheckFailed", "None"), 1), "condition failures should be retried")
+	req.Equal(errDynamoRetry, retryableCancellation(cancelled("TransactionConflict", "None"), 1), "transaction conflicts should be retried")
+	req.Equal(errPositionTaken, retryableCancellation(cancelled("None", "ConditionalCheckFailed"), 1), "conflicts over the counter should be retried as taken positions")
+	req.Equal(errDynamoRetry, retryableCancellation(cancelled("TransactionConflict", "ConditionalCheckFailed"), 1), "conflicts over other items should be retried as conflicts")
+	req.Nil(retryableCancellation(cancelled("None", "ValidationError"), 1), "validation errors should not be retried")
+	req.Nil(retryableCancellation(cancelled("None"), 1), "cancellations without a reason should not be retried")
+}
+
+func TestDynamoDBIntegration(t *testing.T) {
+	// setup
+	req := require.New(t)
+	ctx := context.Background()
+	t.Setenv("AWS_ACCESS_KEY_ID", "local")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "local")
+
+	// container init
+	contReq := testcontainers.ContainerRequest{
+		Image:        "amazon/dynamodb-local:1.21.0",
+		ExposedPorts: []string{"8000:8000"},
+		WaitingFor:   wait.ForListeningPort("8000/tcp"),
+	}
+	dynamoC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: contReq,
+		Started:          true,
+	})
+	req.NoError(err, "failed to create dynamodb container")
+	defer dynamoC.Terminate(ctx)
+
+	// impl setup
+	config := DynamoDBConfig{
+		Region:   "us-east-1",
+		Endpoint: "http://localhost:8000",
+		Table:    "events",
+		Outbox:   true,
+	}
+
+	dynamoImpl, err := NewDynamoDB(ctx, config)
+	req.NoError(err, "failed to create dynamodb event store")
+
+	_, err = NewDynamoDB(ctx, config)
+	req.NoError(err, "creating the table again should succeed")
+
+	// data setup
+	_event := event.New()
+	id := "some_random_id"
+	curTime := time.Now().UTC()
+	_event.SetID(id)
+	_event.SetSubject("test")
+	_event.SetSource("dynamodb_test")
+	_event.SetTime(curTime)
+	_event.SetSpecVersion(event.CloudEventsVersionV1)
+	_event.SetType("test")
+	_event.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": "world"})
+
+	// actual test
+	position, err := dynamoImpl.Append(ctx, &_event, AnyVersion)
+	req.NoError(err, "failed to put event")
+	req.Equal(uint64(1), position, "position not expected value")
+
+	// dynamodb verification
+	out, err := dynamoImpl.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(config.Table),
+		KeyConditionExpression: aws.String("pk = :pk AND sk > :head"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":   &types.AttributeValueMemberS{Value: "stream#test"},
+			":head": dynamoNumber(0),
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	req.NoError(err, "failed to query stream")
+	req.Len(out.Items, 1, "unexpected number of events in stream")
+	rec, err := decodeDynamoStreamRecord(out.Items[0])
+	req.NoError(err, "failed to decode event")
+	req.Equal(position, rec.Position, "position not expected value")
+	req.Equal(uint64(1), rec.Version, "version not expected value")
+
+	// iterate
+	iter, err := dynamoImpl.Iterate(ctx, Filter{})
+	req.NoError(err, "failed to iterate events")
+	defer iter.Close(ctx)
+
+	rec, err = iter.Next(ctx)
+	req.NoError(err, "failed to read event")
+	req.Equal(position, rec.Position, "position not expected value")
+
+	ev := rec.Event
+	req.Equal(id, ev.ID(), "id not expected value")
+	req.Equal("dynamodb_test", ev.Source(), "source not expected value")
+	req.Equal("test", ev.Type(), "type not expected value")
+	req.Equal("test", ev.Subject(), "subject not expected value")
+	req.Equal(curTime, ev.Time(), "time is not equal")
+	req.JSONEq(`{"hello":"world"}`, string(ev.Data()), "data not expected value")
+
+	_, err = iter.Next(ctx)
+	req.ErrorIs(err, io.EOF, "expected no more events")
+
+	// get
+	got, err := dynamoImpl.Get(ctx, "dynamodb_test", id)
+	req.NoError(err, "failed to get event")
+	req.Equal(position, got.Position, "position not expected value")
+	req.Equal(curTime, got.Event.Time(), "time is not equal")
+	req.JSONEq(`{"hello":"world"}`, string(got.Event.Data()), "data not expected value")
+
+	_, err = dynamoImpl.Get(ctx, "dynamodb_test", "missing_id")
+	var notFoundErr *EventNotFoundError
+	req.ErrorAs(err, &notFoundErr, "expected event not found error")
+
+	// outbox
+	pending, err := dynamoImpl.Pending(ctx, 10)
+	req.NoError(err, "failed to get pending events")
+	req.Len(pending, 1, "unexpected number of pending events")
+	req.Equal(position, pending[0].Position, "position not expected value")
+
+	req.NoError(dynamoImpl.Dispatched(ctx, pending...), "failed to mark events as dispatched")
+	pending, err = dynamoImpl.Pending(ctx, 10)
+	req.NoError(err, "failed to get pending events")
+	req.Empty(pending, "every event should have been dispatched")
+
+	// checkpoints
+	checkpoint, err := dynamoImpl.Checkpoint(ctx, "projector")
+	req.NoError(err, "failed to get checkpoint")
+	req.Zero(checkpoint, "a group which has not committed should be at position 0")
+
+	req.NoError(dynamoImpl.Commit(ctx, "projector", 5), "failed to commit checkpoint")
+	req.NoError(dynamoImpl.Commit(ctx, "projector", 3), "failed to commit earlier checkpoint")
+	checkpoint, err = dynamoImpl.Checkpoint(ctx, "projector")
+	req.NoError(err, "failed to get checkpoint")
+	req.Equal(uint64(5), checkpoint, "checkpoint should not have moved back")
+
+	// idempotency
+	samePosition, err := dynamoImpl.Append(ctx, &_event, AnyVersion)
+	req.NoError(err, "re-appending the same event should succeed")
+	req.Equal(position, samePosition, "position not expected value")
+
+	conflicting := _event.Clone()
+	conflicting.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": "there"})
+	_, err = dynamoImpl.Append(ctx, &conflicting, AnyVersion)
+	var dupErr *DuplicateEventError
+	req.ErrorAs(err, &dupErr, "expected duplicate event error")
+
+	// stream versions
+	_event.SetID("stale_random_id")
+	_, err = dynamoImpl.Append(ctx, &_event, 0)
+	var conflictErr *VersionConflictError
+	req.ErrorAs(err, &conflictErr, "expected version conflict error")
+	req.Equal("test", conflictErr.Stream, "stream not expected value")
+	req.Equal(uint64(1), conflictErr.Actual, "actual version not expected value")
+
+	nextPosition, err := dynamoImpl.Append(ctx, &_event, 1)
+	req.NoError(err, "failed to put event at expected version")
+	req.Greater(nextPosition, position, "position not expected value")
+
+	// batches
+	newEvent := func(id string) *event.Event {
+		ev := event.New()
+		ev.SetID(id)
+		ev.SetSubject("batch")
+		ev.SetSource("dynamodb_test")
+		ev.SetSpecVersion(event.CloudEventsVersionV1)
+		ev.SetType("test")
+		return &ev
+	}
+
+	positions, err := dynamoImpl.AppendBatch(ctx, []*event.Event{newEvent("1"), newEvent("2")}, 0)
+	req.NoError(err, "failed to put batch")
+	req.Len(positions, 2, "unexpected number of positions")
+
+	samePositions, err := dynamoImpl.AppendBatch(ctx, []*event.Event{newEvent("1"), newEvent("2")}, 0)
+	req.NoError(err, "re-appending the same batch should succeed")
+	req.Equal(positions, samePositions, "positions not expected value")
+
+	_, err = dynamoImpl.AppendBatch(ctx, []*event.Event{newEvent("3"), newEvent("4")}, 0)
+	req.ErrorAs(err, &conflictErr, "expected version conflict error")
+
+	resumed, err := dynamoImpl.Iterate(ctx, Filter{AfterPosition: nextPosition, Types: []string{"test"}})
+	req.NoError(err, "failed to iterate events after position")
+	defer resumed.Close(ctx)
+
+	var ids []string
+	for {
+		rec, err := resumed.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		req.NoError(err, "failed to read event")
+		ids = append(ids, rec.Event.ID())
+	}
+	req.Equal([]string{"1", "2"}, ids, "failed batch should not have been stored")
+
+	// read stream
+	appendStreamTestEvents(t, dynamoImpl)
+	testReadStream(t, dynamoImpl)
+
+	// concurrent appends
+	testSubscribeConcurrentAppends(t, dynamoImpl)
+	testIterateConcurrentAppends(t, dynamoImpl)
+}
@@ -82,6 +82,181 @@ func (p *PutError) Unwrap() error {
 	return p.Err
 }
 
+// VersionConflictError defines an error when appending to a stream which is not at the expected version
+type VersionConflictError struct {
+	Stream   string
+	Expected uint64
+	Actual   uint64
+}
+
+// NewVersionConflictError creates a new VersionConflictError
+func NewVersionConflictError(stream string, expected, actual uint64) *VersionConflictError {
+	return &VersionConflictError{
+		Stream:   stream,
+		Expected: expected,
+		Actual:   actual,
+	}
+}
+
+// Error returns a string form of the error and implements the error interface
+func (v *VersionConflictError) Error() string {
+	return fmt.Sprintf("expected stream %q to be at version %d but it is at version %d", v.Stream, v.Expected, v.Actual)
+}
+
+// DuplicateEventError defines an error when appending an event whose id and source
+// are already used by a different event
+type DuplicateEventError struct {
+	Source string
+	ID     string
+}
+
+// NewDuplicateEventError creates a new DuplicateEventError
+func NewDuplicateEventError(source, id string) *DuplicateEventError {
+	return &DuplicateEventError{
+		Source: source,
+		ID:     id,
+	}
+}
+
+// Error returns a string form of the error and implements the error interface
+func (d *DuplicateEventError) Error() string {
+	return fmt.Sprintf("a different event with id %q from source %q already exists", d.ID, d.Source)
+}
+
+// EventNotFoundError defines an error when getting an event which does not exist
+type EventNotFoundError struct {
+	Source string
+	ID     string
+}
+
+// NewEventNotFoundError creates a new EventNotFoundError
+func NewEventNotFoundError(source, id string) *EventNotFoundError {
+	return &EventNotFoundError{
+		Source: source,
+		ID:     id,
+	}
+}
+
+// Error returns a string form of the error and implements the error interface
+func (e *EventNotFoundError) Error() string {
+	return fmt.Sprintf("event with id %q from source %q does not exist", e.ID, e.Source)
+}
+
+// GetError defines an error when getting data from a database
+type GetError struct {
+	Source        string
+	RetrievedType string
+	Err           error
+}
+
+// NewGetError creates a new GetError
+func NewGetError(source, retrievedType string, err error) *GetError {
+	return &GetError{
+		Source:        source,
+		RetrievedType: retrievedType,
+		Err:           err,
+	}
+}
+
+// Error returns a string form of the error and implements the error interface
+func (g *GetError) Error() string {
+	return fmt.Sprintf("failed to get %s from %s. %s", g.RetrievedType, g.Source, g.Err)
+}
+
+// Unwrap returns the inner error, making it compatible with errors.Unwrap
+func (g *GetError) Unwrap() error {
+	return g.Err
+}
+
+// RetryLimitError defines an error when an append kept conflicting with concurrent appends
+// and was given up on after being attempted as many times as it is allowed to be
+type RetryLimitError struct {
+	Source   string
+	Attempts int
+	Err      error
+}
+
+// NewRetryLimitError creates a new RetryLimitError
+func NewRetryLimitError(source string, attempts int, err error) *RetryLimitError {
+	return &RetryLimitError{
+		Source:   source,
+		Attempts: attempts,
+		Err:      err,
+	}
+}
+
+// Error returns a string form of the error and implements the error interface
+func (r *RetryLimitError) Error() string {
+	return fmt.Sprintf("gave up appending to %s after %d conflicting attempts. %s", r.Source, r.Attempts, r.Err)
+}
+
+// Unwrap returns the inner error, making it compatible with errors.Unwrap
+func (r *RetryLimitError) Unwrap() error {
+	return r.Err
+}
+
+// UnbatchableError defines an error when a batch of events can not be appended to an event store at once,
+// such as when it holds more events than fit in a single transaction. None of the events are appended, but
+// appending them one at a time or in smaller batches is not affected.
+type UnbatchableError struct {
+	Source string
+	Reason string
+}
+
+// NewUnbatchableError creates a new UnbatchableError
+func NewUnbatchableError(source, reason string) *UnbatchableError {
+	return &UnbatchableError{
+		Source: source,
+		Reason: reason,
+	}
+}
+
+// Error returns a string form of the error and implements the error interface
+func (u *UnbatchableError) Error() string {
+	return fmt.Sprintf("batch can not be appended to %s at once since %s", u.Source, u.Reason)
+}
+
+// CorruptSegmentError defines an error when a segment of the file event store can not be read back
+type CorruptSegmentError struct {
+	Segment string
+	Err     error
+}
+
+// NewCorruptSegmentError creates a new CorruptSegmentError
+func NewCorruptSegmentError(segment string, err error) *CorruptSegmentError {
+	return &CorruptSegmentError{
+		Segment: segment,
+		Err:     err,
+	}
+}
+
+// Error returns a string form of the error and implements the error interface
+func (c *CorruptSegmentError) Error() string {
+	return fmt.Sprintf("segment %s is corrupt. %s", c.Segment, c.Err)
+}
+
+// Unwrap returns the inner error, making it compatible with errors.Unwrap
+func (c *CorruptSegmentError) Unwrap() error {
+	return c.Err
+}
+
+// OutboxDisabledError defines an error when reading the outbox of an event store which is not configured to record pending events
+type OutboxDisabledError struct {
+	Source string
+}
+
+// NewOutboxDisabledError creates a new OutboxDisabledError
+func NewOutboxDisabledError(source string) *OutboxDisabledError {
+	return &OutboxDisabledError{
+		Source: source,
+	}
+}
+
+// Error returns a string form of the error and implements the error interface
+func (o *OutboxDisabledError) Error() string {
+	return fmt.Sprintf("outbox of %s is not enabled", o.Source)
+}
+
 // InvalidValidationError Alias for validator package validator.InvalidValidationError
 var InvalidValidationError = validator.InvalidValidationError{}
 
@@ -0,0 +1,947 @@
+package eventstore
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// Sync policies for the file event store
+const (
+	// FileSyncAlways flushes every append to disk before it is acknowledged
+	FileSyncAlways = "always"
+	// FileSyncInterval flushes appends to disk periodically, so a crash can lose
+	// the appends acknowledged since the last flush
+	FileSyncInterval = "interval"
+	// FileSyncNever leaves flushing appends to disk to the operating system
+	FileSyncNever = "never"
+)
+
+// DefaultFileSegmentSize is the size in bytes a segment grows to before a new one is started
+const DefaultFileSegmentSize = 64 << 20
+
+// FileConfig defines the configuration of the file event store
+type FileConfig struct {
+	// Dir is the directory the segments of the log are kept in. It is created if it does not exist.
+	Dir string `mapstructure:"dir" validate:"required"`
+
+	// SegmentSize is the size in bytes a segment grows to before a new one is started.
+	// DefaultFileSegmentSize is used if it is not set.
+	SegmentSize int64 `mapstructure:"segment_size" validate:"gte=0"`
+
+	// Sync is the policy for flushing appends to disk, which is one of
+	// "always", "interval" or "never". It defaults to "always".
+	Sync string `mapstructure:"sync" validate:"omitempty,oneof=always interval never"`
+	// SyncInterval is how often appends are flushed to disk with the "interval" policy. It defaults to a second.
+	SyncInterval time.Duration `mapstructure:"sync_interval" validate:"gte=0"`
+
+	// StreamExtension is the extension attribute used to group events into streams.
+	// Events are grouped by their subject if it is not set.
+	StreamExtension string `mapstructure:"stream_extension" validate:"omitempty,alphanum,lowercase"`
+
+	// Outbox records every appended event as pending dispatch until it is marked as dispatched.
+	// Since events are written to the log in position order, this is tracked by the position
+	// up to which every event has been dispatched, which is kept in a file alongside the segments.
+	Outbox bool `mapstructure:"outbox"`
+}
+
+// Validate ensures file config is correct
+func (f *FileConfig) Validate() error {
+	return validator.New().Struct(f)
+}
+
+// File is an embedded event store implementation which keeps events in an append-only,
+// segmented log on local disk. It is safe for concurrent use, but only by a single process.
+//
+// On open, any torn or incomplete batch of records at the end of the log, as left
+// by a crash part way through an append, is truncated away.
+type File struct {
+	config FileConfig
+	logger *zap.Logger
+
+	mu       sync.RWMutex
+	closed   bool
+	dirty    bool
+	segments []fileSegment
+	log      *os.File
+	index    *os.File
+	size     int64
+	last     uint64
+	ids      map[eventKey]uint64
+	streams  map[string][]uint64
+
+	// dispatched is the position up to which every event has been dispatched, and
+	// dispatchedAfter holds the positions after it which have been dispatched out of order
+	dispatched      uint64
+	dispatchedAfter map[uint64]bool
+
+	appended appendSignal
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewFile constructs a *File, recovering any log already in the configured directory
+func NewFile(config FileConfig) (*File, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config, %w", err)
+	}
+	if config.SegmentSize == 0 {
+		config.SegmentSize = DefaultFileSegmentSize
+	}
+	if config.Sync == "" {
+		config.Sync = FileSyncAlways
+	}
+	if config.SyncInterval == 0 {
+		config.SyncInterval = time.Second
+	}
+
+	impl := &File{
+		config:          config,
+		logger:          zap.L().With(zap.String("source", "FileEventStoreImpl")),
+		ids:             make(map[eventKey]uint64),
+		streams:         make(map[string][]uint64),
+		dispatchedAfter: make(map[uint64]bool),
+		done:            make(chan struct{}),
+	}
+
+	err = impl.init()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init, %w", err)
+	}
+
+	if config.Sync == FileSyncInterval {
+		impl.wg.Add(1)
+		go impl.syncPeriodically()
+	}
+	return impl, nil
+}
+
+func (f *File) init() error {
+	err := os.MkdirAll(filepath.Join(f.config.Dir, fileCheckpointsDir), 0o755)
+	if err != nil {
+		return err
+	}
+
+	segments, err := listFileSegments(f.config.Dir)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		segments = []fileSegment{{base: 1, dir: f.config.Dir}}
+	}
+
+	var end int64
+	for i, seg := range segments {
+		end, err = f.recover(seg, i == len(segments)-1)
+		if err != nil {
+			return err
+		}
+	}
+
+	active := segments[len(segments)-1]
+	f.log, f.index, err = openFileSegment(active)
+	if err != nil {
+		return err
+	}
+	f.segments = segments
+	f.size = end
+
+	if f.config.Outbox {
+		err = f.recoverOutbox()
+		if err != nil {
+			return err
+		}
+	}
+
+	f.logger.Info("opened log",
+		zap.String("dir", f.config.Dir),
+		zap.Int("segments", len(segments)),
+		zap.Uint64("last_position", f.last),
+	)
+	return syncDir(f.config.Dir)
+}
+
+// recover reads every record in the segment into the id and stream indexes and rebuilds
+// the offset index of the segment if it is out of date. A torn write is truncated from the
+// active segment, but is only expected at the end of the log so is an error in any other.
+func (f *File) recover(seg fileSegment, active bool) (int64, error) {
+	if seg.base != f.last+1 {
+		return 0, NewCorruptSegmentError(seg.name(), fmt.Errorf("expected first position %d", f.last+1))
+	}
+
+	var offsets []int64
+	end, torn, err := scanFileSegment(seg, func(rec *fileRecord) error {
+		if rec.position != f.last+1 {
+			return fmt.Errorf("expected position %d at offset %d but found %d", f.last+1, rec.offset, rec.position)
+		}
+
+		ev := event.New()
+		err := ev.UnmarshalJSON(rec.data)
+		if err != nil {
+			return fmt.Errorf("unable to decode event at position %d. %w", rec.position, err)
+		}
+
+		f.ids[eventKey{source: ev.Source(), id: ev.ID()}] = rec.position
+		if stream := StreamOf(&ev, f.config.StreamExtension); stream != "" {
+			f.streams[stream] = append(f.streams[stream], rec.position)
+		}
+		offsets = append(offsets, rec.offset)
+		f.last = rec.position
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) && active {
+		end, torn, err = 0, false, nil
+	}
+	if err != nil {
+		return 0, NewCorruptSegmentError(seg.name(), err)
+	}
+
+	if torn {
+		if !active {
+			return 0, NewCorruptSegmentError(seg.name(), errTornRecord)
+		}
+		f.logger.Warn("truncating torn write from end of log",
+			zap.String("segment", seg.name()),
+			zap.Int64("offset", end),
+		)
+		err = os.Truncate(seg.logPath(), end)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	info, err := os.Stat(seg.indexPath())
+	if err == nil && info.Size() == int64(len(offsets))*fileIndexEntrySize {
+		return end, nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	f.logger.Info("rebuilding segment index", zap.String("segment", seg.name()))
+	return end, writeFileIndex(seg, offsets)
+}
+
+// recoverOutbox reads the position up to which every event has been dispatched. If the outbox
+// has just been enabled, only events appended from now on are pending dispatch.
+func (f *File) recoverOutbox() error {
+	path := filepath.Join(f.config.Dir, fileOutboxName)
+	dispatched, err := readFileCheckpoint(path)
+	if errors.Is(err, os.ErrNotExist) {
+		f.dispatched = f.last
+		return writeFileCheckpoint(path, f.dispatched)
+	}
+	if err != nil {
+		return err
+	}
+
+	// events of a torn write were never acknowledged, so can not have been dispatched
+	if dispatched > f.last {
+		dispatched = f.last
+	}
+	f.dispatched = dispatched
+	return nil
+}
+
+func openFileSegment(seg fileSegment) (log *os.File, index *os.File, err error) {
+	log, err = os.OpenFile(seg.logPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	index, err = os.OpenFile(seg.indexPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		log.Close()
+		return nil, nil, err
+	}
+	return log, index, nil
+}
+
+func (f *File) syncPeriodically() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.config.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.done:
+			return
+		case <-ticker.C:
+		}
+
+		f.mu.Lock()
+		if f.dirty {
+			err := f.sync()
+			if err != nil {
+				f.logger.Error("failed to sync log", zap.Error(err))
+			}
+		}
+		f.mu.Unlock()
+	}
+}
+
+// sync flushes the active segment to disk. The caller must hold the lock.
+func (f *File) sync() error {
+	err := f.log.Sync()
+	if err != nil {
+		return err
+	}
+	err = f.index.Sync()
+	if err != nil {
+		return err
+	}
+	f.dirty = false
+	return nil
+}
+
+// Close flushes the log to disk and closes it. The store can not be used once closed.
+func (f *File) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+	f.mu.Unlock()
+
+	// wake any subscriptions so they fail now that the store is closed
+	f.appended.broadcast()
+	close(f.done)
+	f.wg.Wait()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err := f.sync()
+	if closeErr := f.log.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := f.index.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Append writes an event to the end of the log, returning its position, and implements the interface AppendOnly.
+func (f *File) Append(ctx context.Context, event *event.Event, expectedVersion uint64) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, NewPutError("file", "event", os.ErrClosed)
+	}
+
+	position, ok, err := f.existing(event)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return position, nil
+	}
+
+	stream := StreamOf(event, f.config.StreamExtension)
+	if version := uint64(len(f.streams[stream])); expectedVersion != AnyVersion && version != expectedVersion {
+		f.logger.Warn("stream is not at expected version",
+			zap.String("stream", stream),
+			zap.Uint64("expected_version", expectedVersion),
+			zap.Uint64("actual_version", version),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return 0, NewVersionConflictError(stream, expectedVersion, version)
+	}
+
+	positions, err := f.write(event)
+	if err != nil {
+		return 0, err
+	}
+	return positions[0], nil
+}
+
+// AppendBatch writes events to the end of the log such that either all or none of them are
+// recovered after a crash and implements the interface BatchAppendOnly.
+func (f *File) AppendBatch(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return nil, NewPutError("file", "event", os.ErrClosed)
+	}
+
+	positions := make([]uint64, len(events))
+	pending := make([]*event.Event, 0, len(events))
+	batched := make(map[eventKey]int, len(events))
+	for i, ev := range events {
+		position, ok, err := f.existing(ev)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			positions[i] = position
+			continue
+		}
+
+		key := eventKey{source: ev.Source(), id: ev.ID()}
+		if j, ok := batched[key]; ok {
+			if !sameEvent(events[j], ev) {
+				return nil, NewDuplicateEventError(ev.Source(), ev.ID())
+			}
+			positions[i] = positions[j]
+			continue
+		}
+		batched[key] = i
+		positions[i] = f.last + uint64(len(pending)) + 1
+		pending = append(pending, ev)
+	}
+	if len(pending) == 0 {
+		return positions, nil
+	}
+
+	if expectedVersion != AnyVersion {
+		for _, ev := range events {
+			stream := StreamOf(ev, f.config.StreamExtension)
+			if version := uint64(len(f.streams[stream])); version != expectedVersion {
+				return nil, NewVersionConflictError(stream, expectedVersion, version)
+			}
+		}
+	}
+
+	_, err := f.write(pending...)
+	if err != nil {
+		return nil, err
+	}
+	f.logger.Info("successfully inserted batch", zap.Int("events", len(events)))
+	return positions, nil
+}
+
+// existing returns the position of an identical event which has already been appended, or
+// a *DuplicateEventError if a different event with the same id and source has been appended.
+// The caller must hold the lock.
+func (f *File) existing(event *event.Event) (uint64, bool, error) {
+	position, ok := f.ids[eventKey{source: event.Source(), id: event.ID()}]
+	if !ok {
+		return 0, false, nil
+	}
+
+	rec, err := f.read(position)
+	if err != nil {
+		return 0, false, NewGetError("file", "event", err)
+	}
+	if !sameEvent(rec.Event, event) {
+		f.logger.Warn("event already exists with different content",
+			zap.Uint64("position", position),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return 0, false, NewDuplicateEventError(event.Source(), event.ID())
+	}
+	f.logger.Info("event has already been inserted",
+		zap.Uint64("position", position),
+		zap.String("event_id", event.ID()),
+		zap.String("event_type", event.Type()),
+		zap.String("event_source", event.Source()),
+		zap.String("event_subject", event.Subject()),
+	)
+	return position, true, nil
+}
+
+// write appends the events to the active segment as a single batch, starting a new
+// segment first if the batch would not fit. The caller must hold the lock.
+func (f *File) write(events ...*event.Event) ([]uint64, error) {
+	data := make([][]byte, len(events))
+	var size int64
+	for i, ev := range events {
+		b, err := ev.MarshalJSON()
+		if err != nil {
+			return nil, NewMarshalError("cloudevent", "json", err)
+		}
+		data[i] = b
+		size += int64(fileRecordHeaderSize + fileRecordMetaSize + len(b))
+	}
+
+	if f.size > 0 && f.size+size > f.config.SegmentSize {
+		err := f.roll()
+		if err != nil {
+			return nil, NewPutError("file", "event", err)
+		}
+	}
+
+	positions := make([]uint64, len(events))
+	buf := make([]byte, 0, size)
+	index := make([]byte, 0, len(events)*fileIndexEntrySize)
+	for i, b := range data {
+		positions[i] = f.last + uint64(i) + 1
+		index = appendFileIndexEntry(index, f.size+int64(len(buf)))
+		buf = encodeFileRecord(buf, positions[i], uint32(len(data)-i-1), b)
+	}
+
+	err := f.writeSegment(buf, index)
+	if err != nil {
+		f.logger.Error("failed to write to log", zap.Error(err))
+		return nil, NewPutError("file", "event", err)
+	}
+
+	for i, ev := range events {
+		f.ids[eventKey{source: ev.Source(), id: ev.ID()}] = positions[i]
+		if stream := StreamOf(ev, f.config.StreamExtension); stream != "" {
+			f.streams[stream] = append(f.streams[stream], positions[i])
+		}
+
+		f.logger.Info("successfully inserted event",
+			zap.Uint64("position", positions[i]),
+			zap.String("event_id", ev.ID()),
+			zap.String("event_type", ev.Type()),
+			zap.String("event_source", ev.Source()),
+			zap.String("event_subject", ev.Subject()),
+		)
+	}
+	f.last += uint64(len(events))
+	f.size += int64(len(buf))
+	f.appended.broadcast()
+	return positions, nil
+}
+
+// writeSegment writes the records and their index entries to the active segment, flushing them
+// to disk if required by the sync policy. The segment is truncated back if any step fails so
+// the records are never acknowledged without being written. The caller must hold the lock.
+func (f *File) writeSegment(records, index []byte) (err error) {
+	defer func() {
+		if err == nil {
+			return
+		}
+		f.log.Truncate(f.size)
+		f.index.Truncate(int64(f.last+1-f.segments[len(f.segments)-1].base) * fileIndexEntrySize)
+	}()
+
+	_, err = f.log.Write(records)
+	if err != nil {
+		return err
+	}
+	_, err = f.index.Write(index)
+	if err != nil {
+		return err
+	}
+
+	f.dirty = true
+	if f.config.Sync != FileSyncAlways {
+		return nil
+	}
+	return f.sync()
+}
+
+// roll flushes and closes the active segment and starts a new one after it. The caller must hold the lock.
+func (f *File) roll() error {
+	err := f.sync()
+	if err != nil {
+		return err
+	}
+	err = f.log.Close()
+	if err != nil {
+		return err
+	}
+	err = f.index.Close()
+	if err != nil {
+		return err
+	}
+
+	seg := fileSegment{base: f.last + 1, dir: f.config.Dir}
+	f.log, f.index, err = openFileSegment(seg)
+	if err != nil {
+		return err
+	}
+	f.segments = append(f.segments, seg)
+	f.size = 0
+
+	f.logger.Info("started new segment", zap.String("segment", seg.name()))
+	return syncDir(f.config.Dir)
+}
+
+// read returns the record at the position. The caller must hold the lock.
+func (f *File) read(position uint64) (*Record, error) {
+	seg := f.segments[findFileSegment(f.segments, position)]
+	offset, err := readFileIndexEntry(seg, position)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(seg.logPath())
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	rec, _, err := decodeFileRecord(seg, bufio.NewReader(file), offset, position)
+	return rec, err
+}
+
+// findFileSegment returns the index of the segment containing position
+func findFileSegment(segments []fileSegment, position uint64) int {
+	i := sort.Search(len(segments), func(i int) bool {
+		return segments[i].base > position
+	})
+	if i > 0 {
+		i--
+	}
+	return i
+}
+
+// decodeFileRecord reads the record at offset, checking it is for the expected position,
+// and returns it along with its size in the segment
+func decodeFileRecord(seg fileSegment, r *bufio.Reader, offset int64, position uint64) (*Record, int64, error) {
+	rec, err := readFileRecord(r, offset)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, 0, NewCorruptSegmentError(seg.name(), err)
+	}
+	if rec.position != position {
+		return nil, 0, NewCorruptSegmentError(seg.name(), fmt.Errorf("expected position %d at offset %d but found %d", position, offset, rec.position))
+	}
+
+	ev := event.New()
+	err = ev.UnmarshalJSON(rec.data)
+	if err != nil {
+		return nil, 0, NewMarshalError("json", "cloudevent", err)
+	}
+	return &Record{
+		Position: rec.position,
+		Event:    &ev,
+	}, rec.size, nil
+}
+
+// Get returns the event with the given source and id, which is read from the log at the position held
+// by the in-memory index of ids, and implements the interface Gettable
+func (f *File) Get(ctx context.Context, source, id string) (*Record, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	position, ok := f.ids[eventKey{source: source, id: id}]
+	if !ok {
+		return nil, NewEventNotFoundError(source, id)
+	}
+
+	rec, err := f.read(position)
+	if err != nil {
+		f.logger.Error("failed to read event", zap.Error(err), zap.Uint64("position", position))
+		return nil, NewGetError("file", "event", err)
+	}
+	return rec, nil
+}
+
+// ReadStream returns an Iterator over the events of the stream in the order of their versions and implements the
+// interface StreamReader. The selected events are read from the log up front, using the positions of each stream
+// which are indexed in memory.
+func (f *File) ReadStream(ctx context.Context, stream string, opts ReadStreamOptions) (Iterator, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.closed {
+		return nil, NewGetError("file", "events", os.ErrClosed)
+	}
+
+	positions := f.streams[stream]
+	first, last := opts.span(uint64(len(positions)))
+	var records []*Record
+	for version := first; version <= last; version++ {
+		rec, err := f.read(positions[version-1])
+		if err != nil {
+			f.logger.Error("failed to read event", zap.Error(err), zap.Uint64("position", positions[version-1]))
+			return nil, NewGetError("file", "event", err)
+		}
+		rec.Version = version
+		records = append(records, rec)
+	}
+	if opts.Backward {
+		reverseRecords(records)
+	}
+	return &recordsIterator{records: records}, nil
+}
+
+// Pending returns up to limit events which are pending dispatch, in the order they were appended,
+// and implements the interface Outbox
+func (f *File) Pending(ctx context.Context, limit int) ([]*Record, error) {
+	if !f.config.Outbox {
+		return nil, NewOutboxDisabledError("file")
+	}
+
+	f.mu.RLock()
+	dispatched := f.dispatched
+	skip := make(map[uint64]bool, len(f.dispatchedAfter))
+	for position := range f.dispatchedAfter {
+		skip[position] = true
+	}
+	f.mu.RUnlock()
+
+	iter, err := f.Iterate(ctx, Filter{AfterPosition: dispatched})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close(ctx)
+
+	var records []*Record
+	for len(records) < limit {
+		rec, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if skip[rec.Position] {
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// Dispatched marks the events as no longer pending dispatch and implements the interface Outbox.
+// The position up to which every event has been dispatched is written to disk whenever it moves on,
+// so events dispatched out of order may be pending dispatch again after the store is reopened.
+func (f *File) Dispatched(ctx context.Context, records ...*Record) error {
+	if !f.config.Outbox {
+		return NewOutboxDisabledError("file")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return NewPutError("file", "outbox", os.ErrClosed)
+	}
+
+	for _, rec := range records {
+		if rec.Position > f.dispatched && rec.Position <= f.last {
+			f.dispatchedAfter[rec.Position] = true
+		}
+	}
+
+	dispatched := f.dispatched
+	for f.dispatchedAfter[dispatched+1] {
+		dispatched++
+	}
+	if dispatched == f.dispatched {
+		return nil
+	}
+
+	err := writeFileCheckpoint(filepath.Join(f.config.Dir, fileOutboxName), dispatched)
+	if err != nil {
+		f.logger.Error("failed to write outbox", zap.Error(err), zap.Uint64("position", dispatched))
+		return NewPutError("file", "outbox", err)
+	}
+	for position := f.dispatched + 1; position <= dispatched; position++ {
+		delete(f.dispatchedAfter, position)
+	}
+	f.dispatched = dispatched
+	return nil
+}
+
+// checkpointPath returns the path of the file holding the checkpoint of a consumer group. Group
+// names are hashed, since they may contain characters which are not allowed in file names.
+func (f *File) checkpointPath(group string) string {
+	return filepath.Join(f.config.Dir, fileCheckpointsDir, HashKey(group))
+}
+
+// Checkpoint returns the position a consumer group has committed and implements the interface Checkpointer
+func (f *File) Checkpoint(ctx context.Context, group string) (uint64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.closed {
+		return 0, NewGetError("file", "checkpoint", os.ErrClosed)
+	}
+
+	position, err := readFileCheckpoint(f.checkpointPath(group))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		f.logger.Error("failed to read checkpoint", zap.Error(err), zap.String("group", group))
+		return 0, NewGetError("file", "checkpoint", err)
+	}
+	return position, nil
+}
+
+// Commit moves the checkpoint of a consumer group forward to the position, replacing the file holding it,
+// and implements the interface Checkpointer
+func (f *File) Commit(ctx context.Context, group string, position uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return NewPutError("file", "checkpoint", os.ErrClosed)
+	}
+
+	path := f.checkpointPath(group)
+	current, err := readFileCheckpoint(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		f.logger.Error("failed to read checkpoint", zap.Error(err), zap.String("group", group))
+		return NewPutError("file", "checkpoint", err)
+	}
+	if position <= current {
+		return nil
+	}
+
+	err = writeFileCheckpoint(path, position)
+	if err != nil {
+		f.logger.Error("failed to write checkpoint", zap.Error(err), zap.String("group", group), zap.Uint64("position", position))
+		return NewPutError("file", "checkpoint", err)
+	}
+	return nil
+}
+
+// Iterate returns an Iterator over the events in the log which match the filter in the order they were appended
+// and implements the interface Iterable. Events appended after Iterate is called are not returned.
+func (f *File) Iterate(ctx context.Context, filter Filter) (Iterator, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.closed {
+		return nil, NewGetError("file", "events", os.ErrClosed)
+	}
+
+	next := filter.AfterPosition + 1
+	if filter.AfterPosition >= f.last {
+		next = f.last + 1
+	}
+
+	// segments are never modified once appended to the slice, so a
+	// snapshot of it can be read without holding the lock
+	return &fileIterator{
+		filter:   filter,
+		segments: f.segments,
+		next:     next,
+		last:     f.last,
+	}, nil
+}
+
+// Subscribe returns an Iterator over the events in the log which match the filter, which waits for matching events
+// to be appended once it has returned every stored event, and implements the interface Subscribable
+func (f *File) Subscribe(ctx context.Context, filter Filter) (Iterator, error) {
+	return newSubscription(f, filter, &f.appended, nil), nil
+}
+
+type fileIterator struct {
+	filter   Filter
+	segments []fileSegment
+	next     uint64
+	last     uint64
+
+	segment int
+	offset  int64
+	file    *os.File
+	reader  *bufio.Reader
+}
+
+// Next returns the next matching event and implements the interface Iterator
+func (it *fileIterator) Next(ctx context.Context) (*Record, error) {
+	for it.next <= it.last {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if it.reader == nil {
+			err := it.open()
+			if err != nil {
+				return nil, NewGetError("file", "event", err)
+			}
+		}
+
+		seg := it.segments[it.segment]
+		_, err := it.reader.Peek(1)
+		if err == io.EOF {
+			err = it.openNext()
+			if err != nil {
+				return nil, NewGetError("file", "event", err)
+			}
+			continue
+		}
+
+		rec, size, err := decodeFileRecord(seg, it.reader, it.offset, it.next)
+		if err != nil {
+			return nil, NewGetError("file", "event", err)
+		}
+		it.offset += size
+		it.next++
+
+		if !it.filter.match(rec) {
+			continue
+		}
+		return rec, nil
+	}
+	return nil, io.EOF
+}
+
+// open opens the segment containing the next position and seeks to it
+func (it *fileIterator) open() error {
+	it.segment = findFileSegment(it.segments, it.next)
+	seg := it.segments[it.segment]
+	offset, err := readFileIndexEntry(seg, it.next)
+	if err != nil {
+		return err
+	}
+	return it.openAt(seg, offset)
+}
+
+// openNext moves on to the segment following the current one, which must start at the next position
+func (it *fileIterator) openNext() error {
+	seg := it.segments[it.segment]
+	if it.segment+1 >= len(it.segments) || it.segments[it.segment+1].base != it.next {
+		return NewCorruptSegmentError(seg.name(), fmt.Errorf("expected position %d before end of segment", it.next))
+	}
+	it.segment++
+	return it.openAt(it.segments[it.segment], 0)
+}
+
+func (it *fileIterator) openAt(seg fileSegment, offset int64) error {
+	it.closeFile()
+
+	file, err := os.Open(seg.logPath())
+	if err != nil {
+		return err
+	}
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	it.file = file
+	it.reader = bufio.NewReader(file)
+	it.offset = offset
+	return nil
+}
+
+func (it *fileIterator) closeFile() error {
+	if it.file == nil {
+		return nil
+	}
+	err := it.file.Close()
+	it.file = nil
+	it.reader = nil
+	return err
+}
+
+// Close implements the interface Iterator
+func (it *fileIterator) Close(ctx context.Context) error {
+	it.next = it.last + 1
+	return it.closeFile()
+}
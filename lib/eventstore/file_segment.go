@@ -0,0 +1,281 @@
+package eventstore
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Each segment of the log is a pair of files named after the position of the first
+// record in the segment. The log file holds the records and the index file holds the
+// offset of every record in the log file, in position order.
+//
+// A record is laid out as:
+//
+//	| length (4) | crc32c of payload (4) | position (8) | remaining (4) | event json |
+//	              \-------------------------- payload -----------------------------/
+//
+// where remaining is the number of records following it which were appended in the same
+// batch. A batch is only complete once a record with remaining set to 0 is read.
+const (
+	fileLogExt   = ".log"
+	fileIndexExt = ".index"
+
+	// fileOutboxName is the file holding the position up to which every event has been dispatched
+	fileOutboxName = "outbox"
+
+	// fileCheckpointsDir is the directory holding a file with the checkpoint of each consumer group
+	fileCheckpointsDir = "checkpoints"
+
+	fileRecordHeaderSize = 8
+	fileRecordMetaSize   = 12
+	fileIndexEntrySize   = 8
+
+	// fileMaxRecordSize guards against allocating huge buffers when reading a corrupt length
+	fileMaxRecordSize = 64 << 20
+)
+
+var (
+	crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+	errTornRecord = errors.New("torn record")
+)
+
+type fileSegment struct {
+	base uint64
+	dir  string
+}
+
+func (s fileSegment) name() string {
+	return fmt.Sprintf("%020d", s.base)
+}
+
+func (s fileSegment) logPath() string {
+	return filepath.Join(s.dir, s.name()+fileLogExt)
+}
+
+func (s fileSegment) indexPath() string {
+	return filepath.Join(s.dir, s.name()+fileIndexExt)
+}
+
+// listFileSegments returns the segments in dir ordered by their base position
+func listFileSegments(dir string) ([]fileSegment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []fileSegment
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, fileLogExt) {
+			continue
+		}
+		base, err := strconv.ParseUint(strings.TrimSuffix(name, fileLogExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, fileSegment{base: base, dir: dir})
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].base < segments[j].base
+	})
+	return segments, nil
+}
+
+type fileRecord struct {
+	offset    int64
+	size      int64
+	position  uint64
+	remaining uint32
+	data      []byte
+}
+
+// encodeFileRecord appends the encoded record to buf
+func encodeFileRecord(buf []byte, position uint64, remaining uint32, data []byte) []byte {
+	var header [fileRecordHeaderSize + fileRecordMetaSize]byte
+	binary.BigEndian.PutUint32(header[0:4], uint32(fileRecordMetaSize+len(data)))
+	binary.BigEndian.PutUint64(header[8:16], position)
+	binary.BigEndian.PutUint32(header[16:20], remaining)
+
+	crc := crc32.Update(0, crc32c, header[fileRecordHeaderSize:])
+	crc = crc32.Update(crc, crc32c, data)
+	binary.BigEndian.PutUint32(header[4:8], crc)
+
+	buf = append(buf, header[:]...)
+	return append(buf, data...)
+}
+
+// readFileRecord reads the record starting at offset. errTornRecord is returned if
+// the record is incomplete or fails its checksum, and io.EOF if there are no more records.
+func readFileRecord(r *bufio.Reader, offset int64) (*fileRecord, error) {
+	var header [fileRecordHeaderSize]byte
+	n, err := io.ReadFull(r, header[:])
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err == io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("%w: header at offset %d is %d bytes", errTornRecord, offset, n)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length < fileRecordMetaSize || length > fileMaxRecordSize {
+		return nil, fmt.Errorf("%w: invalid length %d at offset %d", errTornRecord, length, offset)
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(r, payload)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("%w: payload at offset %d is incomplete", errTornRecord, offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if crc32.Checksum(payload, crc32c) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, fmt.Errorf("%w: checksum mismatch at offset %d", errTornRecord, offset)
+	}
+
+	return &fileRecord{
+		offset:    offset,
+		size:      int64(fileRecordHeaderSize + length),
+		position:  binary.BigEndian.Uint64(payload[0:8]),
+		remaining: binary.BigEndian.Uint32(payload[8:12]),
+		data:      payload[fileRecordMetaSize:],
+	}, nil
+}
+
+// scanFileSegment reads every complete batch of records in the segment, in order, and returns
+// the offset just past the last complete batch. Records of an incomplete batch at the end of
+// the segment are not passed to fn.
+func scanFileSegment(seg fileSegment, fn func(*fileRecord) error) (end int64, torn bool, err error) {
+	f, err := os.Open(seg.logPath())
+	if err != nil {
+		return 0, false, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var batch []*fileRecord
+	var offset int64
+	for {
+		rec, err := readFileRecord(r, offset)
+		if err == io.EOF {
+			return end, len(batch) > 0, nil
+		}
+		if errors.Is(err, errTornRecord) {
+			return end, true, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		offset += rec.size
+
+		batch = append(batch, rec)
+		if rec.remaining > 0 {
+			continue
+		}
+		for _, rec := range batch {
+			err = fn(rec)
+			if err != nil {
+				return 0, false, err
+			}
+		}
+		batch = batch[:0]
+		end = offset
+	}
+}
+
+// appendFileIndexEntry appends the index entry of a record at offset to buf
+func appendFileIndexEntry(buf []byte, offset int64) []byte {
+	var entry [fileIndexEntrySize]byte
+	binary.BigEndian.PutUint64(entry[:], uint64(offset))
+	return append(buf, entry[:]...)
+}
+
+// writeFileIndex replaces the index of the segment with the given record offsets
+func writeFileIndex(seg fileSegment, offsets []int64) error {
+	buf := make([]byte, 0, len(offsets)*fileIndexEntrySize)
+	for _, offset := range offsets {
+		buf = appendFileIndexEntry(buf, offset)
+	}
+	return os.WriteFile(seg.indexPath(), buf, 0o644)
+}
+
+// readFileIndexEntry returns the offset in the segment log of the record at position
+func readFileIndexEntry(seg fileSegment, position uint64) (int64, error) {
+	f, err := os.Open(seg.indexPath())
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var entry [fileIndexEntrySize]byte
+	_, err = f.ReadAt(entry[:], int64(position-seg.base)*fileIndexEntrySize)
+	if err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(entry[:])), nil
+}
+
+// readFileCheckpoint reads a position written by writeFileCheckpoint
+func readFileCheckpoint(path string) (uint64, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, fmt.Errorf("expected %s to hold 8 bytes but it holds %d", path, len(b))
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// writeFileCheckpoint replaces the file at path with one holding the position. The file
+// is written alongside and renamed over the existing one, so a crash leaves either the
+// previous or the new position.
+func writeFileCheckpoint(path string, position uint64) error {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], position)
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b[:])
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
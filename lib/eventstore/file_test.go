@@ -0,0 +1,404 @@
+package eventstore
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+)
+
+func appendFileTestEvents(t *testing.T, f *File, ids ...string) {
+	for _, id := range ids {
+		_, err := f.Append(context.Background(), newMemoryTestEvent(id, "test"), AnyVersion)
+		require.NoError(t, err, "failed to put event")
+	}
+}
+
+func iterateFile(t *testing.T, f *File, filter Filter) []*Record {
+	ctx := context.Background()
+	iter, err := f.Iterate(ctx, filter)
+	require.NoError(t, err, "failed to iterate events")
+	records, err := readAll(ctx, iter)
+	require.NoError(t, err, "failed to read events")
+	return records
+}
+
+func TestFileConfig_Validate(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config - missing dir", func(t *testing.T) {
+		conf := FileConfig{}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - sync policy", func(t *testing.T) {
+		conf := FileConfig{Dir: t.TempDir(), Sync: "sometimes"}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - segment size", func(t *testing.T) {
+		conf := FileConfig{Dir: t.TempDir(), SegmentSize: -1}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		conf := FileConfig{Dir: t.TempDir(), Sync: FileSyncInterval, StreamExtension: "aggregateid"}
+		req.NoError(conf.Validate(), "config should have validated")
+	})
+}
+
+func TestFile_Append(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	t.Run("is idempotent and checks the expected stream version", func(t *testing.T) {
+		f, err := NewFile(FileConfig{Dir: t.TempDir(), StreamExtension: "aggregateid"})
+		req.NoError(err, "failed to create file event store")
+		defer f.Close()
+
+		ev := newMemoryTestEvent("1", "test")
+		ev.SetExtension("aggregateid", "a")
+		position, err := f.Append(ctx, ev, 0)
+		req.NoError(err, "failed to put event")
+		req.Equal(uint64(1), position, "position not expected value")
+
+		samePosition, err := f.Append(ctx, ev, 0)
+		req.NoError(err, "re-appending the same event should succeed")
+		req.Equal(position, samePosition, "position not expected value")
+
+		conflicting := ev.Clone()
+		conflicting.SetType("other")
+		_, err = f.Append(ctx, &conflicting, AnyVersion)
+		var dupErr *DuplicateEventError
+		req.ErrorAs(err, &dupErr, "expected duplicate event error")
+
+		ev = newMemoryTestEvent("2", "test")
+		ev.SetExtension("aggregateid", "a")
+		_, err = f.Append(ctx, ev, 0)
+		var conflictErr *VersionConflictError
+		req.ErrorAs(err, &conflictErr, "expected version conflict error")
+		req.Equal(uint64(1), conflictErr.Actual, "actual version not expected value")
+	})
+
+	t.Run("rolls over to new segments", func(t *testing.T) {
+		dir := t.TempDir()
+		f, err := NewFile(FileConfig{Dir: dir, SegmentSize: 512, Sync: FileSyncNever})
+		req.NoError(err, "failed to create file event store")
+		defer f.Close()
+
+		appendFileTestEvents(t, f, "1", "2", "3", "4", "5", "6")
+
+		segments, err := listFileSegments(dir)
+		req.NoError(err, "failed to list segments")
+		req.Greater(len(segments), 1, "expected log to be split into segments")
+
+		records := iterateFile(t, f, Filter{AfterPosition: 2})
+		req.Len(records, 4, "unexpected number of events")
+		for i, rec := range records {
+			req.Equal(uint64(i+3), rec.Position, "position not expected value")
+		}
+	})
+}
+
+func TestFile_AppendBatch(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	f, err := NewFile(FileConfig{Dir: t.TempDir()})
+	req.NoError(err, "failed to create file event store")
+	defer f.Close()
+
+	first := newMemoryTestEvent("1", "a")
+	positions, err := f.AppendBatch(ctx, []*event.Event{first, newMemoryTestEvent("2", "b"), first}, AnyVersion)
+	req.NoError(err, "failed to put batch")
+	req.Equal([]uint64{1, 2, 1}, positions, "positions not expected value")
+
+	_, err = f.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("3", "a"), newMemoryTestEvent("4", "c")}, 0)
+	var conflictErr *VersionConflictError
+	req.ErrorAs(err, &conflictErr, "expected version conflict error")
+	req.Len(iterateFile(t, f, Filter{}), 2, "no events should have been stored from the failed batch")
+}
+
+func TestFile_Get(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	f, err := NewFile(FileConfig{Dir: t.TempDir()})
+	req.NoError(err, "failed to create file event store")
+	defer f.Close()
+
+	appendFileTestEvents(t, f, "1", "2", "3")
+
+	rec, err := f.Get(ctx, "memory_test", "2")
+	req.NoError(err, "failed to get event")
+	req.Equal(uint64(2), rec.Position, "position not expected value")
+	req.Equal("2", rec.Event.ID(), "id not expected value")
+	req.JSONEq(`{"hello":"world"}`, string(rec.Event.Data()), "data not expected value")
+
+	_, err = f.Get(ctx, "memory_test", "4")
+	var notFoundErr *EventNotFoundError
+	req.ErrorAs(err, &notFoundErr, "expected event not found error")
+}
+
+func TestFile_Outbox(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	t.Run("outbox disabled", func(t *testing.T) {
+		f, err := NewFile(FileConfig{Dir: t.TempDir()})
+		req.NoError(err, "failed to create file event store")
+		defer f.Close()
+
+		_, err = f.Pending(ctx, 10)
+		var disabledErr *OutboxDisabledError
+		req.ErrorAs(err, &disabledErr, "expected outbox disabled error")
+	})
+
+	t.Run("events appended before enabling are not pending", func(t *testing.T) {
+		dir := t.TempDir()
+		f, err := NewFile(FileConfig{Dir: dir})
+		req.NoError(err, "failed to create file event store")
+		appendFileTestEvents(t, f, "1", "2")
+		req.NoError(f.Close(), "failed to close file event store")
+
+		f, err = NewFile(FileConfig{Dir: dir, Outbox: true})
+		req.NoError(err, "failed to reopen file event store")
+		defer f.Close()
+		appendFileTestEvents(t, f, "3")
+
+		pending, err := f.Pending(ctx, 10)
+		req.NoError(err, "failed to get pending events")
+		req.Len(pending, 1, "unexpected number of pending events")
+		req.Equal(uint64(3), pending[0].Position, "position not expected value")
+	})
+
+	t.Run("dispatched events survive reopening", func(t *testing.T) {
+		dir := t.TempDir()
+		f, err := NewFile(FileConfig{Dir: dir, Outbox: true})
+		req.NoError(err, "failed to create file event store")
+		appendFileTestEvents(t, f, "1", "2", "3", "4")
+
+		pending, err := f.Pending(ctx, 3)
+		req.NoError(err, "failed to get pending events")
+		req.Len(pending, 3, "unexpected number of pending events")
+
+		req.NoError(f.Dispatched(ctx, pending[0], pending[2]), "failed to mark events as dispatched")
+		pending, err = f.Pending(ctx, 10)
+		req.NoError(err, "failed to get pending events")
+		req.Len(pending, 2, "unexpected number of pending events")
+		req.Equal(uint64(2), pending[0].Position, "position not expected value")
+		req.Equal(uint64(4), pending[1].Position, "position not expected value")
+		req.NoError(f.Close(), "failed to close file event store")
+
+		// only the events up to the first pending event are recorded as dispatched,
+		// so the events dispatched after it are pending again once reopened
+		f, err = NewFile(FileConfig{Dir: dir, Outbox: true})
+		req.NoError(err, "failed to reopen file event store")
+		defer f.Close()
+
+		pending, err = f.Pending(ctx, 10)
+		req.NoError(err, "failed to get pending events")
+		req.Len(pending, 3, "unexpected number of pending events")
+		req.Equal(uint64(2), pending[0].Position, "position not expected value")
+
+		req.NoError(f.Dispatched(ctx, pending...), "failed to mark events as dispatched")
+		pending, err = f.Pending(ctx, 10)
+		req.NoError(err, "failed to get pending events")
+		req.Empty(pending, "every event should have been dispatched")
+	})
+}
+
+func TestFile_Checkpoint(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	f, err := NewFile(FileConfig{Dir: dir})
+	req.NoError(err, "failed to create file event store")
+
+	position, err := f.Checkpoint(ctx, "projector/orders")
+	req.NoError(err, "failed to get checkpoint")
+	req.Zero(position, "a group which has not committed should be at position 0")
+
+	req.NoError(f.Commit(ctx, "projector/orders", 5), "failed to commit checkpoint")
+	req.NoError(f.Commit(ctx, "projector/orders", 3), "failed to commit earlier checkpoint")
+	req.NoError(f.Close(), "failed to close file event store")
+
+	f, err = NewFile(FileConfig{Dir: dir})
+	req.NoError(err, "failed to reopen file event store")
+	defer f.Close()
+
+	position, err = f.Checkpoint(ctx, "projector/orders")
+	req.NoError(err, "failed to get checkpoint")
+	req.Equal(uint64(5), position, "checkpoint should have survived reopening")
+}
+
+func TestFile_ReadStream(t *testing.T) {
+	dir := t.TempDir()
+
+	f, err := NewFile(FileConfig{Dir: dir})
+	require.NoError(t, err, "failed to create file event store")
+
+	appendStreamTestEvents(t, f)
+	testReadStream(t, f)
+	require.NoError(t, f.Close(), "failed to close file event store")
+
+	t.Run("after reopening", func(t *testing.T) {
+		f, err := NewFile(FileConfig{Dir: dir})
+		require.NoError(t, err, "failed to reopen file event store")
+		defer f.Close()
+
+		testReadStream(t, f)
+	})
+}
+
+func TestFile_SubscribeConcurrentAppends(t *testing.T) {
+	f, err := NewFile(FileConfig{Dir: t.TempDir()})
+	require.NoError(t, err, "failed to create file event store")
+	defer f.Close()
+
+	testSubscribeConcurrentAppends(t, f)
+}
+
+func TestFile_IterateConcurrentAppends(t *testing.T) {
+	f, err := NewFile(FileConfig{Dir: t.TempDir()})
+	require.NoError(t, err, "failed to create file event store")
+	defer f.Close()
+
+	testIterateConcurrentAppends(t, f)
+}
+
+func TestFile_Recovery(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	t.Run("reopens an existing log", func(t *testing.T) {
+		dir := t.TempDir()
+		f, err := NewFile(FileConfig{Dir: dir, SegmentSize: 512})
+		req.NoError(err, "failed to create file event store")
+		appendFileTestEvents(t, f, "1", "2")
+		ev := newMemoryTestEvent("3", "test")
+		_, err = f.Append(ctx, ev, AnyVersion)
+		req.NoError(err, "failed to put event")
+		appendFileTestEvents(t, f, "4", "5")
+		req.NoError(f.Close(), "failed to close file event store")
+
+		f, err = NewFile(FileConfig{Dir: dir, SegmentSize: 512})
+		req.NoError(err, "failed to reopen file event store")
+		defer f.Close()
+
+		position, err := f.Append(ctx, ev, AnyVersion)
+		req.NoError(err, "re-appending the same event should succeed")
+		req.Equal(uint64(3), position, "position not expected value")
+
+		position, err = f.Append(ctx, newMemoryTestEvent("6", "test"), 5)
+		req.NoError(err, "failed to put event at expected version")
+		req.Equal(uint64(6), position, "position not expected value")
+		req.Len(iterateFile(t, f, Filter{}), 6, "unexpected number of events")
+	})
+
+	t.Run("truncates a torn write", func(t *testing.T) {
+		dir := t.TempDir()
+		f, err := NewFile(FileConfig{Dir: dir})
+		req.NoError(err, "failed to create file event store")
+		appendFileTestEvents(t, f, "1", "2")
+		req.NoError(f.Close(), "failed to close file event store")
+
+		seg := fileSegment{base: 1, dir: dir}
+		info, err := os.Stat(seg.logPath())
+		req.NoError(err, "failed to stat segment")
+		req.NoError(os.Truncate(seg.logPath(), info.Size()-3), "failed to tear write")
+
+		f, err = NewFile(FileConfig{Dir: dir})
+		req.NoError(err, "failed to reopen file event store")
+		defer f.Close()
+
+		records := iterateFile(t, f, Filter{})
+		req.Len(records, 1, "torn event should have been truncated")
+
+		position, err := f.Append(ctx, newMemoryTestEvent("3", "test"), AnyVersion)
+		req.NoError(err, "failed to put event after recovery")
+		req.Equal(uint64(2), position, "position not expected value")
+		req.Len(iterateFile(t, f, Filter{}), 2, "unexpected number of events")
+	})
+
+	t.Run("truncates a corrupt record", func(t *testing.T) {
+		dir := t.TempDir()
+		f, err := NewFile(FileConfig{Dir: dir})
+		req.NoError(err, "failed to create file event store")
+		appendFileTestEvents(t, f, "1", "2")
+		req.NoError(f.Close(), "failed to close file event store")
+
+		seg := fileSegment{base: 1, dir: dir}
+		b, err := os.ReadFile(seg.logPath())
+		req.NoError(err, "failed to read segment")
+		b[len(b)-2] ^= 0xff
+		req.NoError(os.WriteFile(seg.logPath(), b, 0o644), "failed to corrupt segment")
+
+		f, err = NewFile(FileConfig{Dir: dir})
+		req.NoError(err, "failed to reopen file event store")
+		defer f.Close()
+		req.Len(iterateFile(t, f, Filter{}), 1, "corrupt event should have been truncated")
+	})
+
+	t.Run("truncates an incomplete batch", func(t *testing.T) {
+		dir := t.TempDir()
+		f, err := NewFile(FileConfig{Dir: dir})
+		req.NoError(err, "failed to create file event store")
+		appendFileTestEvents(t, f, "1")
+		_, err = f.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("2", "a"), newMemoryTestEvent("3", "b")}, AnyVersion)
+		req.NoError(err, "failed to put batch")
+		req.NoError(f.Close(), "failed to close file event store")
+
+		seg := fileSegment{base: 1, dir: dir}
+		info, err := os.Stat(seg.logPath())
+		req.NoError(err, "failed to stat segment")
+		req.NoError(os.Truncate(seg.logPath(), info.Size()-1), "failed to tear write")
+
+		f, err = NewFile(FileConfig{Dir: dir})
+		req.NoError(err, "failed to reopen file event store")
+		defer f.Close()
+
+		records := iterateFile(t, f, Filter{})
+		req.Len(records, 1, "all events of the torn batch should have been truncated")
+		req.Equal("1", records[0].Event.ID(), "event id not expected value")
+	})
+
+	t.Run("rebuilds a missing index", func(t *testing.T) {
+		dir := t.TempDir()
+		f, err := NewFile(FileConfig{Dir: dir})
+		req.NoError(err, "failed to create file event store")
+		appendFileTestEvents(t, f, "1", "2", "3")
+		req.NoError(f.Close(), "failed to close file event store")
+
+		seg := fileSegment{base: 1, dir: dir}
+		req.NoError(os.Remove(seg.indexPath()), "failed to remove index")
+
+		f, err = NewFile(FileConfig{Dir: dir})
+		req.NoError(err, "failed to reopen file event store")
+		defer f.Close()
+
+		records := iterateFile(t, f, Filter{AfterPosition: 1})
+		req.Len(records, 2, "unexpected number of events")
+		req.Equal("2", records[0].Event.ID(), "event id not expected value")
+	})
+
+	t.Run("fails on a corrupt sealed segment", func(t *testing.T) {
+		dir := t.TempDir()
+		f, err := NewFile(FileConfig{Dir: dir, SegmentSize: 512})
+		req.NoError(err, "failed to create file event store")
+		appendFileTestEvents(t, f, "1", "2", "3", "4", "5")
+		req.NoError(f.Close(), "failed to close file event store")
+
+		seg := fileSegment{base: 1, dir: dir}
+		info, err := os.Stat(seg.logPath())
+		req.NoError(err, "failed to stat segment")
+		req.NoError(os.Truncate(seg.logPath(), info.Size()-1), "failed to tear write")
+
+		_, err = NewFile(FileConfig{Dir: dir, SegmentSize: 512})
+		var corruptErr *CorruptSegmentError
+		req.ErrorAs(err, &corruptErr, "expected corrupt segment error")
+	})
+}
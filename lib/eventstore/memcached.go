@@ -0,0 +1,184 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// DefaultMemcachedCachePrefix is prepended to every key written to memcached
+const DefaultMemcachedCachePrefix = "evrys:"
+
+// MemcachedCacheConfig defines the configuration to connect to memcached and how events are cached
+type MemcachedCacheConfig struct {
+	// Servers are the addresses of the memcached servers, which events are spread across by their key
+	Servers []string `mapstructure:"servers" validate:"required,min=1,dive,hostname_port"`
+
+	// Timeout is the socket read/write timeout, defaulting to memcache.DefaultTimeout
+	Timeout time.Duration `mapstructure:"timeout" validate:"gte=0"`
+
+	// Prefix is prepended to every key, defaulting to DefaultMemcachedCachePrefix
+	Prefix string `mapstructure:"prefix"`
+
+	// TTL is how long an event stays cached. Since events never change once appended, they are
+	// only evicted when memcached runs out of memory if it is not set. Memcached treats expirations
+	// over 30 days as a unix time, so it can be at most 720h.
+	TTL time.Duration `mapstructure:"ttl" validate:"gte=0,lte=720h"`
+}
+
+// Validate ensures memcached cache config is correct
+func (m *MemcachedCacheConfig) Validate() error {
+	return validator.New().Struct(m)
+}
+
+// MemcachedCache wraps an event store and caches individual events in memcached by their id and source,
+// so looking up an event with Get usually avoids the event store. Events are cached aside when they are
+// first looked up, while appending and iterating go straight to the wrapped event store.
+//
+// Lookups of events which do not exist are not cached, since the event may be appended later. Failing
+// to read from or write to memcached does not fail a lookup, which is read from the event store instead.
+type MemcachedCache struct {
+	config MemcachedCacheConfig
+	logger *zap.Logger
+	store  Store
+	client *memcache.Client
+}
+
+// NewMemcachedCache constructs a *MemcachedCache wrapping the given event store and checks it can connect to memcached
+func NewMemcachedCache(ctx context.Context, store Store, config MemcachedCacheConfig) (*MemcachedCache, error) {
+	if ctx == nil {
+		return nil, errors.New("context can not be nil")
+	}
+	if store == nil {
+		return nil, errors.New("store can not be nil")
+	}
+
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config, %w", err)
+	}
+	if config.Prefix == "" {
+		config.Prefix = DefaultMemcachedCachePrefix
+	}
+
+	impl := &MemcachedCache{
+		config: config,
+		logger: zap.L().With(zap.String("source", "MemcachedCacheImpl")),
+		store:  store,
+	}
+
+	err = impl.init()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init, %w", err)
+	}
+
+	return impl, nil
+}
+
+func (m *MemcachedCache) init() error {
+	m.client = memcache.New(m.config.Servers...)
+	m.client.Timeout = m.config.Timeout
+
+	m.logger.Debug("attempting to ping memcached")
+	err := m.client.Ping()
+	if err != nil {
+		m.logger.Error("failed to ping memcached", zap.Error(err))
+		return NewConnectionError("memcached", err)
+	}
+	m.logger.Debug("successfully pinged memcached")
+
+	return nil
+}
+
+// Close closes the wrapped event store if it is an io.Closer
+func (m *MemcachedCache) Close() error {
+	if closer, ok := m.store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// key returns the key an event is cached under. Memcached keys can not contain
+// whitespace and are limited to 250 bytes, so the source and id are hashed.
+func (m *MemcachedCache) key(source, id string) string {
+	return m.config.Prefix + "event:" + HashKey(source, id)
+}
+
+// Append appends the event to the wrapped event store and implements the interface AppendOnly
+func (m *MemcachedCache) Append(ctx context.Context, event *event.Event, expectedVersion uint64) (uint64, error) {
+	return m.store.Append(ctx, event, expectedVersion)
+}
+
+// AppendBatch appends the events to the wrapped event store and implements the interface BatchAppendOnly
+func (m *MemcachedCache) AppendBatch(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+	return m.store.AppendBatch(ctx, events, expectedVersion)
+}
+
+// Iterate iterates over the events in the wrapped event store and implements the interface Iterable
+func (m *MemcachedCache) Iterate(ctx context.Context, filter Filter) (Iterator, error) {
+	return m.store.Iterate(ctx, filter)
+}
+
+// Subscribe subscribes to the wrapped event store and implements the interface Subscribable
+func (m *MemcachedCache) Subscribe(ctx context.Context, filter Filter) (Iterator, error) {
+	return Subscribe(ctx, m.store, filter)
+}
+
+// ReadStream reads the stream from the wrapped event store and implements the interface StreamReader
+func (m *MemcachedCache) ReadStream(ctx context.Context, stream string, opts ReadStreamOptions) (Iterator, error) {
+	return m.store.ReadStream(ctx, stream, opts)
+}
+
+// Checkpoint returns the checkpoint of a consumer group from the wrapped event store and implements the interface Checkpointer
+func (m *MemcachedCache) Checkpoint(ctx context.Context, group string) (uint64, error) {
+	return m.store.Checkpoint(ctx, group)
+}
+
+// Commit commits the checkpoint of a consumer group to the wrapped event store and implements the interface Checkpointer
+func (m *MemcachedCache) Commit(ctx context.Context, group string, position uint64) error {
+	return m.store.Commit(ctx, group, position)
+}
+
+// Get returns the event with the given source and id from memcached, or from the wrapped event store
+// if it is not cached, in which case it is then cached. Get implements the interface Gettable.
+func (m *MemcachedCache) Get(ctx context.Context, source, id string) (*Record, error) {
+	key := m.key(source, id)
+
+	item, err := m.client.Get(key)
+	switch {
+	case err == nil:
+		rec, err := decodeCachedRecord(string(item.Value))
+		if err == nil {
+			m.logger.Debug("read event from cache", zap.String("event_id", id), zap.String("event_source", source))
+			return rec, nil
+		}
+		m.logger.Warn("failed to decode cached event", zap.Error(err), zap.String("event_id", id), zap.String("event_source", source))
+	case err != memcache.ErrCacheMiss:
+		m.logger.Warn("failed to read event from cache", zap.Error(err), zap.String("event_id", id), zap.String("event_source", source))
+	}
+
+	rec, err := m.store.Get(ctx, source, id)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := encodeCachedRecord(rec)
+	if err == nil {
+		err = m.client.Set(&memcache.Item{
+			Key:        key,
+			Value:      []byte(value),
+			Expiration: int32(m.config.TTL / time.Second),
+		})
+	}
+	if err != nil {
+		m.logger.Warn("failed to cache event", zap.Error(err), zap.String("event_id", id), zap.String("event_source", source))
+	}
+	return rec, nil
+}
@@ -0,0 +1,134 @@
+package eventstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestMemcachedCacheConfig_Validate(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config - no servers", func(t *testing.T) {
+		conf := MemcachedCacheConfig{}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - server without port", func(t *testing.T) {
+		conf := MemcachedCacheConfig{Servers: []string{"localhost:11211", "localhost"}}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - ttl over 30 days", func(t *testing.T) {
+		conf := MemcachedCacheConfig{Servers: []string{"localhost:11211"}, TTL: 31 * 24 * time.Hour}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		conf := MemcachedCacheConfig{Servers: []string{"localhost:11211"}, TTL: time.Hour}
+		req.NoError(conf.Validate(), "config should have validated")
+	})
+}
+
+func TestNewMemcachedCache(t *testing.T) {
+	req := require.New(t)
+	store, err := NewMemory(MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	t.Run("nil ctx", func(t *testing.T) {
+		_, err := NewMemcachedCache(nil, store, MemcachedCacheConfig{})
+		req.ErrorContains(err, "context can not be nil", "error is not target error")
+	})
+
+	t.Run("nil store", func(t *testing.T) {
+		_, err := NewMemcachedCache(context.TODO(), nil, MemcachedCacheConfig{})
+		req.ErrorContains(err, "store can not be nil", "error is not target error")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewMemcachedCache(context.TODO(), store, MemcachedCacheConfig{})
+		req.ErrorAs(err, &ValidationErrors, "expected validation error")
+	})
+
+	t.Run("memcached connection error", func(t *testing.T) {
+		_, err := NewMemcachedCache(context.TODO(), store, MemcachedCacheConfig{Servers: []string{"localhost:1"}, Timeout: time.Second})
+		var connErr *ConnectionError
+		req.ErrorAs(err, &connErr, "expected connection error")
+	})
+}
+
+func TestMemcachedCacheIntegration(t *testing.T) {
+	// setup
+	req := require.New(t)
+	ctx := context.Background()
+
+	// container init
+	contReq := testcontainers.ContainerRequest{
+		Image:        "memcached:1.6",
+		ExposedPorts: []string{"11211:11211"},
+		WaitingFor:   wait.ForListeningPort("11211/tcp"),
+	}
+	memcachedC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: contReq,
+		Started:          true,
+	})
+	req.NoError(err, "failed to create memcached container")
+	defer memcachedC.Terminate(ctx)
+
+	// memcached verification
+	client := memcache.New("localhost:11211")
+	req.NoError(client.Ping(), "failed to connect to memcached")
+
+	// impl setup
+	store, err := NewMemory(MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	cache, err := NewMemcachedCache(ctx, store, MemcachedCacheConfig{
+		Servers: []string{"localhost:11211"},
+		TTL:     time.Minute,
+	})
+	req.NoError(err, "failed to create memcached cache")
+	defer cache.Close()
+
+	key := "evrys:event:" + HashKey("memory_test", "1")
+
+	// appending does not cache events
+	position, err := cache.Append(ctx, newMemoryTestEvent("1", "a"), AnyVersion)
+	req.NoError(err, "failed to put event")
+	_, err = client.Get(key)
+	req.ErrorIs(err, memcache.ErrCacheMiss, "event should not be cached")
+
+	// cache aside
+	rec, err := cache.Get(ctx, "memory_test", "1")
+	req.NoError(err, "failed to get event")
+	req.Equal(position, rec.Position, "position not expected value")
+	req.Equal("1", rec.Event.ID(), "id not expected value")
+
+	item, err := client.Get(key)
+	req.NoError(err, "event should be cached")
+	cached, err := decodeCachedRecord(string(item.Value))
+	req.NoError(err, "failed to decode cached event")
+	req.Equal(position, cached.Position, "position not expected value")
+
+	// the cache is read, not the event store
+	cached.Event.SetType("cached")
+	value, err := encodeCachedRecord(cached)
+	req.NoError(err, "failed to encode cached event")
+	req.NoError(client.Set(&memcache.Item{Key: key, Value: []byte(value)}), "failed to overwrite cached event")
+
+	rec, err = cache.Get(ctx, "memory_test", "1")
+	req.NoError(err, "failed to get event")
+	req.Equal("cached", rec.Event.Type(), "event should be read from cache")
+
+	// events which do not exist are not cached
+	_, err = cache.Get(ctx, "memory_test", "2")
+	var notFoundErr *EventNotFoundError
+	req.ErrorAs(err, &notFoundErr, "expected event not found error")
+	_, err = client.Get("evrys:event:" + HashKey("memory_test", "2"))
+	req.ErrorIs(err, memcache.ErrCacheMiss, "missing event should not be cached")
+}
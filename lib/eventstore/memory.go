@@ -0,0 +1,365 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// MemoryConfig defines the configuration of the in-memory event store
+type MemoryConfig struct {
+	// StreamExtension is the extension attribute used to group events into streams.
+	// Events are grouped by their subject if it is not set.
+	StreamExtension string `mapstructure:"stream_extension" validate:"omitempty,alphanum,lowercase"`
+
+	// Outbox records every appended event as pending dispatch until it is marked as dispatched
+	Outbox bool `mapstructure:"outbox"`
+}
+
+// Validate ensures memory config is correct
+func (m *MemoryConfig) Validate() error {
+	return validator.New().Struct(m)
+}
+
+type eventKey struct {
+	source string
+	id     string
+}
+
+// Memory is an in-process event store implementation which is safe for concurrent use.
+// All events are lost once the process exits, so it is intended for tests and local development.
+type Memory struct {
+	config MemoryConfig
+	logger *zap.Logger
+
+	mu       sync.RWMutex
+	records  []*Record
+	ids      map[eventKey]*Record
+	streams  map[string][]*Record
+	versions map[string]uint64
+	pending  []*Record
+	appended appendSignal
+
+	checkpoints map[string]uint64
+}
+
+// NewMemory constructs an empty *Memory
+func NewMemory(config MemoryConfig) (*Memory, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config, %w", err)
+	}
+
+	return &Memory{
+		config:   config,
+		logger:   zap.L().With(zap.String("source", "MemoryEventStoreImpl")),
+		ids:      make(map[eventKey]*Record),
+		streams:  make(map[string][]*Record),
+		versions: make(map[string]uint64),
+
+		checkpoints: make(map[string]uint64),
+	}, nil
+}
+
+// Append stores an event in memory, returning its position in the log, and implements the interface AppendOnly.
+func (m *Memory) Append(ctx context.Context, event *event.Event, expectedVersion uint64) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, err := m.existing(event)
+	if err != nil {
+		return 0, err
+	}
+	if existing != nil {
+		return existing.Position, nil
+	}
+
+	stream := StreamOf(event, m.config.StreamExtension)
+	if version := m.versions[stream]; expectedVersion != AnyVersion && version != expectedVersion {
+		m.logger.Warn("stream is not at expected version",
+			zap.String("stream", stream),
+			zap.Uint64("expected_version", expectedVersion),
+			zap.Uint64("actual_version", version),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return 0, NewVersionConflictError(stream, expectedVersion, version)
+	}
+
+	return m.insert(event, stream), nil
+}
+
+// AppendBatch stores events in memory such that either all or none of them are stored
+// and implements the interface BatchAppendOnly.
+func (m *Memory) AppendBatch(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	positions := make([]uint64, len(events))
+	inserted := true
+	batched := make(map[eventKey]int, len(events))
+	for i, ev := range events {
+		existing, err := m.existing(ev)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			positions[i] = existing.Position
+			continue
+		}
+		inserted = false
+
+		key := eventKey{source: ev.Source(), id: ev.ID()}
+		if j, ok := batched[key]; ok && !sameEvent(events[j], ev) {
+			return nil, NewDuplicateEventError(ev.Source(), ev.ID())
+		}
+		batched[key] = i
+	}
+	if inserted {
+		return positions, nil
+	}
+
+	if expectedVersion != AnyVersion {
+		for _, ev := range events {
+			stream := StreamOf(ev, m.config.StreamExtension)
+			if version := m.versions[stream]; version != expectedVersion {
+				return nil, NewVersionConflictError(stream, expectedVersion, version)
+			}
+		}
+	}
+
+	for i, ev := range events {
+		existing, _ := m.existing(ev)
+		if existing != nil {
+			positions[i] = existing.Position
+			continue
+		}
+		positions[i] = m.insert(ev, StreamOf(ev, m.config.StreamExtension))
+	}
+	m.logger.Info("successfully inserted batch", zap.Int("events", len(events)))
+	return positions, nil
+}
+
+// existing returns the record of an identical event which has already been stored, or
+// a *DuplicateEventError if a different event with the same id and source has been stored.
+// The caller must hold the lock.
+func (m *Memory) existing(event *event.Event) (*Record, error) {
+	rec, ok := m.ids[eventKey{source: event.Source(), id: event.ID()}]
+	if !ok {
+		return nil, nil
+	}
+	if !sameEvent(rec.Event, event) {
+		m.logger.Warn("event already exists with different content",
+			zap.Uint64("position", rec.Position),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return nil, NewDuplicateEventError(event.Source(), event.ID())
+	}
+	m.logger.Info("event has already been inserted",
+		zap.Uint64("position", rec.Position),
+		zap.String("event_id", event.ID()),
+		zap.String("event_type", event.Type()),
+		zap.String("event_source", event.Source()),
+		zap.String("event_subject", event.Subject()),
+	)
+	return rec, nil
+}
+
+// insert stores a copy of the event at the next position. The caller must hold the lock.
+func (m *Memory) insert(event *event.Event, stream string) uint64 {
+	ev := event.Clone()
+	rec := &Record{
+		Position: uint64(len(m.records) + 1),
+		Event:    &ev,
+	}
+	m.records = append(m.records, rec)
+	m.ids[eventKey{source: ev.Source(), id: ev.ID()}] = rec
+	if stream != "" {
+		m.streams[stream] = append(m.streams[stream], rec)
+		m.versions[stream]++
+	}
+	if m.config.Outbox {
+		m.pending = append(m.pending, rec)
+	}
+	m.appended.broadcast()
+
+	m.logger.Info("successfully inserted event",
+		zap.Uint64("position", rec.Position),
+		zap.String("event_id", ev.ID()),
+		zap.String("event_type", ev.Type()),
+		zap.String("event_source", ev.Source()),
+		zap.String("event_subject", ev.Subject()),
+	)
+	return rec.Position
+}
+
+// Get returns a copy of the event with the given source and id, and implements the interface Gettable
+func (m *Memory) Get(ctx context.Context, source, id string) (*Record, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rec, ok := m.ids[eventKey{source: source, id: id}]
+	if !ok {
+		return nil, NewEventNotFoundError(source, id)
+	}
+
+	ev := rec.Event.Clone()
+	return &Record{
+		Position: rec.Position,
+		Event:    &ev,
+	}, nil
+}
+
+// Pending returns copies of up to limit events which are pending dispatch, in the order they were appended,
+// and implements the interface Outbox
+func (m *Memory) Pending(ctx context.Context, limit int) ([]*Record, error) {
+	if !m.config.Outbox {
+		return nil, NewOutboxDisabledError("memory")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	n := len(m.pending)
+	if limit < n {
+		n = limit
+	}
+	records := make([]*Record, n)
+	for i, rec := range m.pending[:n] {
+		ev := rec.Event.Clone()
+		records[i] = &Record{
+			Position: rec.Position,
+			Event:    &ev,
+		}
+	}
+	return records, nil
+}
+
+// Dispatched marks the events as no longer pending dispatch and implements the interface Outbox
+func (m *Memory) Dispatched(ctx context.Context, records ...*Record) error {
+	if !m.config.Outbox {
+		return NewOutboxDisabledError("memory")
+	}
+
+	dispatched := make(map[uint64]bool, len(records))
+	for _, rec := range records {
+		dispatched[rec.Position] = true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pending := m.pending[:0]
+	for _, rec := range m.pending {
+		if !dispatched[rec.Position] {
+			pending = append(pending, rec)
+		}
+	}
+	for i := len(pending); i < len(m.pending); i++ {
+		m.pending[i] = nil
+	}
+	m.pending = pending
+	return nil
+}
+
+// Checkpoint returns the position a consumer group has committed and implements the interface Checkpointer
+func (m *Memory) Checkpoint(ctx context.Context, group string) (uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.checkpoints[group], nil
+}
+
+// Commit moves the checkpoint of a consumer group forward to the position and implements the interface Checkpointer
+func (m *Memory) Commit(ctx context.Context, group string, position uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if position > m.checkpoints[group] {
+		m.checkpoints[group] = position
+	}
+	return nil
+}
+
+// Iterate returns an Iterator over the events in memory which match the filter in the order they were appended
+// and implements the interface Iterable. Events appended after Iterate is called are not returned.
+func (m *Memory) Iterate(ctx context.Context, filter Filter) (Iterator, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// positions start at 1 and have no gaps, so the events after a position
+	// are found by slicing rather than by scanning from the first event
+	records := m.records
+	if filter.AfterPosition < uint64(len(records)) {
+		records = records[filter.AfterPosition:]
+	} else {
+		records = nil
+	}
+
+	// records are never modified once appended, so a snapshot
+	// of the slice can be read without holding the lock
+	return &memoryIterator{
+		filter:  filter,
+		records: records,
+	}, nil
+}
+
+// ReadStream returns an Iterator over copies of the events of the stream in the order of their versions
+// and implements the interface StreamReader
+func (m *Memory) ReadStream(ctx context.Context, stream string, opts ReadStreamOptions) (Iterator, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return &recordsIterator{
+		records: readStreamRecords(m.streams[stream], opts),
+	}, nil
+}
+
+// Subscribe returns an Iterator over the events in memory which match the filter, which waits for matching events
+// to be appended once it has returned every stored event, and implements the interface Subscribable
+func (m *Memory) Subscribe(ctx context.Context, filter Filter) (Iterator, error) {
+	return newSubscription(m, filter, &m.appended, nil), nil
+}
+
+type memoryIterator struct {
+	filter  Filter
+	records []*Record
+}
+
+// Next returns a copy of the next matching event and implements the interface Iterator
+func (it *memoryIterator) Next(ctx context.Context) (*Record, error) {
+	for len(it.records) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		rec := it.records[0]
+		it.records = it.records[1:]
+		if !it.filter.match(rec) {
+			continue
+		}
+
+		ev := rec.Event.Clone()
+		return &Record{
+			Position: rec.Position,
+			Event:    &ev,
+		}, nil
+	}
+	return nil, io.EOF
+}
+
+// Close implements the interface Iterator
+func (it *memoryIterator) Close(ctx context.Context) error {
+	it.records = nil
+	return nil
+}
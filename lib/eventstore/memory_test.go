@@ -0,0 +1,382 @@
+package eventstore
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryTestEvent(id, subject string) *event.Event {
+	ev := event.New()
+	ev.SetID(id)
+	ev.SetSubject(subject)
+	ev.SetSource("memory_test")
+	ev.SetTime(time.Now().UTC())
+	ev.SetSpecVersion(event.CloudEventsVersionV1)
+	ev.SetType("test")
+	ev.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": "world"})
+	return &ev
+}
+
+func readAll(ctx context.Context, iter Iterator) ([]*Record, error) {
+	defer iter.Close(ctx)
+
+	var records []*Record
+	for {
+		rec, err := iter.Next(ctx)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
+
+func TestMemoryConfig_Validate(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config - stream extension", func(t *testing.T) {
+		conf := MemoryConfig{StreamExtension: "Not_Valid"}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		conf := MemoryConfig{StreamExtension: "aggregateid"}
+		req.NoError(conf.Validate(), "config should have validated")
+	})
+}
+
+func TestMemory_Append(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	t.Run("assigns increasing positions", func(t *testing.T) {
+		m, err := NewMemory(MemoryConfig{})
+		req.NoError(err, "failed to create memory event store")
+
+		for i, id := range []string{"1", "2", "3"} {
+			position, err := m.Append(ctx, newMemoryTestEvent(id, "test"), AnyVersion)
+			req.NoError(err, "failed to put event")
+			req.Equal(uint64(i+1), position, "position not expected value")
+		}
+	})
+
+	t.Run("is idempotent", func(t *testing.T) {
+		m, err := NewMemory(MemoryConfig{})
+		req.NoError(err, "failed to create memory event store")
+
+		ev := newMemoryTestEvent("1", "test")
+		position, err := m.Append(ctx, ev, AnyVersion)
+		req.NoError(err, "failed to put event")
+
+		samePosition, err := m.Append(ctx, ev, 0)
+		req.NoError(err, "re-appending the same event should succeed")
+		req.Equal(position, samePosition, "position not expected value")
+
+		conflicting := ev.Clone()
+		conflicting.SetType("other")
+		_, err = m.Append(ctx, &conflicting, AnyVersion)
+		var dupErr *DuplicateEventError
+		req.ErrorAs(err, &dupErr, "expected duplicate event error")
+	})
+
+	t.Run("checks the expected stream version", func(t *testing.T) {
+		m, err := NewMemory(MemoryConfig{StreamExtension: "aggregateid"})
+		req.NoError(err, "failed to create memory event store")
+
+		ev := newMemoryTestEvent("1", "test")
+		ev.SetExtension("aggregateid", "a")
+		_, err = m.Append(ctx, ev, 0)
+		req.NoError(err, "failed to put event to new stream")
+
+		ev = newMemoryTestEvent("2", "test")
+		ev.SetExtension("aggregateid", "a")
+		_, err = m.Append(ctx, ev, 0)
+		var conflictErr *VersionConflictError
+		req.ErrorAs(err, &conflictErr, "expected version conflict error")
+		req.Equal("a", conflictErr.Stream, "stream not expected value")
+		req.Equal(uint64(1), conflictErr.Actual, "actual version not expected value")
+
+		_, err = m.Append(ctx, ev, 1)
+		req.NoError(err, "failed to put event at expected version")
+	})
+
+	t.Run("is safe for concurrent use", func(t *testing.T) {
+		m, err := NewMemory(MemoryConfig{})
+		req.NoError(err, "failed to create memory event store")
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				m.Append(ctx, newMemoryTestEvent(string(rune('A'+i)), "test"), AnyVersion)
+			}(i)
+		}
+		wg.Wait()
+
+		iter, err := m.Iterate(ctx, Filter{})
+		req.NoError(err, "failed to iterate events")
+		records, err := readAll(ctx, iter)
+		req.NoError(err, "failed to read events")
+		req.Len(records, 50, "returned slice not of correct length")
+		for i, rec := range records {
+			req.Equal(uint64(i+1), rec.Position, "position not expected value")
+		}
+	})
+}
+
+func TestMemory_AppendBatch(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	m, err := NewMemory(MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	positions, err := m.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("1", "test"), newMemoryTestEvent("2", "test")}, 0)
+	req.NoError(err, "failed to put batch")
+	req.Equal([]uint64{1, 2}, positions, "positions not expected value")
+
+	_, err = m.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("3", "test"), newMemoryTestEvent("4", "test")}, 0)
+	var conflictErr *VersionConflictError
+	req.ErrorAs(err, &conflictErr, "expected version conflict error")
+
+	conflicting := newMemoryTestEvent("1", "test")
+	conflicting.SetType("other")
+	_, err = m.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("5", "test"), conflicting}, AnyVersion)
+	var dupErr *DuplicateEventError
+	req.ErrorAs(err, &dupErr, "expected duplicate event error")
+
+	conflicting = newMemoryTestEvent("6", "test")
+	conflicting.SetType("other")
+	_, err = m.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("6", "test"), conflicting}, AnyVersion)
+	req.ErrorAs(err, &dupErr, "expected duplicate event error within batch")
+
+	iter, err := m.Iterate(ctx, Filter{})
+	req.NoError(err, "failed to iterate events")
+	records, err := readAll(ctx, iter)
+	req.NoError(err, "failed to read events")
+	req.Len(records, 2, "only the first batch should have been stored")
+}
+
+func TestMemory_Get(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	m, err := NewMemory(MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	_, err = m.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("1", "test"), newMemoryTestEvent("2", "test")}, AnyVersion)
+	req.NoError(err, "failed to put batch")
+
+	rec, err := m.Get(ctx, "memory_test", "2")
+	req.NoError(err, "failed to get event")
+	req.Equal(uint64(2), rec.Position, "position not expected value")
+	req.Equal("2", rec.Event.ID(), "id not expected value")
+
+	rec.Event.SetType("modified")
+	rec, err = m.Get(ctx, "memory_test", "2")
+	req.NoError(err, "failed to get event")
+	req.Equal("test", rec.Event.Type(), "returned event should be a copy")
+
+	_, err = m.Get(ctx, "other_source", "2")
+	var notFoundErr *EventNotFoundError
+	req.ErrorAs(err, &notFoundErr, "expected event not found error")
+}
+
+func TestMemory_Outbox(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	t.Run("outbox disabled", func(t *testing.T) {
+		m, err := NewMemory(MemoryConfig{})
+		req.NoError(err, "failed to create memory event store")
+
+		_, err = m.Pending(ctx, 10)
+		var disabledErr *OutboxDisabledError
+		req.ErrorAs(err, &disabledErr, "expected outbox disabled error")
+	})
+
+	m, err := NewMemory(MemoryConfig{Outbox: true})
+	req.NoError(err, "failed to create memory event store")
+
+	_, err = m.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("1", "a"), newMemoryTestEvent("2", "b"), newMemoryTestEvent("3", "c")}, AnyVersion)
+	req.NoError(err, "failed to put batch")
+
+	pending, err := m.Pending(ctx, 2)
+	req.NoError(err, "failed to get pending events")
+	req.Len(pending, 2, "unexpected number of pending events")
+	req.Equal(uint64(1), pending[0].Position, "position not expected value")
+	req.Equal(uint64(2), pending[1].Position, "position not expected value")
+
+	req.NoError(m.Dispatched(ctx, pending[1]), "failed to mark event as dispatched")
+	pending, err = m.Pending(ctx, 10)
+	req.NoError(err, "failed to get pending events")
+	req.Len(pending, 2, "unexpected number of pending events")
+	req.Equal(uint64(1), pending[0].Position, "position not expected value")
+	req.Equal(uint64(3), pending[1].Position, "position not expected value")
+
+	req.NoError(m.Dispatched(ctx, pending...), "failed to mark events as dispatched")
+	pending, err = m.Pending(ctx, 10)
+	req.NoError(err, "failed to get pending events")
+	req.Empty(pending, "every event should have been dispatched")
+}
+
+func TestMemory_Checkpoint(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	m, err := NewMemory(MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	position, err := m.Checkpoint(ctx, "projector")
+	req.NoError(err, "failed to get checkpoint")
+	req.Zero(position, "a group which has not committed should be at position 0")
+
+	req.NoError(m.Commit(ctx, "projector", 5), "failed to commit checkpoint")
+	req.NoError(m.Commit(ctx, "projector", 3), "failed to commit earlier checkpoint")
+	req.NoError(m.Commit(ctx, "other", 1), "failed to commit checkpoint of other group")
+
+	position, err = m.Checkpoint(ctx, "projector")
+	req.NoError(err, "failed to get checkpoint")
+	req.Equal(uint64(5), position, "checkpoint should not have moved back")
+}
+
+func TestMemory_ReadStream(t *testing.T) {
+	m, err := NewMemory(MemoryConfig{})
+	require.NoError(t, err, "failed to create memory event store")
+
+	appendStreamTestEvents(t, m)
+	testReadStream(t, m)
+}
+
+func TestMemory_SubscribeConcurrentAppends(t *testing.T) {
+	m, err := NewMemory(MemoryConfig{})
+	require.NoError(t, err, "failed to create memory event store")
+
+	testSubscribeConcurrentAppends(t, m)
+}
+
+func TestMemory_IterateConcurrentAppends(t *testing.T) {
+	m, err := NewMemory(MemoryConfig{})
+	require.NoError(t, err, "failed to create memory event store")
+
+	testIterateConcurrentAppends(t, m)
+}
+
+func TestMemory_Iterate(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	m, err := NewMemory(MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	for _, id := range []string{"1", "2", "3"} {
+		_, err := m.Append(ctx, newMemoryTestEvent(id, "subject-"+id), AnyVersion)
+		req.NoError(err, "failed to put event")
+	}
+
+	t.Run("all events", func(t *testing.T) {
+		iter, err := m.Iterate(ctx, Filter{})
+		req.NoError(err, "failed to iterate events")
+		records, err := readAll(ctx, iter)
+		req.NoError(err, "failed to read events")
+		req.Len(records, 3, "returned slice not of correct length")
+		req.Equal("1", records[0].Event.ID(), "id not expected value")
+		req.JSONEq(`{"hello":"world"}`, string(records[0].Event.Data()), "data not expected value")
+	})
+
+	t.Run("filtered events", func(t *testing.T) {
+		iter, err := m.Iterate(ctx, Filter{AfterPosition: 1, Subjects: []string{"subject-1", "subject-3"}})
+		req.NoError(err, "failed to iterate events")
+		records, err := readAll(ctx, iter)
+		req.NoError(err, "failed to read events")
+		req.Len(records, 1, "returned slice not of correct length")
+		req.Equal(uint64(3), records[0].Position, "position not expected value")
+	})
+
+	t.Run("starts after the position", func(t *testing.T) {
+		iter, err := m.Iterate(ctx, Filter{AfterPosition: 2})
+		req.NoError(err, "failed to iterate events")
+		req.Len(iter.(*memoryIterator).records, 1, "events before the position should not be scanned")
+		records, err := readAll(ctx, iter)
+		req.NoError(err, "failed to read events")
+		req.Len(records, 1, "returned slice not of correct length")
+		req.Equal(uint64(3), records[0].Position, "position not expected value")
+
+		iter, err = m.Iterate(ctx, Filter{AfterPosition: 5})
+		req.NoError(err, "failed to iterate events")
+		records, err = readAll(ctx, iter)
+		req.NoError(err, "failed to read events")
+		req.Empty(records, "no events should have been read")
+	})
+
+	t.Run("returned events are copies", func(t *testing.T) {
+		iter, err := m.Iterate(ctx, Filter{})
+		req.NoError(err, "failed to iterate events")
+		records, err := readAll(ctx, iter)
+		req.NoError(err, "failed to read events")
+		records[0].Event.SetType("modified")
+
+		iter, err = m.Iterate(ctx, Filter{})
+		req.NoError(err, "failed to iterate events")
+		records, err = readAll(ctx, iter)
+		req.NoError(err, "failed to read events")
+		req.Equal("test", records[0].Event.Type(), "stored event should not have been modified")
+	})
+}
+
+func TestMemory_Subscribe(t *testing.T) {
+	req := require.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	m, err := NewMemory(MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	for _, id := range []string{"1", "2", "3"} {
+		_, err := m.Append(ctx, newMemoryTestEvent(id, "subject-"+id), AnyVersion)
+		req.NoError(err, "failed to put event")
+	}
+
+	iter, err := m.Subscribe(ctx, Filter{AfterPosition: 1, Subjects: []string{"subject-2", "subject-3", "subject-5"}})
+	req.NoError(err, "failed to subscribe")
+	defer iter.Close(ctx)
+
+	t.Run("catches up on stored events", func(t *testing.T) {
+		for _, position := range []uint64{2, 3} {
+			rec, err := iter.Next(ctx)
+			req.NoError(err, "failed to read event")
+			req.Equal(position, rec.Position, "position not expected value")
+		}
+	})
+
+	t.Run("waits for appended events", func(t *testing.T) {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			m.Append(ctx, newMemoryTestEvent("4", "subject-4"), AnyVersion)
+			m.Append(ctx, newMemoryTestEvent("5", "subject-5"), AnyVersion)
+		}()
+
+		start := time.Now()
+		rec, err := iter.Next(ctx)
+		req.NoError(err, "failed to read event")
+		req.Equal(uint64(5), rec.Position, "position not expected value")
+		req.Less(time.Since(start), subscribePollInterval, "subscription should have been signalled")
+	})
+
+	t.Run("returns once the context is done", func(t *testing.T) {
+		nextCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		_, err := iter.Next(nextCtx)
+		req.ErrorIs(err, context.DeadlineExceeded, "expected context error")
+	})
+}
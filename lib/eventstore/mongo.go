@@ -4,6 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/go-playground/validator/v10"
@@ -23,6 +28,14 @@ type MongoConfig struct {
 
 	Database   string `mapstructure:"database" validate:"required"`
 	Collection string `mapstructure:"collection" validate:"required"`
+
+	// StreamExtension is the extension attribute used to group events into streams.
+	// Events are grouped by their subject if it is not set.
+	StreamExtension string `mapstructure:"stream_extension" validate:"omitempty,alphanum,lowercase"`
+
+	// Outbox records every appended event as pending dispatch until it is marked as dispatched.
+	// The marker is a field of the event document, so it is inserted atomically with the event.
+	Outbox bool `mapstructure:"outbox"`
 }
 
 // Validate ensures mongo config is correct
@@ -75,13 +88,244 @@ func (m *Mongo) init(ctx context.Context) error {
 	m.logger.Debug("successfully connected to mongo")
 
 	m.client = client
+
+	m.logger.Debug("attempting to create indexes")
+	err = m.createIndexes(ctx)
+	if err != nil {
+		m.logger.Error("failed to create indexes", zap.Error(err))
+		return NewConnectionError("mongo", err)
+	}
+	m.logger.Debug("successfully created indexes")
+
 	return nil
 }
 
-// Append puts an event into mongo and implements the interface PutEvent
-func (m *Mongo) Append(ctx context.Context, event *event.Event) error {
+func (m *Mongo) createIndexes(ctx context.Context) error {
+	coll := m.client.Database(m.config.Database).Collection(m.config.Collection)
+
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "id", Value: 1},
+				{Key: "source", Value: 1},
+			},
+			Options: options.Index().
+				SetName("id_source").
+				SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: mongoMetadataKey + ".stream", Value: 1},
+				{Key: mongoMetadataKey + ".version", Value: 1},
+			},
+			Options: options.Index().
+				SetName("stream_version").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.D{
+					{Key: mongoMetadataKey + ".stream", Value: bson.D{{Key: "$exists", Value: true}}},
+				}),
+		},
+		{
+			Keys: bson.D{
+				{Key: mongoMetadataKey + ".pending", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+			Options: options.Index().
+				SetName("pending").
+				SetPartialFilterExpression(bson.D{
+					{Key: mongoMetadataKey + ".pending", Value: true},
+				}),
+		},
+	})
+	return err
+}
+
+// Append puts an event into mongo, returning its position in the log, and implements the interface AppendOnly.
+// Each event is inserted at the position after the latest event, so positions are committed in order
+// without gaps. When a concurrent insert takes the same position, the event is inserted at the next one
+// for as long as it takes, since the concurrent insert succeeded. Stream versions are kept gap-free by a
+// unique index on the stream and version.
+func (m *Mongo) Append(ctx context.Context, event *event.Event, expectedVersion uint64) (uint64, error) {
+	attrs, err := m.marshalEvent(event)
+	if err != nil {
+		return 0, err
+	}
+
+	var position uint64
+	err = retryAppend(ctx, "mongo", func() (bool, error) {
+		var err error
+		position, err = m.insert(ctx, event, attrs, expectedVersion)
+		if mongoPositionTaken(err) {
+			m.logger.Debug("position was taken by a concurrently inserted event",
+				zap.String("event_id", event.ID()),
+				zap.String("event_type", event.Type()),
+				zap.String("event_source", event.Source()),
+				zap.String("event_subject", event.Subject()),
+			)
+			return true, errPositionTaken
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			// either the same event or another event in the stream was inserted
+			// concurrently, so run the checks again
+			m.logger.Debug("event conflicted with a concurrently inserted event",
+				zap.String("event_id", event.ID()),
+				zap.String("event_type", event.Type()),
+				zap.String("event_source", event.Source()),
+				zap.String("event_subject", event.Subject()),
+			)
+			return true, err
+		}
+		return false, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return position, nil
+}
+
+// AppendBatch puts events into mongo within a single transaction, so either all or none of them are
+// stored, and implements the interface BatchAppendOnly. The positions assigned to the events are
+// returned in the same order as the events. Transactions require mongo to be deployed as a replica set.
+func (m *Mongo) AppendBatch(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+	attrs := make([]bson.D, len(events))
+	for i, ev := range events {
+		doc, err := m.marshalEvent(ev)
+		if err != nil {
+			return nil, err
+		}
+		attrs[i] = doc
+	}
+
+	m.logger.Debug("attempting to start session", zap.Int("events", len(events)))
+	sess, err := m.client.StartSession()
+	if err != nil {
+		m.logger.Error("failed to start session", zap.Error(err))
+		return nil, NewConnectionError("mongo", err)
+	}
+	defer sess.EndSession(ctx)
+
+	var positions []uint64
+	err = retryAppend(ctx, "mongo", func() (bool, error) {
+		var err error
+		positions, err = m.insertBatch(ctx, sess, events, attrs, expectedVersion)
+		if mongoPositionTaken(err) {
+			m.logger.Debug("positions were taken by a concurrently inserted event", zap.Int("events", len(events)))
+			return true, errPositionTaken
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			m.logger.Debug("batch conflicted with a concurrently inserted event", zap.Int("events", len(events)))
+			return true, err
+		}
+		return false, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.logger.Info("successfully inserted batch", zap.Int("events", len(events)))
+	return positions, nil
+}
+
+// insertBatch checks the events have not already been inserted and that their streams are at the expected
+// version, then inserts them in a transaction at the positions after the latest event. The checks are made
+// before the transaction starts, so that its snapshot is taken right before the events are inserted and a
+// concurrent insert can only take their positions in between, while the unique indexes catch any event
+// inserted concurrently since the checks.
+func (m *Mongo) insertBatch(ctx context.Context, sess mongo.Session, events []*event.Event, attrs []bson.D, expectedVersion uint64) ([]uint64, error) {
+	positions := make([]uint64, len(events))
+
+	var pending []int
+	batched := make(map[eventKey]int)
+	aliases := make(map[int]int)
+	for i, ev := range events {
+		existing, err := m.find(ctx, ev.Source(), ev.ID())
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			if !sameEvent(existing.Event, ev) {
+				return nil, NewDuplicateEventError(ev.Source(), ev.ID())
+			}
+			positions[i] = existing.Position
+			continue
+		}
+
+		key := eventKey{source: ev.Source(), id: ev.ID()}
+		if j, ok := batched[key]; ok {
+			if !sameEvent(events[j], ev) {
+				return nil, NewDuplicateEventError(ev.Source(), ev.ID())
+			}
+			aliases[i] = j
+			continue
+		}
+		batched[key] = i
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return positions, nil
+	}
+
+	current := make(map[string]uint64)
+	for _, ev := range events {
+		stream := StreamOf(ev, m.config.StreamExtension)
+		if _, ok := current[stream]; ok {
+			continue
+		}
+		version, err := m.streamVersion(ctx, stream)
+		if err != nil {
+			return nil, NewGetError("mongo", "stream version", err)
+		}
+		if expectedVersion != AnyVersion && version != expectedVersion {
+			return nil, NewVersionConflictError(stream, expectedVersion, version)
+		}
+		current[stream] = version
+	}
+
+	mds := make([]mongoMetadata, len(pending))
+	for n, i := range pending {
+		mds[n] = newMongoMetadata(events[i])
+		mds[n].Pending = m.config.Outbox
+		if stream := StreamOf(events[i], m.config.StreamExtension); stream != "" {
+			current[stream]++
+			mds[n].Stream = stream
+			mds[n].Version = int64(current[stream])
+		}
+	}
+
 	coll := m.client.Database(m.config.Database).Collection(m.config.Collection)
+	_, err := sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		last, err := m.lastPosition(sessCtx)
+		if err != nil {
+			return nil, NewGetError("mongo", "position", err)
+		}
 
+		docs := make([]interface{}, len(pending))
+		for n, i := range pending {
+			positions[i] = last + uint64(n) + 1
+
+			doc := make(bson.D, 0, len(attrs[i])+2)
+			doc = append(doc, bson.E{Key: "_id", Value: int64(positions[i])})
+			doc = append(doc, attrs[i]...)
+			doc = append(doc, bson.E{Key: mongoMetadataKey, Value: mds[n]})
+			docs[n] = doc
+		}
+		_, err = coll.InsertMany(sessCtx, docs)
+		if err != nil {
+			return nil, NewPutError("mongo", "event", err)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		m.logger.Error("failed to insert batch", zap.Error(err), zap.Int("events", len(events)))
+		return nil, err
+	}
+
+	for i, j := range aliases {
+		positions[i] = positions[j]
+	}
+	return positions, nil
+}
+
+func (m *Mongo) marshalEvent(event *event.Event) (bson.D, error) {
 	m.logger.Debug("attempting to marshal event to json",
 		zap.String("event_id", event.ID()),
 		zap.String("event_type", event.Type()),
@@ -97,7 +341,7 @@ func (m *Mongo) Append(ctx context.Context, event *event.Event) error {
 			zap.String("event_source", event.Source()),
 			zap.String("event_subject", event.Subject()),
 		)
-		return NewMarshalError("*event.Event", "json", err)
+		return nil, NewMarshalError("*event.Event", "json", err)
 	}
 	m.logger.Debug("successfully marshaled event to json",
 		zap.String("event_id", event.ID()),
@@ -112,7 +356,7 @@ func (m *Mongo) Append(ctx context.Context, event *event.Event) error {
 		zap.String("event_source", event.Source()),
 		zap.String("event_subject", event.Subject()),
 	)
-	var bdoc interface{}
+	var bdoc bson.D
 	err = bson.UnmarshalExtJSON(raw, true, &bdoc)
 	if err != nil {
 		m.logger.Error("failed to marshal json to bson",
@@ -122,7 +366,7 @@ func (m *Mongo) Append(ctx context.Context, event *event.Event) error {
 			zap.String("event_source", event.Source()),
 			zap.String("event_subject", event.Subject()),
 		)
-		return NewMarshalError("json", "bson", err)
+		return nil, NewMarshalError("json", "bson", err)
 	}
 	m.logger.Debug("successfully marshaled json to bson",
 		zap.String("event_id", event.ID()),
@@ -131,13 +375,113 @@ func (m *Mongo) Append(ctx context.Context, event *event.Event) error {
 		zap.String("event_subject", event.Subject()),
 	)
 
+	return bdoc, nil
+}
+
+// insert puts a single event into mongo after checking it has not already been inserted and
+// that its stream is at the expected version
+func (m *Mongo) insert(ctx context.Context, event *event.Event, attrs bson.D, expectedVersion uint64) (uint64, error) {
+	coll := m.client.Database(m.config.Database).Collection(m.config.Collection)
+
+	existing, err := m.find(ctx, event.Source(), event.ID())
+	if err != nil {
+		m.logger.Error("failed to check for existing event",
+			zap.Error(err),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return 0, err
+	}
+	if existing != nil {
+		if !sameEvent(existing.Event, event) {
+			m.logger.Warn("event already exists with different content",
+				zap.Uint64("position", existing.Position),
+				zap.String("event_id", event.ID()),
+				zap.String("event_type", event.Type()),
+				zap.String("event_source", event.Source()),
+				zap.String("event_subject", event.Subject()),
+			)
+			return 0, NewDuplicateEventError(event.Source(), event.ID())
+		}
+		m.logger.Info("event has already been inserted",
+			zap.Uint64("position", existing.Position),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return existing.Position, nil
+	}
+
+	stream := StreamOf(event, m.config.StreamExtension)
+	md := newMongoMetadata(event)
+	md.Pending = m.config.Outbox
+	if stream != "" || expectedVersion != AnyVersion {
+		version, err := m.streamVersion(ctx, stream)
+		if err != nil {
+			m.logger.Error("failed to get stream version",
+				zap.Error(err),
+				zap.String("stream", stream),
+				zap.String("event_id", event.ID()),
+				zap.String("event_type", event.Type()),
+				zap.String("event_source", event.Source()),
+				zap.String("event_subject", event.Subject()),
+			)
+			return 0, NewGetError("mongo", "stream version", err)
+		}
+		if expectedVersion != AnyVersion && version != expectedVersion {
+			m.logger.Warn("stream is not at expected version",
+				zap.String("stream", stream),
+				zap.Uint64("expected_version", expectedVersion),
+				zap.Uint64("actual_version", version),
+				zap.String("event_id", event.ID()),
+				zap.String("event_type", event.Type()),
+				zap.String("event_source", event.Source()),
+				zap.String("event_subject", event.Subject()),
+			)
+			return 0, NewVersionConflictError(stream, expectedVersion, version)
+		}
+		if stream != "" {
+			md.Stream = stream
+			md.Version = int64(version + 1)
+		}
+	}
+
+	m.logger.Debug("attempting to get last position",
+		zap.String("event_id", event.ID()),
+		zap.String("event_type", event.Type()),
+		zap.String("event_source", event.Source()),
+		zap.String("event_subject", event.Subject()),
+	)
+	last, err := m.lastPosition(ctx)
+	if err != nil {
+		m.logger.Error("failed to get last position",
+			zap.Error(err),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return 0, NewGetError("mongo", "position", err)
+	}
+	// the position is the primary key, so only one of any concurrent inserts after the
+	// same event succeeds and the others fail with a duplicate key error
+	position := last + 1
+
+	doc := make(bson.D, 0, len(attrs)+2)
+	doc = append(doc, bson.E{Key: "_id", Value: int64(position)})
+	doc = append(doc, attrs...)
+	doc = append(doc, bson.E{Key: mongoMetadataKey, Value: md})
+
 	m.logger.Debug("attempting to insert event",
 		zap.String("event_id", event.ID()),
 		zap.String("event_type", event.Type()),
 		zap.String("event_source", event.Source()),
 		zap.String("event_subject", event.Subject()),
 	)
-	_, err = coll.InsertOne(ctx, bdoc)
+	_, err = coll.InsertOne(ctx, doc)
 	if err != nil {
 		m.logger.Error("failed to insert event",
 			zap.Error(err),
@@ -146,14 +490,487 @@ func (m *Mongo) Append(ctx context.Context, event *event.Event) error {
 			zap.String("event_source", event.Source()),
 			zap.String("event_subject", event.Subject()),
 		)
-		return NewPutError("mongo", "event", err)
+		return 0, NewPutError("mongo", "event", err)
 	}
 	m.logger.Info("successfully inserted event",
+		zap.Uint64("position", position),
 		zap.String("event_id", event.ID()),
 		zap.String("event_type", event.Type()),
 		zap.String("event_source", event.Source()),
 		zap.String("event_subject", event.Subject()),
 	)
 
+	return position, nil
+}
+
+// find returns the event with the given source and id, or nil if it does not exist
+func (m *Mongo) find(ctx context.Context, source, id string) (*Record, error) {
+	coll := m.client.Database(m.config.Database).Collection(m.config.Collection)
+
+	filter := bson.D{
+		{Key: "id", Value: id},
+		{Key: "source", Value: source},
+	}
+	opts := options.FindOne().
+		SetProjection(bson.D{{Key: mongoMetadataKey, Value: 0}})
+
+	raw, err := coll.FindOne(ctx, filter, opts).DecodeBytes()
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, NewGetError("mongo", "event", err)
+	}
+	return decodeMongoRecord(raw)
+}
+
+// streamVersion returns the version of the latest event in the stream
+func (m *Mongo) streamVersion(ctx context.Context, stream string) (uint64, error) {
+	if stream == "" {
+		return 0, nil
+	}
+
+	coll := m.client.Database(m.config.Database).Collection(m.config.Collection)
+
+	filter := bson.D{{Key: mongoMetadataKey + ".stream", Value: stream}}
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: mongoMetadataKey + ".version", Value: -1}}).
+		SetProjection(bson.D{{Key: mongoMetadataKey, Value: 1}})
+
+	var doc struct {
+		Metadata mongoMetadata `bson:"_evrys"`
+	}
+	err := coll.FindOne(ctx, filter, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return uint64(doc.Metadata.Version), nil
+}
+
+// mongoDuplicateKeyCode is the code of the write error of an insert which violates a unique index
+const mongoDuplicateKeyCode = 11000
+
+// mongoPositionTaken reports whether an insert failed because the position of an event, which is its
+// _id, was taken by a concurrent insert, rather than because of the other unique indexes
+func mongoPositionTaken(err error) bool {
+	var writeErrs []mongo.WriteError
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		writeErrs = we.WriteErrors
+	}
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) {
+		for _, e := range bwe.WriteErrors {
+			writeErrs = append(writeErrs, e.WriteError)
+		}
+	}
+	for _, e := range writeErrs {
+		if e.Code == mongoDuplicateKeyCode && strings.Contains(e.Message, " index: _id_ ") {
+			return true
+		}
+	}
+	return false
+}
+
+// lastPosition returns the position of the latest event
+func (m *Mongo) lastPosition(ctx context.Context) (uint64, error) {
+	coll := m.client.Database(m.config.Database).Collection(m.config.Collection)
+
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetProjection(bson.D{{Key: "_id", Value: 1}})
+
+	var doc struct {
+		Position int64 `bson:"_id"`
+	}
+	err := coll.FindOne(ctx, bson.D{}, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return uint64(doc.Position), nil
+}
+
+// mongoCheckpointsCollection holds a checkpoint document per consumer group of each event collection
+const mongoCheckpointsCollection = "checkpoints"
+
+func (m *Mongo) checkpointID(group string) bson.D {
+	return bson.D{
+		{Key: "collection", Value: m.config.Collection},
+		{Key: "group", Value: group},
+	}
+}
+
+// Checkpoint returns the position a consumer group has committed and implements the interface Checkpointer
+func (m *Mongo) Checkpoint(ctx context.Context, group string) (uint64, error) {
+	coll := m.client.Database(m.config.Database).Collection(mongoCheckpointsCollection)
+
+	var checkpoint struct {
+		Position int64 `bson:"position"`
+	}
+	err := coll.FindOne(ctx, bson.D{{Key: "_id", Value: m.checkpointID(group)}}).Decode(&checkpoint)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		m.logger.Error("failed to get checkpoint", zap.Error(err), zap.String("group", group))
+		return 0, NewGetError("mongo", "checkpoint", err)
+	}
+	return uint64(checkpoint.Position), nil
+}
+
+// Commit moves the checkpoint of a consumer group forward to the position and implements the interface Checkpointer
+func (m *Mongo) Commit(ctx context.Context, group string, position uint64) error {
+	coll := m.client.Database(m.config.Database).Collection(mongoCheckpointsCollection)
+
+	filter := bson.D{{Key: "_id", Value: m.checkpointID(group)}}
+	update := bson.D{{Key: "$max", Value: bson.D{{Key: "position", Value: int64(position)}}}}
+	_, err := coll.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// a concurrent commit created the checkpoint first, so it now only needs updating
+		_, err = coll.UpdateOne(ctx, filter, update)
+	}
+	if err != nil {
+		m.logger.Error("failed to commit checkpoint", zap.Error(err), zap.String("group", group), zap.Uint64("position", position))
+		return NewPutError("mongo", "checkpoint", err)
+	}
 	return nil
 }
+
+// Get returns the event with the given source and id using the unique index on them, and implements the interface Gettable
+func (m *Mongo) Get(ctx context.Context, source, id string) (*Record, error) {
+	rec, err := m.find(ctx, source, id)
+	if err != nil {
+		m.logger.Error("failed to find event", zap.Error(err), zap.String("event_id", id), zap.String("event_source", source))
+		return nil, err
+	}
+	if rec == nil {
+		return nil, NewEventNotFoundError(source, id)
+	}
+	return rec, nil
+}
+
+// ReadStream returns an Iterator over the events of the stream in the order of their versions and implements the
+// interface StreamReader. The events are read using the partial index on the stream and version of event documents.
+func (m *Mongo) ReadStream(ctx context.Context, stream string, opts ReadStreamOptions) (Iterator, error) {
+	coll := m.client.Database(m.config.Database).Collection(m.config.Collection)
+
+	op, order := "$gte", 1
+	if opts.Backward {
+		op, order = "$lte", -1
+	}
+	filter := bson.D{
+		{Key: mongoMetadataKey + ".stream", Value: stream},
+		{Key: mongoMetadataKey + ".version", Value: bson.D{{Key: op, Value: opts.from()}}},
+	}
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: mongoMetadataKey + ".version", Value: order}}).
+		SetLimit(opts.limit()).
+		SetProjection(bson.D{
+			{Key: mongoMetadataKey + ".time", Value: 0},
+			{Key: mongoMetadataKey + ".stream", Value: 0},
+			{Key: mongoMetadataKey + ".pending", Value: 0},
+		})
+
+	m.logger.Debug("attempting to find stream events", zap.String("stream", stream))
+	cursor, err := coll.Find(ctx, filter, findOpts)
+	if err != nil {
+		m.logger.Error("failed to find stream events", zap.Error(err), zap.String("stream", stream))
+		return nil, NewGetError("mongo", "event", err)
+	}
+	m.logger.Debug("successfully found stream events", zap.String("stream", stream))
+
+	return &mongoIterator{
+		logger: m.logger,
+		cursor: cursor,
+	}, nil
+}
+
+// Pending returns up to limit events which are pending dispatch, in the order they were appended, using the
+// partial index on the pending field of event documents, and implements the interface Outbox
+func (m *Mongo) Pending(ctx context.Context, limit int) ([]*Record, error) {
+	if !m.config.Outbox {
+		return nil, NewOutboxDisabledError("mongo")
+	}
+	coll := m.client.Database(m.config.Database).Collection(m.config.Collection)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.D{{Key: mongoMetadataKey, Value: 0}})
+
+	cursor, err := coll.Find(ctx, bson.D{{Key: mongoMetadataKey + ".pending", Value: true}}, opts)
+	if err != nil {
+		m.logger.Error("failed to find pending events", zap.Error(err))
+		return nil, NewGetError("mongo", "pending events", err)
+	}
+
+	iter := &mongoIterator{
+		logger: m.logger,
+		cursor: cursor,
+	}
+	defer iter.Close(ctx)
+
+	var records []*Record
+	for {
+		rec, err := iter.Next(ctx)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// Dispatched removes the pending field from the documents of the events and implements the interface Outbox
+func (m *Mongo) Dispatched(ctx context.Context, records ...*Record) error {
+	if !m.config.Outbox {
+		return NewOutboxDisabledError("mongo")
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	coll := m.client.Database(m.config.Database).Collection(m.config.Collection)
+
+	positions := make(bson.A, len(records))
+	for i, rec := range records {
+		positions[i] = int64(rec.Position)
+	}
+
+	_, err := coll.UpdateMany(
+		ctx,
+		bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: positions}}}},
+		bson.D{{Key: "$unset", Value: bson.D{{Key: mongoMetadataKey + ".pending", Value: ""}}}},
+	)
+	if err != nil {
+		m.logger.Error("failed to mark events as dispatched", zap.Error(err), zap.Int("events", len(records)))
+		return NewPutError("mongo", "outbox", err)
+	}
+	return nil
+}
+
+// Iterate returns an Iterator over the events in mongo which match the filter in the order they were appended and implements the interface Iterable
+func (m *Mongo) Iterate(ctx context.Context, filter Filter) (Iterator, error) {
+	coll := m.client.Database(m.config.Database).Collection(m.config.Collection)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetProjection(bson.D{{Key: mongoMetadataKey, Value: 0}})
+
+	m.logger.Debug("attempting to find events")
+	cursor, err := coll.Find(ctx, newMongoFilter(filter), opts)
+	if err != nil {
+		m.logger.Error("failed to find events", zap.Error(err))
+		return nil, NewGetError("mongo", "event", err)
+	}
+	m.logger.Debug("successfully found events")
+
+	return &mongoIterator{
+		logger: m.logger,
+		cursor: cursor,
+	}, nil
+}
+
+// Subscribe returns an Iterator over the events in mongo which match the filter, which waits for matching events
+// to be appended once it has returned every stored event, and implements the interface Subscribable.
+//
+// Each subscription watches a change stream of inserts into the collection, so it is woken once events are
+// appended, including by other processes. Change streams require a replica set or sharded cluster, so the
+// subscription falls back to polling if the change stream can not be opened or fails.
+func (m *Mongo) Subscribe(ctx context.Context, filter Filter) (Iterator, error) {
+	coll := m.client.Database(m.config.Database).Collection(m.config.Collection)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "operationType", Value: "insert"}}}},
+		{{Key: "$project", Value: bson.D{{Key: "documentKey", Value: 1}}}},
+	}
+
+	m.logger.Debug("attempting to watch for appended events")
+	cs, err := coll.Watch(ctx, pipeline)
+	if err != nil {
+		m.logger.Warn("failed to watch for appended events, so falling back to polling", zap.Error(err))
+		return newSubscription(m, filter, nil, nil), nil
+	}
+	m.logger.Debug("successfully watching for appended events")
+
+	signal := new(appendSignal)
+	wctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer cs.Close(context.Background())
+
+		for cs.Next(wctx) {
+			signal.broadcast()
+		}
+		if err := cs.Err(); err != nil && wctx.Err() == nil {
+			m.logger.Warn("stopped watching for appended events, so falling back to polling", zap.Error(err))
+		}
+	}()
+
+	return newSubscription(m, filter, signal, func() {
+		cancel()
+		<-done
+	}), nil
+}
+
+type mongoIterator struct {
+	logger *zap.Logger
+	cursor *mongo.Cursor
+}
+
+// Next decodes the next event from the mongo cursor and implements the interface Iterator
+func (it *mongoIterator) Next(ctx context.Context) (*Record, error) {
+	if !it.cursor.Next(ctx) {
+		err := it.cursor.Err()
+		if err != nil {
+			it.logger.Error("failed to read next event", zap.Error(err))
+			return nil, NewGetError("mongo", "event", err)
+		}
+		return nil, io.EOF
+	}
+
+	rec, err := decodeMongoRecord(it.cursor.Current)
+	if err != nil {
+		it.logger.Error("failed to decode event", zap.Error(err))
+		return nil, err
+	}
+	it.logger.Debug("successfully read event",
+		zap.Uint64("position", rec.Position),
+		zap.String("event_id", rec.Event.ID()),
+		zap.String("event_type", rec.Event.Type()),
+		zap.String("event_source", rec.Event.Source()),
+		zap.String("event_subject", rec.Event.Subject()),
+	)
+
+	return rec, nil
+}
+
+// Close closes the underlying mongo cursor and implements the interface Iterator
+func (it *mongoIterator) Close(ctx context.Context) error {
+	return it.cursor.Close(ctx)
+}
+
+// decodeMongoRecord converts a stored document back into a Record. The document is expected to have been
+// projected without its metadata, other than the version of its stream which is set on the Record if present.
+func decodeMongoRecord(raw bson.Raw) (*Record, error) {
+	var doc bson.D
+	err := bson.Unmarshal(raw, &doc)
+	if err != nil {
+		return nil, NewMarshalError("bson", "bson.D", err)
+	}
+
+	rec := new(Record)
+	attrs := make(bson.D, 0, len(doc))
+	for _, elem := range doc {
+		if elem.Key == mongoMetadataKey {
+			md, _ := elem.Value.(bson.D)
+			for _, field := range md {
+				if version, ok := field.Value.(int64); ok && field.Key == "version" {
+					rec.Version = uint64(version)
+				}
+			}
+			continue
+		}
+		if elem.Key != "_id" {
+			attrs = append(attrs, elem)
+			continue
+		}
+		position, ok := elem.Value.(int64)
+		if !ok {
+			return nil, NewMarshalError("bson", "position", fmt.Errorf("unexpected _id type %T", elem.Value))
+		}
+		rec.Position = uint64(position)
+	}
+
+	b, err := bson.MarshalExtJSON(attrs, false, false)
+	if err != nil {
+		return nil, NewMarshalError("bson", "json", err)
+	}
+
+	var ev event.Event
+	err = ev.UnmarshalJSON(b)
+	if err != nil {
+		return nil, NewMarshalError("json", "*event.Event", err)
+	}
+	rec.Event = &ev
+	return rec, nil
+}
+
+// mongoMetadataKey is the field events are stored under alongside their attributes.
+// Cloudevent attribute names are restricted to lowercase letters and digits, so it
+// can never collide with an extension attribute.
+const mongoMetadataKey = "_evrys"
+
+// mongoMetadata holds fields derived from an event which are only used for querying
+type mongoMetadata struct {
+	Time    *time.Time `bson:"time,omitempty"`
+	Stream  string     `bson:"stream,omitempty"`
+	Version int64      `bson:"version,omitempty"`
+	Pending bool       `bson:"pending,omitempty"`
+}
+
+func newMongoMetadata(ev *event.Event) mongoMetadata {
+	var md mongoMetadata
+	if t := ev.Time(); !t.IsZero() {
+		md.Time = &t
+	}
+	return md
+}
+
+func newMongoFilter(filter Filter) bson.D {
+	doc := bson.D{}
+	if filter.AfterPosition > 0 {
+		doc = append(doc, bson.E{Key: "_id", Value: bson.D{{Key: "$gt", Value: int64(filter.AfterPosition)}}})
+	}
+	if len(filter.Types) > 0 {
+		doc = append(doc, bson.E{Key: "type", Value: bson.D{{Key: "$in", Value: filter.Types}}})
+	}
+	if len(filter.Sources) > 0 {
+		doc = append(doc, bson.E{Key: "source", Value: bson.D{{Key: "$in", Value: filter.Sources}}})
+	}
+	if len(filter.Subjects) > 0 {
+		doc = append(doc, bson.E{Key: "subject", Value: bson.D{{Key: "$in", Value: filter.Subjects}}})
+	}
+
+	timeRange := bson.D{}
+	if !filter.StartTime.IsZero() {
+		timeRange = append(timeRange, bson.E{Key: "$gte", Value: filter.StartTime})
+	}
+	if !filter.EndTime.IsZero() {
+		timeRange = append(timeRange, bson.E{Key: "$lt", Value: filter.EndTime})
+	}
+	if len(timeRange) > 0 {
+		doc = append(doc, bson.E{Key: mongoMetadataKey + ".time", Value: timeRange})
+	}
+
+	names := make([]string, 0, len(filter.Extensions))
+	for name := range filter.Extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		doc = append(doc, bson.E{Key: name, Value: bson.D{{Key: "$in", Value: extensionValues(filter.Extensions[name])}}})
+	}
+	return doc
+}
+
+// extensionValues returns every typed form an extension attribute with
+// the given string value could have been stored as.
+func extensionValues(s string) []interface{} {
+	values := []interface{}{s}
+	if i, err := strconv.ParseInt(s, 10, 32); err == nil {
+		values = append(values, int32(i))
+	}
+	if b, err := strconv.ParseBool(s); err == nil {
+		values = append(values, b)
+	}
+	return values
+}
@@ -2,6 +2,8 @@ package eventstore
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"testing"
 	"time"
 
@@ -138,22 +140,84 @@ func TestNewMongoEventStoreImpl(t *testing.T) {
 			Database:   "testdb",
 			Collection: "testcoll",
 		}
-		m, err := NewMongo(context.TODO(), conf)
-		req.NoError(err, "no error expected creating mongo instance")
-
-		_event := event.New()
-		curTime := time.Now().UTC()
-		_event.SetID("some_random_id")
-		_event.SetSubject("test")
-		_event.SetSource("mongo_test")
-		_event.SetTime(curTime)
-		_event.SetSpecVersion(event.CloudEventsVersionV1)
-		_event.SetType("test")
-		_event.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": "world"})
-		err = m.Append(context.TODO(), &_event)
-		var connErr *PutError
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, err := NewMongo(ctx, conf)
+		var connErr *ConnectionError
 		req.ErrorAs(err, &connErr, "expected connection error")
 	})
+
+	t.Run("invalid stream extension", func(t *testing.T) {
+		conf := MongoConfig{
+			Host:            "something",
+			Port:            "1234",
+			Username:        "username",
+			Password:        "dfasdfad",
+			Database:        "dfasdfas",
+			Collection:      "dfads",
+			StreamExtension: "Not_Valid",
+		}
+		_, err := NewMongo(context.TODO(), conf)
+		req.ErrorAs(err, &ValidationErrors, "expected validation error")
+	})
+}
+
+func TestNewMongoFilter(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("empty filter", func(t *testing.T) {
+		req.Equal(bson.D{}, newMongoFilter(Filter{}), "empty filter should match everything")
+	})
+
+	t.Run("after position", func(t *testing.T) {
+		expected := bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$gt", Value: int64(10)}}},
+		}
+		req.Equal(expected, newMongoFilter(Filter{AfterPosition: 10}), "filter not expected value")
+	})
+
+	t.Run("attribute filters", func(t *testing.T) {
+		filter := Filter{
+			Types:    []string{"a", "b"},
+			Sources:  []string{"c"},
+			Subjects: []string{"d"},
+		}
+		expected := bson.D{
+			{Key: "type", Value: bson.D{{Key: "$in", Value: []string{"a", "b"}}}},
+			{Key: "source", Value: bson.D{{Key: "$in", Value: []string{"c"}}}},
+			{Key: "subject", Value: bson.D{{Key: "$in", Value: []string{"d"}}}},
+		}
+		req.Equal(expected, newMongoFilter(filter), "filter not expected value")
+	})
+
+	t.Run("time range", func(t *testing.T) {
+		start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		end := start.Add(time.Hour)
+		expected := bson.D{
+			{Key: "_evrys.time", Value: bson.D{
+				{Key: "$gte", Value: start},
+				{Key: "$lt", Value: end},
+			}},
+		}
+		req.Equal(expected, newMongoFilter(Filter{StartTime: start, EndTime: end}), "filter not expected value")
+	})
+
+	t.Run("extensions", func(t *testing.T) {
+		filter := Filter{
+			Extensions: map[string]string{
+				"tenant":  "acme",
+				"retries": "3",
+				"urgent":  "true",
+			},
+		}
+		expected := bson.D{
+			{Key: "retries", Value: bson.D{{Key: "$in", Value: []interface{}{"3", int32(3)}}}},
+			{Key: "tenant", Value: bson.D{{Key: "$in", Value: []interface{}{"acme"}}}},
+			{Key: "urgent", Value: bson.D{{Key: "$in", Value: []interface{}{"true", true}}}},
+		}
+		req.Equal(expected, newMongoFilter(filter), "filter not expected value")
+	})
 }
 
 func TestMongoIntegration(t *testing.T) {
@@ -196,6 +260,7 @@ func TestMongoIntegration(t *testing.T) {
 		Password:   "example",
 		Database:   db,
 		Collection: collName,
+		Outbox:     true,
 	}
 
 	mongoImpl, err := NewMongo(ctx, config)
@@ -214,8 +279,9 @@ func TestMongoIntegration(t *testing.T) {
 	_event.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": "world"})
 
 	// actual test
-	err = mongoImpl.Append(ctx, &_event)
+	position, err := mongoImpl.Append(ctx, &_event, AnyVersion)
 	req.NoError(err, "failed to put event")
+	req.Equal(uint64(1), position, "position not expected value")
 
 	coll := client.Database(db).Collection(collName)
 	filter := bson.D{{Key: "id", Value: id}}
@@ -272,4 +338,261 @@ func TestMongoIntegration(t *testing.T) {
 	}
 	req.True(idCheck && specVersionCheck && sourceCheck && typeCheck && subjectCheck && dataContentTypeCheck && timeCheck && dataCheck,
 		"all values have not been verified")
+
+	// iterate
+	iter, err := mongoImpl.Iterate(ctx, Filter{})
+	req.NoError(err, "failed to iterate events")
+	defer iter.Close(ctx)
+
+	rec, err := iter.Next(ctx)
+	req.NoError(err, "failed to read event")
+	req.Equal(position, rec.Position, "position not expected value")
+
+	ev := rec.Event
+	req.Equal(id, ev.ID(), "id not expected value")
+	req.Equal("mongo_test", ev.Source(), "source not expected value")
+	req.Equal("test", ev.Type(), "type not expected value")
+	req.Equal("test", ev.Subject(), "subject not expected value")
+	req.Equal(curTime, ev.Time(), "time is not equal")
+	req.JSONEq(`{"hello":"world"}`, string(ev.Data()), "data not expected value")
+
+	_, err = iter.Next(ctx)
+	req.ErrorIs(err, io.EOF, "expected no more events")
+
+	// iterate with filter
+	filtered, err := mongoImpl.Iterate(ctx, Filter{
+		Types:     []string{"test"},
+		StartTime: curTime.Add(-time.Minute),
+		EndTime:   curTime.Add(time.Minute),
+	})
+	req.NoError(err, "failed to iterate filtered events")
+	defer filtered.Close(ctx)
+
+	rec, err = filtered.Next(ctx)
+	req.NoError(err, "failed to read filtered event")
+	req.Equal(id, rec.Event.ID(), "id not expected value")
+
+	excluded, err := mongoImpl.Iterate(ctx, Filter{Types: []string{"other"}})
+	req.NoError(err, "failed to iterate filtered events")
+	defer excluded.Close(ctx)
+
+	_, err = excluded.Next(ctx)
+	req.ErrorIs(err, io.EOF, "expected event to be filtered out")
+
+	// get
+	got, err := mongoImpl.Get(ctx, "mongo_test", id)
+	req.NoError(err, "failed to get event")
+	req.Equal(position, got.Position, "position not expected value")
+	req.Equal(curTime, got.Event.Time(), "time is not equal")
+	req.JSONEq(`{"hello":"world"}`, string(got.Event.Data()), "data not expected value")
+
+	_, err = mongoImpl.Get(ctx, "mongo_test", "missing_id")
+	var notFoundErr *EventNotFoundError
+	req.ErrorAs(err, &notFoundErr, "expected event not found error")
+
+	// outbox
+	pending, err := mongoImpl.Pending(ctx, 10)
+	req.NoError(err, "failed to get pending events")
+	req.Len(pending, 1, "unexpected number of pending events")
+	req.Equal(position, pending[0].Position, "position not expected value")
+
+	req.NoError(mongoImpl.Dispatched(ctx, pending...), "failed to mark events as dispatched")
+	pending, err = mongoImpl.Pending(ctx, 10)
+	req.NoError(err, "failed to get pending events")
+	req.Empty(pending, "every event should have been dispatched")
+
+	// checkpoints
+	checkpoint, err := mongoImpl.Checkpoint(ctx, "projector")
+	req.NoError(err, "failed to get checkpoint")
+	req.Zero(checkpoint, "a group which has not committed should be at position 0")
+
+	req.NoError(mongoImpl.Commit(ctx, "projector", 5), "failed to commit checkpoint")
+	req.NoError(mongoImpl.Commit(ctx, "projector", 3), "failed to commit earlier checkpoint")
+	checkpoint, err = mongoImpl.Checkpoint(ctx, "projector")
+	req.NoError(err, "failed to get checkpoint")
+	req.Equal(uint64(5), checkpoint, "checkpoint should not have moved back")
+
+	// idempotency
+	samePosition, err := mongoImpl.Append(ctx, &_event, AnyVersion)
+	req.NoError(err, "re-appending the same event should succeed")
+	req.Equal(position, samePosition, "position not expected value")
+
+	conflicting := _event.Clone()
+	conflicting.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": "there"})
+	_, err = mongoImpl.Append(ctx, &conflicting, AnyVersion)
+	var dupErr *DuplicateEventError
+	req.ErrorAs(err, &dupErr, "expected duplicate event error")
+
+	// positions
+	_event.SetID("another_random_id")
+	nextPosition, err := mongoImpl.Append(ctx, &_event, AnyVersion)
+	req.NoError(err, "failed to put event")
+	req.Equal(position+1, nextPosition, "position not expected value")
+
+	resumed, err := mongoImpl.Iterate(ctx, Filter{AfterPosition: position})
+	req.NoError(err, "failed to iterate events after position")
+	defer resumed.Close(ctx)
+
+	rec, err = resumed.Next(ctx)
+	req.NoError(err, "failed to read event after position")
+	req.Equal(nextPosition, rec.Position, "position not expected value")
+	req.Equal("another_random_id", rec.Event.ID(), "id not expected value")
+
+	_, err = resumed.Next(ctx)
+	req.ErrorIs(err, io.EOF, "expected no more events")
+
+	// stream versions
+	_event.SetID("stale_random_id")
+	_, err = mongoImpl.Append(ctx, &_event, 1)
+	var conflictErr *VersionConflictError
+	req.ErrorAs(err, &conflictErr, "expected version conflict error")
+	req.Equal("test", conflictErr.Stream, "stream not expected value")
+	req.Equal(uint64(2), conflictErr.Actual, "actual version not expected value")
+
+	_, err = mongoImpl.Append(ctx, &_event, 2)
+	req.NoError(err, "failed to put event at expected version")
+
+	_event.SetID("new_stream_id")
+	_event.SetSubject("other")
+	_, err = mongoImpl.Append(ctx, &_event, 0)
+	req.NoError(err, "failed to put event to new stream")
+
+	// subscribe, which polls since change streams require a replica set
+	sub, err := mongoImpl.Subscribe(ctx, Filter{AfterPosition: nextPosition, Subjects: []string{"other"}})
+	req.NoError(err, "failed to subscribe")
+	defer sub.Close(ctx)
+
+	rec, err = sub.Next(ctx)
+	req.NoError(err, "failed to read stored event")
+	req.Equal("new_stream_id", rec.Event.ID(), "id not expected value")
+
+	_event.SetID("subscribed_id")
+	_, err = mongoImpl.Append(ctx, &_event, 1)
+	req.NoError(err, "failed to put event")
+
+	rec, err = sub.Next(ctx)
+	req.NoError(err, "failed to read appended event")
+	req.Equal("subscribed_id", rec.Event.ID(), "id not expected value")
+
+	// read stream
+	appendStreamTestEvents(t, mongoImpl)
+	testReadStream(t, mongoImpl)
+
+	// concurrent appends
+	testSubscribeConcurrentAppends(t, mongoImpl)
+	testIterateConcurrentAppends(t, mongoImpl)
+}
+
+func TestMongoIntegration_AppendBatch(t *testing.T) {
+	// setup
+	req := require.New(t)
+	ctx := context.Background()
+
+	// container init, transactions require a replica set
+	contReq := testcontainers.ContainerRequest{
+		Image:        "mongo:6.0.2",
+		Cmd:          []string{"--replSet", "rs0", "--bind_ip_all"},
+		ExposedPorts: []string{"27017:27017"},
+		WaitingFor:   wait.ForLog("Waiting for connections"),
+	}
+	mongoC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: contReq,
+		Started:          true,
+	})
+	req.NoError(err, "failed to create mongo container")
+	defer mongoC.Terminate(ctx)
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017").SetDirect(true))
+	req.NoError(err, "failed to connect to mongo")
+
+	err = client.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "replSetInitiate", Value: bson.D{
+			{Key: "_id", Value: "rs0"},
+			{Key: "members", Value: bson.A{bson.D{{Key: "_id", Value: 0}, {Key: "host", Value: "localhost:27017"}}}},
+		}},
+	}).Err()
+	req.NoError(err, "failed to initiate replica set")
+
+	req.Eventually(func() bool {
+		return client.Ping(ctx, readpref.Primary()) == nil
+	}, 30*time.Second, 500*time.Millisecond, "replica set never elected a primary")
+
+	err = client.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "createUser", Value: "root"},
+		{Key: "pwd", Value: "example"},
+		{Key: "roles", Value: bson.A{"root"}},
+	}).Err()
+	req.NoError(err, "failed to create user")
+
+	// impl setup
+	config := MongoConfig{
+		Host:       "localhost",
+		Port:       "27017",
+		Username:   "root",
+		Password:   "example",
+		Database:   "testdb",
+		Collection: "testcoll",
+	}
+
+	mongoImpl, err := NewMongo(ctx, config)
+	req.NoError(err, "failed to create mongo event store")
+
+	// data setup
+	newEvent := func(id string) *event.Event {
+		ev := event.New()
+		ev.SetID(id)
+		ev.SetSubject("test")
+		ev.SetSource("mongo_test")
+		ev.SetSpecVersion(event.CloudEventsVersionV1)
+		ev.SetType("test")
+		return &ev
+	}
+
+	// actual test
+	positions, err := mongoImpl.AppendBatch(ctx, []*event.Event{newEvent("1"), newEvent("2")}, 0)
+	req.NoError(err, "failed to put batch")
+	req.Equal([]uint64{1, 2}, positions, "positions not expected value")
+
+	positions, err = mongoImpl.AppendBatch(ctx, []*event.Event{newEvent("1"), newEvent("2")}, 0)
+	req.NoError(err, "re-appending the same batch should succeed")
+	req.Equal([]uint64{1, 2}, positions, "positions not expected value")
+
+	_, err = mongoImpl.AppendBatch(ctx, []*event.Event{newEvent("3"), newEvent("4")}, 0)
+	var conflictErr *VersionConflictError
+	req.ErrorAs(err, &conflictErr, "expected version conflict error")
+
+	conflicting := newEvent("1")
+	conflicting.SetType("other")
+	_, err = mongoImpl.AppendBatch(ctx, []*event.Event{newEvent("5"), conflicting}, AnyVersion)
+	var dupErr *DuplicateEventError
+	req.ErrorAs(err, &dupErr, "expected duplicate event error")
+
+	// none of the failed batches should have been stored
+	iter, err := mongoImpl.Iterate(ctx, Filter{})
+	req.NoError(err, "failed to iterate events")
+	defer iter.Close(ctx)
+
+	var ids []string
+	for {
+		rec, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		req.NoError(err, "failed to read event")
+		ids = append(ids, rec.Event.ID())
+	}
+	req.Equal([]string{"1", "2"}, ids, "only the first batch should have been stored")
+
+	// batches are not given up on while events are appended concurrently
+	appended := make(chan error, 1)
+	go func() {
+		_, err := appendConcurrently(ctx, mongoImpl, "mongo_batch_concurrent_test")
+		appended <- err
+	}()
+	for i := 0; i < concurrentAppends; i++ {
+		batch := []*event.Event{newEvent(fmt.Sprintf("batch-%d-a", i)), newEvent(fmt.Sprintf("batch-%d-b", i))}
+		_, err = mongoImpl.AppendBatch(ctx, batch, AnyVersion)
+		req.NoError(err, "failed to put batch while events are appended concurrently")
+	}
+	req.NoError(<-appended, "failed to put events while batches are appended concurrently")
 }
@@ -0,0 +1,846 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// PostgresConfig defines the configuration to connect to postgres
+type PostgresConfig struct {
+	Host string `mapstructure:"host" validate:"hostname,required"`
+	Port string `mapstructure:"port" validate:"numeric,required"`
+
+	Username string `mapstructure:"username" validate:"required"`
+	Password string `mapstructure:"password" validate:"required"`
+
+	Database string `mapstructure:"database" validate:"required"`
+	Table    string `mapstructure:"table" validate:"required"`
+
+	// SSLMode is passed through to postgres as the sslmode connection parameter
+	SSLMode string `mapstructure:"sslmode" validate:"omitempty,oneof=disable allow prefer require verify-ca verify-full"`
+
+	// StreamExtension is the extension attribute used to group events into streams.
+	// Events are grouped by their subject if it is not set.
+	StreamExtension string `mapstructure:"stream_extension" validate:"omitempty,alphanum,lowercase"`
+
+	// Outbox records every appended event as pending dispatch until it is marked as dispatched.
+	// Pending events are kept in a table named after Table with an "_outbox" suffix, which is
+	// inserted into in the same transaction as the events.
+	Outbox bool `mapstructure:"outbox"`
+}
+
+// Validate ensures postgres config is correct
+func (p *PostgresConfig) Validate() error {
+	return validator.New().Struct(p)
+}
+
+func (p *PostgresConfig) getURI() string {
+	uri := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(p.Username, p.Password),
+		Host:   net.JoinHostPort(p.Host, p.Port),
+		Path:   p.Database,
+	}
+	if p.SSLMode != "" {
+		uri.RawQuery = url.Values{"sslmode": []string{p.SSLMode}}.Encode()
+	}
+	return uri.String()
+}
+
+// Postgres is the event store implementation for postgres
+type Postgres struct {
+	config      PostgresConfig
+	logger      *zap.Logger
+	pool        *pgxpool.Pool
+	table       string
+	outbox      string
+	checkpoints string
+
+	// channel is notified whenever events are appended, once their transaction commits
+	channel string
+}
+
+// NewPostgres constructs and initializes a *Postgres, creating its table and indexes if they do not exist
+func NewPostgres(ctx context.Context, config PostgresConfig) (*Postgres, error) {
+	if ctx == nil {
+		return nil, errors.New("context can not be nil")
+	}
+
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config, %w", err)
+	}
+
+	impl := &Postgres{
+		config:      config,
+		logger:      zap.L().With(zap.String("source", "PostgresEventStoreImpl")),
+		table:       pgx.Identifier{config.Table}.Sanitize(),
+		outbox:      pgx.Identifier{config.Table + "_outbox"}.Sanitize(),
+		checkpoints: pgx.Identifier{config.Table + "_checkpoints"}.Sanitize(),
+		channel:     config.Table + "_appended",
+	}
+
+	err = impl.init(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init, %w", err)
+	}
+
+	return impl, nil
+}
+
+func (p *Postgres) init(ctx context.Context) error {
+	p.logger.Debug("attempting to open connection to postgres")
+	pool, err := pgxpool.New(ctx, p.config.getURI())
+	if err != nil {
+		p.logger.Error("failed to connect to postgres", zap.Error(err))
+		return NewConnectionError("postgres", err)
+	}
+	err = pool.Ping(ctx)
+	if err != nil {
+		pool.Close()
+		p.logger.Error("failed to connect to postgres", zap.Error(err))
+		return NewConnectionError("postgres", err)
+	}
+	p.logger.Debug("successfully connected to postgres")
+
+	p.pool = pool
+
+	p.logger.Debug("attempting to create schema")
+	err = p.createSchema(ctx)
+	if err != nil {
+		p.logger.Error("failed to create schema", zap.Error(err))
+		return NewConnectionError("postgres", err)
+	}
+	p.logger.Debug("successfully created schema")
+
+	return nil
+}
+
+// Close closes every connection to postgres
+func (p *Postgres) Close() error {
+	p.pool.Close()
+	return nil
+}
+
+func (p *Postgres) index(name string) string {
+	return pgx.Identifier{p.config.Table + "_" + name}.Sanitize()
+}
+
+// createSchema creates the events, outbox and checkpoints tables. Events are stored in the cloudevents json format in the
+// data column, with the attributes used for filtering copied out into their own columns.
+func (p *Postgres) createSchema(ctx context.Context) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS ` + p.table + ` (
+			position bigserial PRIMARY KEY,
+			id text NOT NULL,
+			source text NOT NULL,
+			type text NOT NULL,
+			subject text,
+			time timestamptz,
+			stream text,
+			version bigint,
+			data jsonb NOT NULL
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS ` + p.index("id_source") + ` ON ` + p.table + ` (id, source)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS ` + p.index("stream_version") + ` ON ` + p.table + ` (stream, version) WHERE stream IS NOT NULL`,
+		`CREATE INDEX IF NOT EXISTS ` + p.index("type") + ` ON ` + p.table + ` (type)`,
+		`CREATE INDEX IF NOT EXISTS ` + p.index("source") + ` ON ` + p.table + ` (source)`,
+		`CREATE INDEX IF NOT EXISTS ` + p.index("subject") + ` ON ` + p.table + ` (subject)`,
+		`CREATE TABLE IF NOT EXISTS ` + p.outbox + ` (
+			position bigint PRIMARY KEY REFERENCES ` + p.table + ` (position)
+		)`,
+		`CREATE TABLE IF NOT EXISTS ` + p.checkpoints + ` (
+			consumer_group text PRIMARY KEY,
+			position bigint NOT NULL
+		)`,
+	}
+
+	return pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
+		// concurrent CREATE ... IF NOT EXISTS statements can still
+		// conflict, so only let one instance create the schema at a time
+		err := p.lock(ctx, tx)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range statements {
+			_, err := tx.Exec(ctx, stmt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// lock takes a lock on the table which is held until the end of the transaction. Appends hold it so that
+// positions are committed in the order they are allocated, which a bigserial alone does not guarantee.
+// Otherwise a reader could see a later position before an earlier one commits and skip past it.
+func (p *Postgres) lock(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", p.config.Database+"."+p.config.Table)
+	return err
+}
+
+// pgUniqueViolation is the postgres error code for a unique constraint violation
+const pgUniqueViolation = "23505"
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+// Append puts an event into postgres, returning its position in the log, and implements the interface AppendOnly.
+// Positions are allocated from a bigserial column so a failed insert will leave a gap.
+func (p *Postgres) Append(ctx context.Context, event *event.Event, expectedVersion uint64) (uint64, error) {
+	data, err := p.marshalEvent(event)
+	if err != nil {
+		return 0, err
+	}
+
+	var position uint64
+	err = retryAppend(ctx, "postgres", func() (bool, error) {
+		err := pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
+			err := p.lock(ctx, tx)
+			if err != nil {
+				return NewPutError("postgres", "event", err)
+			}
+			position, err = p.insert(ctx, tx, event, data, expectedVersion)
+			return err
+		})
+		if isUniqueViolation(err) {
+			// the event was inserted concurrently by something
+			// other than evrys, so run the checks again
+			p.logger.Debug("event conflicted with a concurrently inserted event",
+				zap.String("event_id", event.ID()),
+				zap.String("event_type", event.Type()),
+				zap.String("event_source", event.Source()),
+				zap.String("event_subject", event.Subject()),
+			)
+			return true, err
+		}
+		return false, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return position, nil
+}
+
+// AppendBatch puts events into postgres within a single transaction, so either all or none of them are
+// stored, and implements the interface BatchAppendOnly. The positions assigned to the events are
+// returned in the same order as the events.
+func (p *Postgres) AppendBatch(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+	data := make([][]byte, len(events))
+	for i, ev := range events {
+		b, err := p.marshalEvent(ev)
+		if err != nil {
+			return nil, err
+		}
+		data[i] = b
+	}
+
+	var positions []uint64
+	err := retryAppend(ctx, "postgres", func() (bool, error) {
+		err := pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
+			err := p.lock(ctx, tx)
+			if err != nil {
+				return NewPutError("postgres", "event", err)
+			}
+			positions, err = p.insertBatch(ctx, tx, events, data, expectedVersion)
+			return err
+		})
+		if isUniqueViolation(err) {
+			p.logger.Debug("batch conflicted with a concurrently inserted event", zap.Int("events", len(events)))
+			return true, err
+		}
+		return false, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.logger.Info("successfully inserted batch", zap.Int("events", len(events)))
+	return positions, nil
+}
+
+func (p *Postgres) insertBatch(ctx context.Context, tx pgx.Tx, events []*event.Event, data [][]byte, expectedVersion uint64) ([]uint64, error) {
+	positions := make([]uint64, len(events))
+
+	inserted := true
+	for i, ev := range events {
+		existing, err := p.find(ctx, tx, ev.Source(), ev.ID())
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			inserted = false
+			continue
+		}
+		if !sameEvent(existing.Event, ev) {
+			return nil, NewDuplicateEventError(ev.Source(), ev.ID())
+		}
+		positions[i] = existing.Position
+	}
+	if inserted {
+		return positions, nil
+	}
+
+	if expectedVersion != AnyVersion {
+		checked := make(map[string]bool)
+		for _, ev := range events {
+			stream := StreamOf(ev, p.config.StreamExtension)
+			if checked[stream] {
+				continue
+			}
+			checked[stream] = true
+
+			version, err := p.streamVersion(ctx, tx, stream)
+			if err != nil {
+				return nil, NewGetError("postgres", "stream version", err)
+			}
+			if version != expectedVersion {
+				return nil, NewVersionConflictError(stream, expectedVersion, version)
+			}
+		}
+	}
+
+	for i, ev := range events {
+		position, err := p.insert(ctx, tx, ev, data[i], AnyVersion)
+		if err != nil {
+			return nil, err
+		}
+		positions[i] = position
+	}
+	return positions, nil
+}
+
+func (p *Postgres) marshalEvent(event *event.Event) ([]byte, error) {
+	p.logger.Debug("attempting to marshal event to json",
+		zap.String("event_id", event.ID()),
+		zap.String("event_type", event.Type()),
+		zap.String("event_source", event.Source()),
+		zap.String("event_subject", event.Subject()),
+	)
+	b, err := event.MarshalJSON()
+	if err != nil {
+		p.logger.Error("failed to marshal event to json",
+			zap.Error(err),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return nil, NewMarshalError("*event.Event", "json", err)
+	}
+	return b, nil
+}
+
+// insert puts a single event into postgres after checking it has not already been inserted and
+// that its stream is at the expected version. The caller must hold the table lock.
+func (p *Postgres) insert(ctx context.Context, tx pgx.Tx, event *event.Event, data []byte, expectedVersion uint64) (uint64, error) {
+	existing, err := p.find(ctx, tx, event.Source(), event.ID())
+	if err != nil {
+		p.logger.Error("failed to check for existing event",
+			zap.Error(err),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return 0, err
+	}
+	if existing != nil {
+		if !sameEvent(existing.Event, event) {
+			p.logger.Warn("event already exists with different content",
+				zap.Uint64("position", existing.Position),
+				zap.String("event_id", event.ID()),
+				zap.String("event_type", event.Type()),
+				zap.String("event_source", event.Source()),
+				zap.String("event_subject", event.Subject()),
+			)
+			return 0, NewDuplicateEventError(event.Source(), event.ID())
+		}
+		p.logger.Info("event has already been inserted",
+			zap.Uint64("position", existing.Position),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return existing.Position, nil
+	}
+
+	var stream, subject *string
+	var version *int64
+	var t *time.Time
+	if s := StreamOf(event, p.config.StreamExtension); s != "" || expectedVersion != AnyVersion {
+		current, err := p.streamVersion(ctx, tx, s)
+		if err != nil {
+			p.logger.Error("failed to get stream version",
+				zap.Error(err),
+				zap.String("stream", s),
+				zap.String("event_id", event.ID()),
+				zap.String("event_type", event.Type()),
+				zap.String("event_source", event.Source()),
+				zap.String("event_subject", event.Subject()),
+			)
+			return 0, NewGetError("postgres", "stream version", err)
+		}
+		if expectedVersion != AnyVersion && current != expectedVersion {
+			p.logger.Warn("stream is not at expected version",
+				zap.String("stream", s),
+				zap.Uint64("expected_version", expectedVersion),
+				zap.Uint64("actual_version", current),
+				zap.String("event_id", event.ID()),
+				zap.String("event_type", event.Type()),
+				zap.String("event_source", event.Source()),
+				zap.String("event_subject", event.Subject()),
+			)
+			return 0, NewVersionConflictError(s, expectedVersion, current)
+		}
+		if s != "" {
+			next := int64(current + 1)
+			stream, version = &s, &next
+		}
+	}
+	if s := event.Subject(); s != "" {
+		subject = &s
+	}
+	if et := event.Time(); !et.IsZero() {
+		t = &et
+	}
+
+	p.logger.Debug("attempting to insert event",
+		zap.String("event_id", event.ID()),
+		zap.String("event_type", event.Type()),
+		zap.String("event_source", event.Source()),
+		zap.String("event_subject", event.Subject()),
+	)
+	var position int64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO `+p.table+` (id, source, type, subject, time, stream, version, data)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING position`,
+		event.ID(), event.Source(), event.Type(), subject, t, stream, version, data,
+	).Scan(&position)
+	if isUniqueViolation(err) {
+		return 0, err
+	}
+	if err != nil {
+		p.logger.Error("failed to insert event",
+			zap.Error(err),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return 0, NewPutError("postgres", "event", err)
+	}
+	if p.config.Outbox {
+		_, err = tx.Exec(ctx, `INSERT INTO `+p.outbox+` (position) VALUES ($1)`, position)
+		if err != nil {
+			p.logger.Error("failed to insert event into outbox",
+				zap.Error(err),
+				zap.Int64("position", position),
+				zap.String("event_id", event.ID()),
+				zap.String("event_source", event.Source()),
+			)
+			return 0, NewPutError("postgres", "outbox", err)
+		}
+	}
+	// notifications are only delivered once the transaction commits,
+	// and identical ones from the same transaction are delivered once
+	_, err = tx.Exec(ctx, `SELECT pg_notify($1, '')`, p.channel)
+	if err != nil {
+		p.logger.Error("failed to notify of event",
+			zap.Error(err),
+			zap.Int64("position", position),
+			zap.String("event_id", event.ID()),
+			zap.String("event_source", event.Source()),
+		)
+		return 0, NewPutError("postgres", "notification", err)
+	}
+	p.logger.Info("successfully inserted event",
+		zap.Uint64("position", uint64(position)),
+		zap.String("event_id", event.ID()),
+		zap.String("event_type", event.Type()),
+		zap.String("event_source", event.Source()),
+		zap.String("event_subject", event.Subject()),
+	)
+
+	return uint64(position), nil
+}
+
+// postgresQuerier is implemented by both the connection pool and transactions
+type postgresQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// find returns the event with the given source and id, or nil if it does not exist
+func (p *Postgres) find(ctx context.Context, q postgresQuerier, source, id string) (*Record, error) {
+	var position int64
+	var data []byte
+	err := q.QueryRow(ctx,
+		`SELECT position, data FROM `+p.table+` WHERE id = $1 AND source = $2`,
+		id, source,
+	).Scan(&position, &data)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, NewGetError("postgres", "event", err)
+	}
+	return decodePostgresRecord(position, data)
+}
+
+// streamVersion returns the version of the latest event in the stream
+func (p *Postgres) streamVersion(ctx context.Context, tx pgx.Tx, stream string) (uint64, error) {
+	if stream == "" {
+		return 0, nil
+	}
+
+	var version int64
+	err := tx.QueryRow(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM `+p.table+` WHERE stream = $1`,
+		stream,
+	).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(version), nil
+}
+
+// Get returns the event with the given source and id using the unique index on them, and implements the interface Gettable
+func (p *Postgres) Get(ctx context.Context, source, id string) (*Record, error) {
+	rec, err := p.find(ctx, p.pool, source, id)
+	if err != nil {
+		p.logger.Error("failed to find event", zap.Error(err), zap.String("event_id", id), zap.String("event_source", source))
+		return nil, err
+	}
+	if rec == nil {
+		return nil, NewEventNotFoundError(source, id)
+	}
+	return rec, nil
+}
+
+// ReadStream returns an Iterator over the events of the stream in the order of their versions and implements the
+// interface StreamReader. The events are read using the index on the stream and version columns.
+func (p *Postgres) ReadStream(ctx context.Context, stream string, opts ReadStreamOptions) (Iterator, error) {
+	query := `SELECT position, version, data FROM ` + p.table + ` WHERE stream = $1 AND version >= $2 ORDER BY version LIMIT $3`
+	if opts.Backward {
+		query = `SELECT position, version, data FROM ` + p.table + ` WHERE stream = $1 AND version <= $2 ORDER BY version DESC LIMIT $3`
+	}
+	// a null limit does not limit the rows returned
+	var limit *int64
+	if l := opts.limit(); l > 0 {
+		limit = &l
+	}
+
+	rows, err := p.pool.Query(ctx, query, stream, opts.from(), limit)
+	if err != nil {
+		p.logger.Error("failed to find stream events", zap.Error(err), zap.String("stream", stream))
+		return nil, NewGetError("postgres", "event", err)
+	}
+	defer rows.Close()
+
+	var records []*Record
+	for rows.Next() {
+		var position, version int64
+		var data []byte
+		err := rows.Scan(&position, &version, &data)
+		if err != nil {
+			p.logger.Error("failed to read stream event", zap.Error(err), zap.String("stream", stream))
+			return nil, NewGetError("postgres", "event", err)
+		}
+
+		rec, err := decodePostgresRecord(position, data)
+		if err != nil {
+			p.logger.Error("failed to decode event", zap.Error(err))
+			return nil, err
+		}
+		rec.Version = uint64(version)
+		records = append(records, rec)
+	}
+	err = rows.Err()
+	if err != nil {
+		p.logger.Error("failed to read stream event", zap.Error(err), zap.String("stream", stream))
+		return nil, NewGetError("postgres", "event", err)
+	}
+	return &recordsIterator{records: records}, nil
+}
+
+// Pending returns up to limit events which are pending dispatch, in the order they were appended,
+// from the outbox table and implements the interface Outbox
+func (p *Postgres) Pending(ctx context.Context, limit int) ([]*Record, error) {
+	if !p.config.Outbox {
+		return nil, NewOutboxDisabledError("postgres")
+	}
+
+	rows, err := p.pool.Query(ctx,
+		`SELECT e.position, e.data FROM `+p.outbox+` o
+		JOIN `+p.table+` e ON e.position = o.position
+		ORDER BY o.position
+		LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		p.logger.Error("failed to find pending events", zap.Error(err))
+		return nil, NewGetError("postgres", "pending events", err)
+	}
+
+	iter := &postgresIterator{
+		logger: p.logger,
+		rows:   rows,
+	}
+	defer iter.Close(ctx)
+
+	var records []*Record
+	for {
+		rec, err := iter.Next(ctx)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// Dispatched deletes the events from the outbox table and implements the interface Outbox
+func (p *Postgres) Dispatched(ctx context.Context, records ...*Record) error {
+	if !p.config.Outbox {
+		return NewOutboxDisabledError("postgres")
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	positions := make([]int64, len(records))
+	for i, rec := range records {
+		positions[i] = int64(rec.Position)
+	}
+
+	_, err := p.pool.Exec(ctx, `DELETE FROM `+p.outbox+` WHERE position = ANY($1)`, positions)
+	if err != nil {
+		p.logger.Error("failed to mark events as dispatched", zap.Error(err), zap.Int("events", len(records)))
+		return NewPutError("postgres", "outbox", err)
+	}
+	return nil
+}
+
+// Checkpoint returns the position a consumer group has committed and implements the interface Checkpointer
+func (p *Postgres) Checkpoint(ctx context.Context, group string) (uint64, error) {
+	var position int64
+	err := p.pool.QueryRow(ctx, `SELECT position FROM `+p.checkpoints+` WHERE consumer_group = $1`, group).Scan(&position)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		p.logger.Error("failed to get checkpoint", zap.Error(err), zap.String("group", group))
+		return 0, NewGetError("postgres", "checkpoint", err)
+	}
+	return uint64(position), nil
+}
+
+// Commit moves the checkpoint of a consumer group forward to the position and implements the interface Checkpointer
+func (p *Postgres) Commit(ctx context.Context, group string, position uint64) error {
+	_, err := p.pool.Exec(ctx,
+		`INSERT INTO `+p.checkpoints+` AS c (consumer_group, position) VALUES ($1, $2)
+		ON CONFLICT (consumer_group) DO UPDATE SET position = excluded.position
+		WHERE excluded.position > c.position`,
+		group, int64(position),
+	)
+	if err != nil {
+		p.logger.Error("failed to commit checkpoint", zap.Error(err), zap.String("group", group), zap.Uint64("position", position))
+		return NewPutError("postgres", "checkpoint", err)
+	}
+	return nil
+}
+
+// Iterate returns an Iterator over the events in postgres which match the filter in the order they were appended and implements the interface Iterable
+func (p *Postgres) Iterate(ctx context.Context, filter Filter) (Iterator, error) {
+	where, args := newPostgresFilter(filter)
+	query := `SELECT position, data FROM ` + p.table
+	if where != "" {
+		query += ` WHERE ` + where
+	}
+	query += ` ORDER BY position`
+
+	p.logger.Debug("attempting to find events")
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		p.logger.Error("failed to find events", zap.Error(err))
+		return nil, NewGetError("postgres", "event", err)
+	}
+	p.logger.Debug("successfully found events")
+
+	return &postgresIterator{
+		logger: p.logger,
+		rows:   rows,
+	}, nil
+}
+
+// Subscribe returns an Iterator over the events in postgres which match the filter, which waits for matching events
+// to be appended once it has returned every stored event, and implements the interface Subscribable.
+//
+// Each subscription holds a connection which LISTENs for the notification sent by every append, so it is woken
+// once events are committed, including those appended by other processes. If the connection can not listen, or
+// stops listening, the subscription falls back to polling.
+func (p *Postgres) Subscribe(ctx context.Context, filter Filter) (Iterator, error) {
+	conn, err := p.listen(ctx)
+	if err != nil {
+		p.logger.Warn("failed to listen for appended events, so falling back to polling", zap.Error(err))
+		return newSubscription(p, filter, nil, nil), nil
+	}
+
+	signal := new(appendSignal)
+	lctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer conn.Close(context.Background())
+
+		for {
+			_, err := conn.WaitForNotification(lctx)
+			if err != nil {
+				if lctx.Err() == nil {
+					p.logger.Warn("stopped listening for appended events, so falling back to polling", zap.Error(err))
+				}
+				return
+			}
+			signal.broadcast()
+		}
+	}()
+
+	return newSubscription(p, filter, signal, func() {
+		cancel()
+		<-done
+	}), nil
+}
+
+// listen takes a connection out of the pool which listens for the notification sent by every append
+func (p *Postgres) listen(ctx context.Context) (*pgx.Conn, error) {
+	pooled, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn := pooled.Hijack()
+
+	_, err = conn.Exec(ctx, `LISTEN `+pgx.Identifier{p.channel}.Sanitize())
+	if err != nil {
+		conn.Close(context.Background())
+		return nil, err
+	}
+	return conn, nil
+}
+
+type postgresIterator struct {
+	logger *zap.Logger
+	rows   pgx.Rows
+}
+
+// Next decodes the next event from the postgres rows and implements the interface Iterator
+func (it *postgresIterator) Next(ctx context.Context) (*Record, error) {
+	if !it.rows.Next() {
+		err := it.rows.Err()
+		if err != nil {
+			it.logger.Error("failed to read next event", zap.Error(err))
+			return nil, NewGetError("postgres", "event", err)
+		}
+		return nil, io.EOF
+	}
+
+	var position int64
+	var data []byte
+	err := it.rows.Scan(&position, &data)
+	if err != nil {
+		it.logger.Error("failed to read next event", zap.Error(err))
+		return nil, NewGetError("postgres", "event", err)
+	}
+
+	rec, err := decodePostgresRecord(position, data)
+	if err != nil {
+		it.logger.Error("failed to decode event", zap.Error(err))
+		return nil, err
+	}
+	it.logger.Debug("successfully read event",
+		zap.Uint64("position", rec.Position),
+		zap.String("event_id", rec.Event.ID()),
+		zap.String("event_type", rec.Event.Type()),
+		zap.String("event_source", rec.Event.Source()),
+		zap.String("event_subject", rec.Event.Subject()),
+	)
+
+	return rec, nil
+}
+
+// Close closes the underlying postgres rows and implements the interface Iterator
+func (it *postgresIterator) Close(ctx context.Context) error {
+	it.rows.Close()
+	return it.rows.Err()
+}
+
+func decodePostgresRecord(position int64, data []byte) (*Record, error) {
+	var ev event.Event
+	err := ev.UnmarshalJSON(data)
+	if err != nil {
+		return nil, NewMarshalError("json", "*event.Event", err)
+	}
+	return &Record{
+		Position: uint64(position),
+		Event:    &ev,
+	}, nil
+}
+
+// newPostgresFilter returns the where clause and its arguments for the filter. Extension values are
+// compared as text, which is how postgres formats json strings, integers and booleans alike.
+func newPostgresFilter(filter Filter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.AfterPosition > 0 {
+		conds = append(conds, "position > "+arg(int64(filter.AfterPosition)))
+	}
+	if len(filter.Types) > 0 {
+		conds = append(conds, "type = ANY("+arg(filter.Types)+")")
+	}
+	if len(filter.Sources) > 0 {
+		conds = append(conds, "source = ANY("+arg(filter.Sources)+")")
+	}
+	if len(filter.Subjects) > 0 {
+		conds = append(conds, "subject = ANY("+arg(filter.Subjects)+")")
+	}
+	if !filter.StartTime.IsZero() {
+		conds = append(conds, "time >= "+arg(filter.StartTime))
+	}
+	if !filter.EndTime.IsZero() {
+		conds = append(conds, "time < "+arg(filter.EndTime))
+	}
+
+	names := make([]string, 0, len(filter.Extensions))
+	for name := range filter.Extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		conds = append(conds, "data->>"+arg(name)+"::text = "+arg(filter.Extensions[name]))
+	}
+	return strings.Join(conds, " AND "), args
+}
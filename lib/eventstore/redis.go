@@ -0,0 +1,421 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/go-playground/validator/v10"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultRedisCacheTTL is how long a stream stays cached after it was read from the event store
+	DefaultRedisCacheTTL = 10 * time.Minute
+
+	// DefaultRedisCacheMaxStreamLength is the number of events a stream can have before it is no longer cached
+	DefaultRedisCacheMaxStreamLength = 1000
+
+	// DefaultRedisCachePrefix is prepended to every key written to redis
+	DefaultRedisCachePrefix = "evrys:"
+)
+
+// RedisCacheConfig defines the configuration to connect to redis and how streams are cached
+type RedisCacheConfig struct {
+	Addr     string `mapstructure:"addr" validate:"required,hostname_port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db" validate:"gte=0"`
+
+	// Prefix is prepended to every key, defaulting to DefaultRedisCachePrefix
+	Prefix string `mapstructure:"prefix"`
+
+	// TTL is how long a stream stays cached after it was read from the event store, defaulting to DefaultRedisCacheTTL.
+	// Reading or writing through to a cached stream does not extend it, so it bounds how stale a cached stream can be.
+	TTL time.Duration `mapstructure:"ttl" validate:"gte=0"`
+
+	// MaxStreamLength is the number of events a stream can have before it is no longer cached,
+	// defaulting to DefaultRedisCacheMaxStreamLength
+	MaxStreamLength int `mapstructure:"max_stream_length" validate:"gte=0"`
+
+	// StreamExtension is the extension attribute used to group events into streams and must
+	// match the configuration of the wrapped event store. Events are grouped by their subject if it is not set.
+	StreamExtension string `mapstructure:"stream_extension" validate:"omitempty,alphanum,lowercase"`
+}
+
+// Validate ensures redis cache config is correct
+func (r *RedisCacheConfig) Validate() error {
+	return validator.New().Struct(r)
+}
+
+// redisStreamMarker is cached at position 0 of every populated stream, so that streams without any
+// events can be cached and told apart from streams which are not cached. Positions start at 1.
+const redisStreamMarker = "stream"
+
+// populateRedisStream replaces the cached events of a stream, unless the stream was
+// written to after the events were read from the event store.
+//
+// KEYS[1] is the stream, KEYS[2] is the epoch of the stream, ARGV[1] is the epoch
+// before the events were read, ARGV[2] is the ttl in milliseconds and the remaining
+// ARGV are pairs of positions and records.
+var populateRedisStream = redis.NewScript(`
+local epoch = redis.call('GET', KEYS[2]) or '0'
+if epoch ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+for i = 3, #ARGV, 2 do
+	redis.call('ZADD', KEYS[1], ARGV[i], ARGV[i + 1])
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return 1
+`)
+
+// appendRedisStream adds an appended event to a cached stream and moves on its epoch, so that
+// concurrent reads of the event store do not populate the cache with stale events.
+//
+// KEYS[1] is the stream, KEYS[2] is the epoch of the stream, ARGV[1] is the position,
+// ARGV[2] is the record, ARGV[3] is the max stream length and ARGV[4] is the ttl of the epoch in milliseconds.
+// The ttl of the stream is not extended, so it still expires once the ttl has passed since it was populated.
+var appendRedisStream = redis.NewScript(`
+redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[4])
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+if redis.call('ZCOUNT', KEYS[1], ARGV[1], ARGV[1]) == 0 then
+	redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+end
+if redis.call('ZCARD', KEYS[1]) > tonumber(ARGV[3]) + 1 then
+	redis.call('DEL', KEYS[1])
+	return 0
+end
+return 1
+`)
+
+// RedisCache wraps an event store and caches the events of recently read and written streams in redis.
+//
+// Appended events are written through to the cache of their stream, and iterating over a single stream
+// is read through the cache, i.e. a Filter which only matches the events of one stream by its subject,
+// or by the stream extension if one is configured. Other filters are always read from the event store.
+//
+// The event store remains the source of truth, so failing to update the cache does not fail an append.
+// Instead, the stream is evicted from the cache. If that also fails, or the process stops after appending
+// to the event store but before writing through, the cache may be missing events of the stream until it
+// expires. Streams are never cached for longer than the TTL after they were read from the event store.
+type RedisCache struct {
+	config RedisCacheConfig
+	logger *zap.Logger
+	store  Store
+	client *redis.Client
+}
+
+// NewRedisCache constructs a *RedisCache wrapping the given event store and checks it can connect to redis
+func NewRedisCache(ctx context.Context, store Store, config RedisCacheConfig) (*RedisCache, error) {
+	if ctx == nil {
+		return nil, errors.New("context can not be nil")
+	}
+	if store == nil {
+		return nil, errors.New("store can not be nil")
+	}
+
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config, %w", err)
+	}
+	if config.Prefix == "" {
+		config.Prefix = DefaultRedisCachePrefix
+	}
+	if config.TTL == 0 {
+		config.TTL = DefaultRedisCacheTTL
+	}
+	if config.MaxStreamLength == 0 {
+		config.MaxStreamLength = DefaultRedisCacheMaxStreamLength
+	}
+
+	impl := &RedisCache{
+		config: config,
+		logger: zap.L().With(zap.String("source", "RedisCacheImpl")),
+		store:  store,
+	}
+
+	err = impl.init(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init, %w", err)
+	}
+
+	return impl, nil
+}
+
+func (r *RedisCache) init(ctx context.Context) error {
+	r.client = redis.NewClient(&redis.Options{
+		Addr:     r.config.Addr,
+		Username: r.config.Username,
+		Password: r.config.Password,
+		DB:       r.config.DB,
+	})
+
+	r.logger.Debug("attempting to ping redis")
+	err := r.client.Ping(ctx).Err()
+	if err != nil {
+		r.logger.Error("failed to ping redis", zap.Error(err))
+		r.client.Close()
+		return NewConnectionError("redis", err)
+	}
+	r.logger.Debug("successfully pinged redis")
+
+	return nil
+}
+
+// Close closes the connection to redis along with the wrapped event store if it is an io.Closer
+func (r *RedisCache) Close() error {
+	err := r.client.Close()
+	if closer, ok := r.store.(io.Closer); ok {
+		if cerr := closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (r *RedisCache) streamKey(stream string) string {
+	return r.config.Prefix + "stream:" + stream
+}
+
+func (r *RedisCache) epochKey(stream string) string {
+	return r.config.Prefix + "epoch:" + stream
+}
+
+// Append appends the event to the wrapped event store and writes it through to the cache of its stream,
+// and implements the interface AppendOnly
+func (r *RedisCache) Append(ctx context.Context, event *event.Event, expectedVersion uint64) (uint64, error) {
+	position, err := r.store.Append(ctx, event, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	r.writeThrough(ctx, position, event)
+	return position, nil
+}
+
+// AppendBatch appends the events to the wrapped event store and writes them through to the cache of their
+// streams, and implements the interface BatchAppendOnly
+func (r *RedisCache) AppendBatch(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+	positions, err := r.store.AppendBatch(ctx, events, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+	for i, ev := range events {
+		r.writeThrough(ctx, positions[i], ev)
+	}
+	return positions, nil
+}
+
+// writeThrough adds an appended event to the cache of its stream if the stream is cached
+func (r *RedisCache) writeThrough(ctx context.Context, position uint64, ev *event.Event) {
+	stream := StreamOf(ev, r.config.StreamExtension)
+	if stream == "" {
+		return
+	}
+
+	member, err := encodeCachedRecord(&Record{Position: position, Event: ev})
+	if err == nil {
+		err = appendRedisStream.Run(ctx, r.client,
+			[]string{r.streamKey(stream), r.epochKey(stream)},
+			position, member, r.config.MaxStreamLength, r.config.TTL.Milliseconds(),
+		).Err()
+	}
+	if err == nil {
+		return
+	}
+
+	r.logger.Warn("failed to write event through to cache",
+		zap.Error(err),
+		zap.String("stream", stream),
+		zap.Uint64("position", position),
+	)
+	err = r.client.Del(ctx, r.streamKey(stream)).Err()
+	if err != nil {
+		r.logger.Error("failed to evict stream from cache", zap.Error(err), zap.String("stream", stream))
+	}
+}
+
+// streamOf returns the stream a filter only matches the events of, if any
+func (r *RedisCache) streamOf(filter Filter) (string, bool) {
+	if r.config.StreamExtension == "" {
+		if len(filter.Subjects) != 1 || filter.Subjects[0] == "" {
+			return "", false
+		}
+		return filter.Subjects[0], true
+	}
+
+	stream, ok := filter.Extensions[r.config.StreamExtension]
+	if !ok || stream == "" {
+		return "", false
+	}
+	return stream, true
+}
+
+// streamFilter returns a filter which matches every event of the stream
+func (r *RedisCache) streamFilter(stream string) Filter {
+	if r.config.StreamExtension == "" {
+		return Filter{Subjects: []string{stream}}
+	}
+	return Filter{Extensions: map[string]string{r.config.StreamExtension: stream}}
+}
+
+// Get returns the event with the given source and id from the wrapped event store, and implements the interface Gettable
+func (r *RedisCache) Get(ctx context.Context, source, id string) (*Record, error) {
+	return r.store.Get(ctx, source, id)
+}
+
+// Iterate returns an Iterator over the events which match the filter in the order they were appended and
+// implements the interface Iterable. If the filter only matches the events of a single stream, the events
+// are read from the cache, populating it from the wrapped event store if the stream is not cached.
+func (r *RedisCache) Iterate(ctx context.Context, filter Filter) (Iterator, error) {
+	stream, ok := r.streamOf(filter)
+	if !ok {
+		return r.store.Iterate(ctx, filter)
+	}
+
+	records, err := r.cached(ctx, stream)
+	if err != nil {
+		r.logger.Warn("failed to read stream from cache", zap.Error(err), zap.String("stream", stream))
+		return r.store.Iterate(ctx, filter)
+	}
+	if records == nil {
+		records, ok, err = r.populate(ctx, stream)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return r.store.Iterate(ctx, filter)
+		}
+	}
+
+	return &memoryIterator{
+		filter:  filter,
+		records: records,
+	}, nil
+}
+
+// ReadStream returns an Iterator over the events of the stream in the order of their versions and implements
+// the interface StreamReader. The events are read from the cache, populating it from the wrapped event store
+// if the stream is not cached. Since the cache holds every event of a stream in the order they were appended,
+// the version of each event is its place in the cache.
+func (r *RedisCache) ReadStream(ctx context.Context, stream string, opts ReadStreamOptions) (Iterator, error) {
+	if stream == "" {
+		return r.store.ReadStream(ctx, stream, opts)
+	}
+
+	records, err := r.cached(ctx, stream)
+	if err != nil {
+		r.logger.Warn("failed to read stream from cache", zap.Error(err), zap.String("stream", stream))
+		return r.store.ReadStream(ctx, stream, opts)
+	}
+	if records == nil {
+		var ok bool
+		records, ok, err = r.populate(ctx, stream)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return r.store.ReadStream(ctx, stream, opts)
+		}
+	}
+
+	return &recordsIterator{
+		records: readStreamRecords(records, opts),
+	}, nil
+}
+
+// Subscribe subscribes to the wrapped event store and implements the interface Subscribable. Events are
+// always read from the wrapped event store, since the cache of a stream is only populated on demand.
+func (r *RedisCache) Subscribe(ctx context.Context, filter Filter) (Iterator, error) {
+	return Subscribe(ctx, r.store, filter)
+}
+
+// Checkpoint returns the checkpoint of a consumer group from the wrapped event store and implements the interface Checkpointer
+func (r *RedisCache) Checkpoint(ctx context.Context, group string) (uint64, error) {
+	return r.store.Checkpoint(ctx, group)
+}
+
+// Commit commits the checkpoint of a consumer group to the wrapped event store and implements the interface Checkpointer
+func (r *RedisCache) Commit(ctx context.Context, group string, position uint64) error {
+	return r.store.Commit(ctx, group, position)
+}
+
+// cached returns the cached events of a stream, or nil if the stream is not cached
+func (r *RedisCache) cached(ctx context.Context, stream string) ([]*Record, error) {
+	members, err := r.client.ZRange(ctx, r.streamKey(stream), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	records := make([]*Record, 0, len(members))
+	for _, member := range members {
+		if member == redisStreamMarker {
+			continue
+		}
+		rec, err := decodeCachedRecord(member)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	r.logger.Debug("read stream from cache", zap.String("stream", stream), zap.Int("events", len(records)))
+	return records, nil
+}
+
+// populate reads every event of the stream from the wrapped event store and caches them. False is returned
+// if the stream has too many events to be cached, in which case none of its events are returned.
+func (r *RedisCache) populate(ctx context.Context, stream string) ([]*Record, bool, error) {
+	epoch, err := r.client.Get(ctx, r.epochKey(stream)).Result()
+	if err == redis.Nil {
+		epoch = "0"
+	} else if err != nil {
+		r.logger.Warn("failed to read stream epoch from cache", zap.Error(err), zap.String("stream", stream))
+		return nil, false, nil
+	}
+
+	iter, err := r.store.Iterate(ctx, r.streamFilter(stream))
+	if err != nil {
+		return nil, false, err
+	}
+	defer iter.Close(ctx)
+
+	records := []*Record{}
+	for {
+		rec, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if len(records) == r.config.MaxStreamLength {
+			r.logger.Debug("stream is too long to cache", zap.String("stream", stream))
+			return nil, false, nil
+		}
+		records = append(records, rec)
+	}
+	args := []interface{}{epoch, r.config.TTL.Milliseconds(), 0, redisStreamMarker}
+	for _, rec := range records {
+		member, err := encodeCachedRecord(rec)
+		if err != nil {
+			return nil, false, err
+		}
+		args = append(args, rec.Position, member)
+	}
+	err = populateRedisStream.Run(ctx, r.client, []string{r.streamKey(stream), r.epochKey(stream)}, args...).Err()
+	if err != nil {
+		r.logger.Warn("failed to populate stream cache", zap.Error(err), zap.String("stream", stream))
+	}
+	return records, true, nil
+}
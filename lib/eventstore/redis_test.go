@@ -0,0 +1,202 @@
+package eventstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestRedisCacheConfig_Validate(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config - no addr", func(t *testing.T) {
+		conf := RedisCacheConfig{}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - addr without port", func(t *testing.T) {
+		conf := RedisCacheConfig{Addr: "localhost"}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - negative ttl", func(t *testing.T) {
+		conf := RedisCacheConfig{Addr: "localhost:6379", TTL: -time.Second}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		conf := RedisCacheConfig{Addr: "localhost:6379", TTL: time.Minute, MaxStreamLength: 10}
+		req.NoError(conf.Validate(), "config should have validated")
+	})
+}
+
+func TestNewRedisCache(t *testing.T) {
+	req := require.New(t)
+	store, err := NewMemory(MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	t.Run("nil ctx", func(t *testing.T) {
+		_, err := NewRedisCache(nil, store, RedisCacheConfig{})
+		req.ErrorContains(err, "context can not be nil", "error is not target error")
+	})
+
+	t.Run("nil store", func(t *testing.T) {
+		_, err := NewRedisCache(context.TODO(), nil, RedisCacheConfig{})
+		req.ErrorContains(err, "store can not be nil", "error is not target error")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewRedisCache(context.TODO(), store, RedisCacheConfig{})
+		req.ErrorAs(err, &ValidationErrors, "expected validation error")
+	})
+
+	t.Run("redis connection error", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, err := NewRedisCache(ctx, store, RedisCacheConfig{Addr: "localhost:1"})
+		var connErr *ConnectionError
+		req.ErrorAs(err, &connErr, "expected connection error")
+	})
+}
+
+func TestRedisCache_streamOf(t *testing.T) {
+	req := require.New(t)
+
+	bySubject := &RedisCache{}
+	stream, ok := bySubject.streamOf(Filter{Subjects: []string{"a"}, AfterPosition: 10})
+	req.True(ok, "filter by a single subject should read a stream")
+	req.Equal("a", stream, "stream not expected value")
+
+	_, ok = bySubject.streamOf(Filter{Subjects: []string{"a", "b"}})
+	req.False(ok, "filter by several subjects should not read a stream")
+
+	_, ok = bySubject.streamOf(Filter{Types: []string{"a"}})
+	req.False(ok, "filter without a subject should not read a stream")
+
+	byExtension := &RedisCache{config: RedisCacheConfig{StreamExtension: "aggregateid"}}
+	stream, ok = byExtension.streamOf(Filter{Extensions: map[string]string{"aggregateid": "a"}})
+	req.True(ok, "filter by the stream extension should read a stream")
+	req.Equal("a", stream, "stream not expected value")
+
+	_, ok = byExtension.streamOf(Filter{Subjects: []string{"a"}})
+	req.False(ok, "filter by subject should not read a stream when streams are grouped by extension")
+}
+
+func TestRedisCacheIntegration(t *testing.T) {
+	// setup
+	req := require.New(t)
+	ctx := context.Background()
+
+	// container init
+	contReq := testcontainers.ContainerRequest{
+		Image:        "redis:7.0",
+		ExposedPorts: []string{"6379:6379"},
+		WaitingFor:   wait.ForLog("Ready to accept connections"),
+	}
+	redisC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: contReq,
+		Started:          true,
+	})
+	req.NoError(err, "failed to create redis container")
+	defer redisC.Terminate(ctx)
+
+	// redis verification
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+	req.NoError(client.Ping(ctx).Err(), "failed to connect to redis")
+
+	// impl setup
+	store, err := NewMemory(MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	cache, err := NewRedisCache(ctx, store, RedisCacheConfig{
+		Addr:            "localhost:6379",
+		TTL:             time.Minute,
+		MaxStreamLength: 3,
+	})
+	req.NoError(err, "failed to create redis cache")
+	defer cache.Close()
+
+	readStream := func(filter Filter) []string {
+		iter, err := cache.Iterate(ctx, filter)
+		req.NoError(err, "failed to iterate events")
+		records, err := readAll(ctx, iter)
+		req.NoError(err, "failed to read events")
+
+		var ids []string
+		for _, rec := range records {
+			ids = append(ids, rec.Event.ID())
+		}
+		return ids
+	}
+
+	// appending to a stream which is not cached does not cache it
+	first := newMemoryTestEvent("1", "a")
+	_, err = cache.Append(ctx, first, AnyVersion)
+	req.NoError(err, "failed to put event")
+	req.EqualValues(0, client.Exists(ctx, "evrys:stream:a").Val(), "stream should not be cached")
+
+	// read through
+	req.Equal([]string{"1"}, readStream(Filter{Subjects: []string{"a"}}), "events not expected value")
+	req.EqualValues(2, client.ZCard(ctx, "evrys:stream:a").Val(), "stream should be cached")
+	ttl := client.PTTL(ctx, "evrys:stream:a").Val()
+	req.Greater(ttl, time.Duration(0), "cached stream should expire")
+	req.LessOrEqual(ttl, time.Minute, "cached stream should expire")
+
+	// write through
+	_, err = cache.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("2", "a"), newMemoryTestEvent("3", "b")}, AnyVersion)
+	req.NoError(err, "failed to put batch")
+	req.EqualValues(3, client.ZCard(ctx, "evrys:stream:a").Val(), "appended event should be written through")
+	req.EqualValues(0, client.Exists(ctx, "evrys:stream:b").Val(), "stream should not be cached")
+
+	_, err = cache.Append(ctx, first, AnyVersion)
+	req.NoError(err, "re-appending the same event should succeed")
+	req.EqualValues(3, client.ZCard(ctx, "evrys:stream:a").Val(), "re-appended event should not be cached twice")
+
+	// the cache is read, not the event store
+	req.NoError(client.ZRemRangeByScore(ctx, "evrys:stream:a", "1", "1").Err(), "failed to remove cached event")
+	req.Equal([]string{"2"}, readStream(Filter{Subjects: []string{"a"}}), "stream should be read from cache")
+	req.Equal([]string{"1", "2"}, readStream(Filter{Subjects: []string{"a", "c"}}), "other filters should not be read from cache")
+
+	// the rest of the filter is applied to cached events
+	req.NoError(client.Del(ctx, "evrys:stream:a").Err(), "failed to evict stream")
+	req.Equal([]string{"2"}, readStream(Filter{Subjects: []string{"a"}, AfterPosition: 1}), "events not expected value")
+	req.Empty(readStream(Filter{Subjects: []string{"a"}, Types: []string{"other"}}), "events not expected value")
+
+	// streams which are too long are not cached
+	_, err = cache.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("4", "a"), newMemoryTestEvent("5", "a")}, AnyVersion)
+	req.NoError(err, "failed to put batch")
+	req.EqualValues(0, client.Exists(ctx, "evrys:stream:a").Val(), "stream should be evicted once it is too long")
+	req.Equal([]string{"1", "2", "4", "5"}, readStream(Filter{Subjects: []string{"a"}}), "events not expected value")
+	req.EqualValues(0, client.Exists(ctx, "evrys:stream:a").Val(), "stream should not be cached once it is too long")
+
+	// a stream is not populated with events read before a concurrent append
+	epoch := client.Get(ctx, "evrys:epoch:b").Val()
+	_, err = cache.Append(ctx, newMemoryTestEvent("6", "b"), AnyVersion)
+	req.NoError(err, "failed to put event")
+	ok, err := populateRedisStream.Run(ctx, client, []string{"evrys:stream:b", "evrys:epoch:b"}, epoch, time.Minute.Milliseconds()).Int()
+	req.NoError(err, "failed to run populate script")
+	req.Equal(0, ok, "stale events should not be cached")
+
+	// streams without any events are cached too
+	req.Empty(readStream(Filter{Subjects: []string{"d"}}), "events not expected value")
+	req.EqualValues(1, client.Exists(ctx, "evrys:stream:d").Val(), "empty stream should be cached")
+	_, err = cache.Append(ctx, newMemoryTestEvent("8", "d"), AnyVersion)
+	req.NoError(err, "failed to put event")
+	req.Equal([]string{"8"}, readStream(Filter{Subjects: []string{"d"}}), "appended event should be written through")
+
+	// neither reads nor writes extend how long a stream is cached
+	req.NoError(client.PExpire(ctx, "evrys:stream:d", 30*time.Second).Err(), "failed to expire stream")
+	req.Equal([]string{"8"}, readStream(Filter{Subjects: []string{"d"}}), "events not expected value")
+	_, err = cache.Append(ctx, newMemoryTestEvent("9", "d"), AnyVersion)
+	req.NoError(err, "failed to put event")
+	req.EqualValues(3, client.ZCard(ctx, "evrys:stream:d").Val(), "appended event should be written through")
+	req.LessOrEqual(client.PTTL(ctx, "evrys:stream:d").Val(), 30*time.Second, "cached stream ttl should not be extended")
+}
@@ -0,0 +1,53 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+const (
+	// maxAppendAttempts is how many times an append which conflicts with concurrent appends is attempted
+	maxAppendAttempts = 10
+
+	// appendRetryDelay is the longest wait before an append is first retried, which doubles with each retry
+	appendRetryDelay = 5 * time.Millisecond
+)
+
+// errPositionTaken is returned by an attempt to append whose positions were taken by a concurrent append. Unlike
+// other conflicts, it means another append has been committed, or is being committed, so it does not count
+// towards maxAppendAttempts and appends to a busy event store are retried rather than failed.
+var errPositionTaken = errors.New("positions were taken by a concurrent append")
+
+// retryAppend calls attempt until it does not fail because of a conflict with a concurrent append, which attempt
+// reports by returning true along with its error. Conflicting appends are retried after a random wait, so that the
+// appends they conflicted with are spread out. A *RetryLimitError is returned once attempt has conflicted
+// maxAppendAttempts times, other than with errPositionTaken, and the error of the context is returned if it is
+// done before the next attempt.
+func retryAppend(ctx context.Context, source string, attempt func() (bool, error)) error {
+	n := 0
+	for {
+		conflict, err := attempt()
+		if !conflict {
+			return err
+		}
+
+		delay := appendRetryDelay
+		if err != errPositionTaken {
+			n++
+			if n == maxAppendAttempts {
+				return NewRetryLimitError(source, n, err)
+			}
+			delay <<= n - 1
+		}
+
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(delay))))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
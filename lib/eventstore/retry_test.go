@@ -0,0 +1,79 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryAppend(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errConflict := errors.New("conflict")
+
+	t.Run("retries conflicts until the append succeeds", func(t *testing.T) {
+		attempts := 0
+		err := retryAppend(ctx, "test", func() (bool, error) {
+			attempts++
+			if attempts < 3 {
+				return true, errConflict
+			}
+			return false, nil
+		})
+		require.NoError(t, err, "append should have succeeded")
+		require.Equal(t, 3, attempts, "attempts not expected value")
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		attempts := 0
+		err := retryAppend(ctx, "test", func() (bool, error) {
+			attempts++
+			return false, errFailed
+		})
+		require.ErrorIs(t, err, errFailed, "expected the error of the attempt")
+		require.Equal(t, 1, attempts, "attempts not expected value")
+	})
+
+	t.Run("gives up once the attempts are used up", func(t *testing.T) {
+		attempts := 0
+		err := retryAppend(ctx, "test", func() (bool, error) {
+			attempts++
+			return true, errConflict
+		})
+		var limitErr *RetryLimitError
+		require.ErrorAs(t, err, &limitErr, "expected retry limit error")
+		require.ErrorIs(t, err, errConflict, "expected the last conflict to be wrapped")
+		require.Equal(t, maxAppendAttempts, limitErr.Attempts, "attempts not expected value")
+		require.Equal(t, maxAppendAttempts, attempts, "attempts not expected value")
+	})
+
+	t.Run("retries taken positions without giving up", func(t *testing.T) {
+		attempts := 0
+		err := retryAppend(ctx, "test", func() (bool, error) {
+			attempts++
+			if attempts < 2*maxAppendAttempts {
+				return true, errPositionTaken
+			}
+			return false, nil
+		})
+		require.NoError(t, err, "append should have succeeded")
+		require.Equal(t, 2*maxAppendAttempts, attempts, "attempts not expected value")
+	})
+
+	t.Run("stops once the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		attempts := 0
+		err := retryAppend(ctx, "test", func() (bool, error) {
+			attempts++
+			cancel()
+			return true, errConflict
+		})
+		require.ErrorIs(t, err, context.Canceled, "expected the error of the context")
+		require.Equal(t, 1, attempts, "attempts not expected value")
+	})
+}
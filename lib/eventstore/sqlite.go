@@ -0,0 +1,725 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	// registers the "sqlite" database/sql driver
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteConfig defines the configuration of the sqlite event store
+type SQLiteConfig struct {
+	// Path is the database file, which is created if it does not exist
+	Path string `mapstructure:"path" validate:"required"`
+
+	// StreamExtension is the extension attribute used to group events into streams.
+	// Events are grouped by their subject if it is not set.
+	StreamExtension string `mapstructure:"stream_extension" validate:"omitempty,alphanum,lowercase"`
+
+	// Outbox records every appended event as pending dispatch until it is marked as dispatched.
+	// Pending events are kept in the outbox table, which is inserted into in the same transaction as the events.
+	Outbox bool `mapstructure:"outbox"`
+}
+
+// Validate ensures sqlite config is correct
+func (s *SQLiteConfig) Validate() error {
+	return validator.New().Struct(s)
+}
+
+// getDSN returns the data source name of the database. Write-ahead logging lets readers carry on
+// while an append is in progress, and transactions take the write lock as soon as they begin so
+// that concurrent appends wait on the busy timeout instead of failing to upgrade their lock.
+func (s *SQLiteConfig) getDSN() string {
+	q := url.Values{}
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", "synchronous(FULL)")
+	q.Add("_pragma", "busy_timeout(5000)")
+	q.Set("_txlock", "immediate")
+	return s.Path + "?" + q.Encode()
+}
+
+// SQLite is the event store implementation for a local sqlite database file
+type SQLite struct {
+	config SQLiteConfig
+	logger *zap.Logger
+	db     *sql.DB
+
+	// sqlite only allows a single writer at a time, so appends
+	// from this process are serialized before reaching it
+	mu       sync.Mutex
+	appended appendSignal
+}
+
+// NewSQLite constructs and initializes a *SQLite, creating the database and its schema if they do not exist
+func NewSQLite(ctx context.Context, config SQLiteConfig) (*SQLite, error) {
+	if ctx == nil {
+		return nil, errors.New("context can not be nil")
+	}
+
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config, %w", err)
+	}
+
+	impl := &SQLite{
+		config: config,
+		logger: zap.L().With(zap.String("source", "SQLiteEventStoreImpl")),
+	}
+
+	err = impl.init(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init, %w", err)
+	}
+
+	return impl, nil
+}
+
+func (s *SQLite) init(ctx context.Context) error {
+	s.logger.Debug("attempting to open sqlite database", zap.String("path", s.config.Path))
+	db, err := sql.Open("sqlite", s.config.getDSN())
+	if err != nil {
+		s.logger.Error("failed to open sqlite database", zap.Error(err))
+		return NewConnectionError("sqlite", err)
+	}
+	err = db.PingContext(ctx)
+	if err != nil {
+		db.Close()
+		s.logger.Error("failed to open sqlite database", zap.Error(err))
+		return NewConnectionError("sqlite", err)
+	}
+	s.logger.Debug("successfully opened sqlite database")
+
+	s.db = db
+
+	s.logger.Debug("attempting to create schema")
+	err = s.createSchema(ctx)
+	if err != nil {
+		s.logger.Error("failed to create schema", zap.Error(err))
+		return NewConnectionError("sqlite", err)
+	}
+	s.logger.Debug("successfully created schema")
+
+	return nil
+}
+
+// Close closes the sqlite database
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+// createSchema creates the events, outbox and checkpoints tables. Events are stored in the cloudevents json format in the
+// data column, with the attributes used for filtering copied out into their own columns. Times
+// are stored as nanoseconds since the unix epoch so they compare correctly.
+func (s *SQLite) createSchema(ctx context.Context) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS events (
+			position INTEGER PRIMARY KEY AUTOINCREMENT,
+			id TEXT NOT NULL,
+			source TEXT NOT NULL,
+			type TEXT NOT NULL,
+			subject TEXT,
+			time INTEGER,
+			stream TEXT,
+			version INTEGER,
+			data TEXT NOT NULL
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS events_id_source ON events (id, source)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS events_stream_version ON events (stream, version) WHERE stream IS NOT NULL`,
+		`CREATE INDEX IF NOT EXISTS events_type ON events (type)`,
+		`CREATE INDEX IF NOT EXISTS events_source ON events (source)`,
+		`CREATE INDEX IF NOT EXISTS events_subject ON events (subject)`,
+		`CREATE TABLE IF NOT EXISTS outbox (
+			position INTEGER PRIMARY KEY REFERENCES events (position)
+		)`,
+		`CREATE TABLE IF NOT EXISTS checkpoints (
+			consumer_group TEXT PRIMARY KEY,
+			position INTEGER NOT NULL
+		)`,
+	}
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, stmt := range statements {
+			_, err := tx.ExecContext(ctx, stmt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// inTx runs fn in a transaction which is committed if fn succeeds and rolled back otherwise
+func (s *SQLite) inTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Append puts an event into sqlite, returning its position in the log, and implements the interface AppendOnly.
+func (s *SQLite) Append(ctx context.Context, event *event.Event, expectedVersion uint64) (uint64, error) {
+	data, err := s.marshalEvent(event)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var position uint64
+	err = s.inTx(ctx, func(tx *sql.Tx) error {
+		position, err = s.insert(ctx, tx, event, data, expectedVersion)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.appended.broadcast()
+	return position, nil
+}
+
+// AppendBatch puts events into sqlite within a single transaction, so either all or none of them are
+// stored, and implements the interface BatchAppendOnly. The positions assigned to the events are
+// returned in the same order as the events.
+func (s *SQLite) AppendBatch(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+	data := make([]string, len(events))
+	for i, ev := range events {
+		b, err := s.marshalEvent(ev)
+		if err != nil {
+			return nil, err
+		}
+		data[i] = b
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var positions []uint64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		positions, err = s.insertBatch(ctx, tx, events, data, expectedVersion)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.appended.broadcast()
+	s.logger.Info("successfully inserted batch", zap.Int("events", len(events)))
+	return positions, nil
+}
+
+func (s *SQLite) insertBatch(ctx context.Context, tx *sql.Tx, events []*event.Event, data []string, expectedVersion uint64) ([]uint64, error) {
+	positions := make([]uint64, len(events))
+
+	inserted := true
+	for i, ev := range events {
+		existing, err := s.find(ctx, tx, ev.Source(), ev.ID())
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			inserted = false
+			continue
+		}
+		if !sameEvent(existing.Event, ev) {
+			return nil, NewDuplicateEventError(ev.Source(), ev.ID())
+		}
+		positions[i] = existing.Position
+	}
+	if inserted {
+		return positions, nil
+	}
+
+	if expectedVersion != AnyVersion {
+		checked := make(map[string]bool)
+		for _, ev := range events {
+			stream := StreamOf(ev, s.config.StreamExtension)
+			if checked[stream] {
+				continue
+			}
+			checked[stream] = true
+
+			version, err := s.streamVersion(ctx, tx, stream)
+			if err != nil {
+				return nil, NewGetError("sqlite", "stream version", err)
+			}
+			if version != expectedVersion {
+				return nil, NewVersionConflictError(stream, expectedVersion, version)
+			}
+		}
+	}
+
+	for i, ev := range events {
+		position, err := s.insert(ctx, tx, ev, data[i], AnyVersion)
+		if err != nil {
+			return nil, err
+		}
+		positions[i] = position
+	}
+	return positions, nil
+}
+
+func (s *SQLite) marshalEvent(event *event.Event) (string, error) {
+	b, err := event.MarshalJSON()
+	if err != nil {
+		s.logger.Error("failed to marshal event to json",
+			zap.Error(err),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return "", NewMarshalError("*event.Event", "json", err)
+	}
+	return string(b), nil
+}
+
+// insert puts a single event into sqlite after checking it has not already been inserted and
+// that its stream is at the expected version
+func (s *SQLite) insert(ctx context.Context, tx *sql.Tx, event *event.Event, data string, expectedVersion uint64) (uint64, error) {
+	existing, err := s.find(ctx, tx, event.Source(), event.ID())
+	if err != nil {
+		s.logger.Error("failed to check for existing event",
+			zap.Error(err),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return 0, err
+	}
+	if existing != nil {
+		if !sameEvent(existing.Event, event) {
+			s.logger.Warn("event already exists with different content",
+				zap.Uint64("position", existing.Position),
+				zap.String("event_id", event.ID()),
+				zap.String("event_type", event.Type()),
+				zap.String("event_source", event.Source()),
+				zap.String("event_subject", event.Subject()),
+			)
+			return 0, NewDuplicateEventError(event.Source(), event.ID())
+		}
+		s.logger.Info("event has already been inserted",
+			zap.Uint64("position", existing.Position),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return existing.Position, nil
+	}
+
+	var stream, subject sql.NullString
+	var version, t sql.NullInt64
+	if st := StreamOf(event, s.config.StreamExtension); st != "" || expectedVersion != AnyVersion {
+		current, err := s.streamVersion(ctx, tx, st)
+		if err != nil {
+			s.logger.Error("failed to get stream version",
+				zap.Error(err),
+				zap.String("stream", st),
+				zap.String("event_id", event.ID()),
+				zap.String("event_type", event.Type()),
+				zap.String("event_source", event.Source()),
+				zap.String("event_subject", event.Subject()),
+			)
+			return 0, NewGetError("sqlite", "stream version", err)
+		}
+		if expectedVersion != AnyVersion && current != expectedVersion {
+			s.logger.Warn("stream is not at expected version",
+				zap.String("stream", st),
+				zap.Uint64("expected_version", expectedVersion),
+				zap.Uint64("actual_version", current),
+				zap.String("event_id", event.ID()),
+				zap.String("event_type", event.Type()),
+				zap.String("event_source", event.Source()),
+				zap.String("event_subject", event.Subject()),
+			)
+			return 0, NewVersionConflictError(st, expectedVersion, current)
+		}
+		if st != "" {
+			stream = sql.NullString{String: st, Valid: true}
+			version = sql.NullInt64{Int64: int64(current + 1), Valid: true}
+		}
+	}
+	if sub := event.Subject(); sub != "" {
+		subject = sql.NullString{String: sub, Valid: true}
+	}
+	if et := event.Time(); !et.IsZero() {
+		t = sql.NullInt64{Int64: et.UnixNano(), Valid: true}
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO events (id, source, type, subject, time, stream, version, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.ID(), event.Source(), event.Type(), subject, t, stream, version, data,
+	)
+	if err != nil {
+		s.logger.Error("failed to insert event",
+			zap.Error(err),
+			zap.String("event_id", event.ID()),
+			zap.String("event_type", event.Type()),
+			zap.String("event_source", event.Source()),
+			zap.String("event_subject", event.Subject()),
+		)
+		return 0, NewPutError("sqlite", "event", err)
+	}
+	position, err := res.LastInsertId()
+	if err != nil {
+		return 0, NewPutError("sqlite", "position", err)
+	}
+	if s.config.Outbox {
+		_, err = tx.ExecContext(ctx, `INSERT INTO outbox (position) VALUES (?)`, position)
+		if err != nil {
+			s.logger.Error("failed to insert event into outbox",
+				zap.Error(err),
+				zap.Int64("position", position),
+				zap.String("event_id", event.ID()),
+				zap.String("event_source", event.Source()),
+			)
+			return 0, NewPutError("sqlite", "outbox", err)
+		}
+	}
+	s.logger.Info("successfully inserted event",
+		zap.Int64("position", position),
+		zap.String("event_id", event.ID()),
+		zap.String("event_type", event.Type()),
+		zap.String("event_source", event.Source()),
+		zap.String("event_subject", event.Subject()),
+	)
+
+	return uint64(position), nil
+}
+
+// sqliteQuerier is implemented by both the database and transactions
+type sqliteQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// find returns the event with the given source and id, or nil if it does not exist
+func (s *SQLite) find(ctx context.Context, q sqliteQuerier, source, id string) (*Record, error) {
+	var position int64
+	var data string
+	err := q.QueryRowContext(ctx,
+		`SELECT position, data FROM events WHERE id = ? AND source = ?`,
+		id, source,
+	).Scan(&position, &data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, NewGetError("sqlite", "event", err)
+	}
+	return decodeSQLRecord(position, []byte(data))
+}
+
+// streamVersion returns the version of the latest event in the stream
+func (s *SQLite) streamVersion(ctx context.Context, tx *sql.Tx, stream string) (uint64, error) {
+	if stream == "" {
+		return 0, nil
+	}
+
+	var version int64
+	err := tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM events WHERE stream = ?`,
+		stream,
+	).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(version), nil
+}
+
+// Get returns the event with the given source and id using the unique index on them, and implements the interface Gettable
+func (s *SQLite) Get(ctx context.Context, source, id string) (*Record, error) {
+	rec, err := s.find(ctx, s.db, source, id)
+	if err != nil {
+		s.logger.Error("failed to find event", zap.Error(err), zap.String("event_id", id), zap.String("event_source", source))
+		return nil, err
+	}
+	if rec == nil {
+		return nil, NewEventNotFoundError(source, id)
+	}
+	return rec, nil
+}
+
+// ReadStream returns an Iterator over the events of the stream in the order of their versions and implements the
+// interface StreamReader. The events are read using the index on the stream and version columns.
+func (s *SQLite) ReadStream(ctx context.Context, stream string, opts ReadStreamOptions) (Iterator, error) {
+	query := `SELECT position, version, data FROM events WHERE stream = ? AND version >= ? ORDER BY version LIMIT ?`
+	if opts.Backward {
+		query = `SELECT position, version, data FROM events WHERE stream = ? AND version <= ? ORDER BY version DESC LIMIT ?`
+	}
+	limit := opts.limit()
+	if limit == 0 {
+		// a negative limit does not limit the rows returned
+		limit = -1
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, stream, opts.from(), limit)
+	if err != nil {
+		s.logger.Error("failed to find stream events", zap.Error(err), zap.String("stream", stream))
+		return nil, NewGetError("sqlite", "event", err)
+	}
+	defer rows.Close()
+
+	var records []*Record
+	for rows.Next() {
+		var position, version int64
+		var data string
+		err := rows.Scan(&position, &version, &data)
+		if err != nil {
+			s.logger.Error("failed to read stream event", zap.Error(err), zap.String("stream", stream))
+			return nil, NewGetError("sqlite", "event", err)
+		}
+
+		rec, err := decodeSQLRecord(position, []byte(data))
+		if err != nil {
+			s.logger.Error("failed to decode event", zap.Error(err))
+			return nil, err
+		}
+		rec.Version = uint64(version)
+		records = append(records, rec)
+	}
+	err = rows.Err()
+	if err != nil {
+		s.logger.Error("failed to read stream event", zap.Error(err), zap.String("stream", stream))
+		return nil, NewGetError("sqlite", "event", err)
+	}
+	return &recordsIterator{records: records}, nil
+}
+
+// Pending returns up to limit events which are pending dispatch, in the order they were appended,
+// from the outbox table and implements the interface Outbox
+func (s *SQLite) Pending(ctx context.Context, limit int) ([]*Record, error) {
+	if !s.config.Outbox {
+		return nil, NewOutboxDisabledError("sqlite")
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT e.position, e.data FROM outbox o
+		JOIN events e ON e.position = o.position
+		ORDER BY o.position
+		LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		s.logger.Error("failed to find pending events", zap.Error(err))
+		return nil, NewGetError("sqlite", "pending events", err)
+	}
+
+	iter := &sqliteIterator{
+		logger: s.logger,
+		rows:   rows,
+	}
+	defer iter.Close(ctx)
+
+	var records []*Record
+	for {
+		rec, err := iter.Next(ctx)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// Dispatched deletes the events from the outbox table and implements the interface Outbox
+func (s *SQLite) Dispatched(ctx context.Context, records ...*Record) error {
+	if !s.config.Outbox {
+		return NewOutboxDisabledError("sqlite")
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(records))
+	args := make([]interface{}, len(records))
+	for i, rec := range records {
+		placeholders[i] = "?"
+		args[i] = int64(rec.Position)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM outbox WHERE position IN (`+strings.Join(placeholders, ", ")+`)`, args...)
+	if err != nil {
+		s.logger.Error("failed to mark events as dispatched", zap.Error(err), zap.Int("events", len(records)))
+		return NewPutError("sqlite", "outbox", err)
+	}
+	return nil
+}
+
+// Checkpoint returns the position a consumer group has committed and implements the interface Checkpointer
+func (s *SQLite) Checkpoint(ctx context.Context, group string) (uint64, error) {
+	var position int64
+	err := s.db.QueryRowContext(ctx, `SELECT position FROM checkpoints WHERE consumer_group = ?`, group).Scan(&position)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		s.logger.Error("failed to get checkpoint", zap.Error(err), zap.String("group", group))
+		return 0, NewGetError("sqlite", "checkpoint", err)
+	}
+	return uint64(position), nil
+}
+
+// Commit moves the checkpoint of a consumer group forward to the position and implements the interface Checkpointer
+func (s *SQLite) Commit(ctx context.Context, group string, position uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO checkpoints (consumer_group, position) VALUES (?, ?)
+		ON CONFLICT (consumer_group) DO UPDATE SET position = excluded.position
+		WHERE excluded.position > checkpoints.position`,
+		group, int64(position),
+	)
+	if err != nil {
+		s.logger.Error("failed to commit checkpoint", zap.Error(err), zap.String("group", group), zap.Uint64("position", position))
+		return NewPutError("sqlite", "checkpoint", err)
+	}
+	return nil
+}
+
+// Iterate returns an Iterator over the events in sqlite which match the filter in the order they were appended
+// and implements the interface Iterable. Events appended after Iterate is called are not returned.
+func (s *SQLite) Iterate(ctx context.Context, filter Filter) (Iterator, error) {
+	where, args := newSQLiteFilter(filter)
+	query := `SELECT position, data FROM events`
+	if where != "" {
+		query += ` WHERE ` + where
+	}
+	query += ` ORDER BY position`
+
+	s.logger.Debug("attempting to find events")
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		s.logger.Error("failed to find events", zap.Error(err))
+		return nil, NewGetError("sqlite", "event", err)
+	}
+	s.logger.Debug("successfully found events")
+
+	return &sqliteIterator{
+		logger: s.logger,
+		rows:   rows,
+		// extension attributes keep their json type in the data column,
+		// so they are matched once decoded rather than in the query
+		filter: Filter{Extensions: filter.Extensions},
+	}, nil
+}
+
+// Subscribe returns an Iterator over the events in sqlite which match the filter, which waits for matching events
+// to be appended once it has returned every stored event, and implements the interface Subscribable. Appends from
+// other processes are only noticed by polling, since only appends from this process signal subscriptions.
+func (s *SQLite) Subscribe(ctx context.Context, filter Filter) (Iterator, error) {
+	return newSubscription(s, filter, &s.appended, nil), nil
+}
+
+type sqliteIterator struct {
+	logger *zap.Logger
+	rows   *sql.Rows
+	filter Filter
+}
+
+// Next decodes the next event from the sqlite rows and implements the interface Iterator
+func (it *sqliteIterator) Next(ctx context.Context) (*Record, error) {
+	for it.rows.Next() {
+		var position int64
+		var data string
+		err := it.rows.Scan(&position, &data)
+		if err != nil {
+			it.logger.Error("failed to read next event", zap.Error(err))
+			return nil, NewGetError("sqlite", "event", err)
+		}
+
+		rec, err := decodeSQLRecord(position, []byte(data))
+		if err != nil {
+			it.logger.Error("failed to decode event", zap.Error(err))
+			return nil, err
+		}
+		if !it.filter.match(rec) {
+			continue
+		}
+		return rec, nil
+	}
+
+	err := it.rows.Err()
+	if err != nil {
+		it.logger.Error("failed to read next event", zap.Error(err))
+		return nil, NewGetError("sqlite", "event", err)
+	}
+	return nil, io.EOF
+}
+
+// Close closes the underlying sqlite rows and implements the interface Iterator
+func (it *sqliteIterator) Close(ctx context.Context) error {
+	return it.rows.Close()
+}
+
+func decodeSQLRecord(position int64, data []byte) (*Record, error) {
+	var ev event.Event
+	err := ev.UnmarshalJSON(data)
+	if err != nil {
+		return nil, NewMarshalError("json", "*event.Event", err)
+	}
+	return &Record{
+		Position: uint64(position),
+		Event:    &ev,
+	}, nil
+}
+
+// newSQLiteFilter returns the where clause and its arguments for every part of the filter except its extensions
+func newSQLiteFilter(filter Filter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	in := func(column string, values []string) {
+		conds = append(conds, column+" IN (?"+strings.Repeat(", ?", len(values)-1)+")")
+		for _, v := range values {
+			args = append(args, v)
+		}
+	}
+
+	if filter.AfterPosition > 0 {
+		conds = append(conds, "position > ?")
+		args = append(args, int64(filter.AfterPosition))
+	}
+	if len(filter.Types) > 0 {
+		in("type", filter.Types)
+	}
+	if len(filter.Sources) > 0 {
+		in("source", filter.Sources)
+	}
+	if len(filter.Subjects) > 0 {
+		in("subject", filter.Subjects)
+	}
+	if !filter.StartTime.IsZero() {
+		conds = append(conds, "time >= ?")
+		args = append(args, filter.StartTime.UnixNano())
+	}
+	if !filter.EndTime.IsZero() {
+		conds = append(conds, "time < ?")
+		args = append(args, filter.EndTime.UnixNano())
+	}
+	return strings.Join(conds, " AND "), args
+}
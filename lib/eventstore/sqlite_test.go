@@ -0,0 +1,317 @@
+package eventstore
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLiteTestStore(t *testing.T, config SQLiteConfig) *SQLite {
+	if config.Path == "" {
+		config.Path = filepath.Join(t.TempDir(), "events.db")
+	}
+	s, err := NewSQLite(context.Background(), config)
+	require.NoError(t, err, "failed to create sqlite event store")
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSQLiteConfig_Validate(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config - no path", func(t *testing.T) {
+		conf := SQLiteConfig{}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - stream extension", func(t *testing.T) {
+		conf := SQLiteConfig{Path: "events.db", StreamExtension: "Not_Valid"}
+		req.ErrorAs(conf.Validate(), &ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		conf := SQLiteConfig{Path: "events.db", StreamExtension: "aggregateid"}
+		req.NoError(conf.Validate(), "config should have validated")
+	})
+}
+
+func TestNewSQLite(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("nil ctx", func(t *testing.T) {
+		_, err := NewSQLite(nil, SQLiteConfig{})
+		req.ErrorContains(err, "context can not be nil", "error is not target error")
+	})
+
+	t.Run("unable to open database", func(t *testing.T) {
+		_, err := NewSQLite(context.Background(), SQLiteConfig{Path: filepath.Join(t.TempDir(), "missing", "events.db")})
+		var connErr *ConnectionError
+		req.ErrorAs(err, &connErr, "expected connection error")
+	})
+
+	t.Run("uses write-ahead logging", func(t *testing.T) {
+		s := newSQLiteTestStore(t, SQLiteConfig{})
+
+		var mode string
+		err := s.db.QueryRow("PRAGMA journal_mode").Scan(&mode)
+		req.NoError(err, "failed to query journal mode")
+		req.Equal("wal", mode, "journal mode not expected value")
+	})
+
+	t.Run("reopens an existing database", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.db")
+		s := newSQLiteTestStore(t, SQLiteConfig{Path: path})
+		_, err := s.Append(context.Background(), newMemoryTestEvent("1", "test"), AnyVersion)
+		req.NoError(err, "failed to put event")
+		req.NoError(s.Close(), "failed to close sqlite event store")
+
+		s = newSQLiteTestStore(t, SQLiteConfig{Path: path})
+		position, err := s.Append(context.Background(), newMemoryTestEvent("2", "test"), 1)
+		req.NoError(err, "failed to put event at expected version")
+		req.Equal(uint64(2), position, "position not expected value")
+	})
+}
+
+func TestSQLite_Append(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	t.Run("is idempotent", func(t *testing.T) {
+		s := newSQLiteTestStore(t, SQLiteConfig{})
+
+		ev := newMemoryTestEvent("1", "test")
+		position, err := s.Append(ctx, ev, AnyVersion)
+		req.NoError(err, "failed to put event")
+		req.Equal(uint64(1), position, "position not expected value")
+
+		samePosition, err := s.Append(ctx, ev, 0)
+		req.NoError(err, "re-appending the same event should succeed")
+		req.Equal(position, samePosition, "position not expected value")
+
+		conflicting := ev.Clone()
+		conflicting.SetType("other")
+		_, err = s.Append(ctx, &conflicting, AnyVersion)
+		var dupErr *DuplicateEventError
+		req.ErrorAs(err, &dupErr, "expected duplicate event error")
+	})
+
+	t.Run("checks the expected stream version", func(t *testing.T) {
+		s := newSQLiteTestStore(t, SQLiteConfig{StreamExtension: "aggregateid"})
+
+		ev := newMemoryTestEvent("1", "test")
+		ev.SetExtension("aggregateid", "a")
+		_, err := s.Append(ctx, ev, 0)
+		req.NoError(err, "failed to put event to new stream")
+
+		ev = newMemoryTestEvent("2", "test")
+		ev.SetExtension("aggregateid", "a")
+		_, err = s.Append(ctx, ev, 0)
+		var conflictErr *VersionConflictError
+		req.ErrorAs(err, &conflictErr, "expected version conflict error")
+		req.Equal("a", conflictErr.Stream, "stream not expected value")
+		req.Equal(uint64(1), conflictErr.Actual, "actual version not expected value")
+
+		_, err = s.Append(ctx, ev, 1)
+		req.NoError(err, "failed to put event at expected version")
+
+		var stream string
+		var version int64
+		err = s.db.QueryRow(`SELECT stream, version FROM events WHERE id = '2'`).Scan(&stream, &version)
+		req.NoError(err, "failed to find record")
+		req.Equal("a", stream, "stream not expected value")
+		req.Equal(int64(2), version, "version not expected value")
+	})
+
+	t.Run("is safe for concurrent use", func(t *testing.T) {
+		s := newSQLiteTestStore(t, SQLiteConfig{})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.Append(ctx, newMemoryTestEvent(string(rune('A'+i)), "test"), AnyVersion)
+			}(i)
+		}
+		wg.Wait()
+
+		iter, err := s.Iterate(ctx, Filter{})
+		req.NoError(err, "failed to iterate events")
+		records, err := readAll(ctx, iter)
+		req.NoError(err, "failed to read events")
+		req.Len(records, 20, "unexpected number of events")
+	})
+}
+
+func TestSQLite_AppendBatch(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	s := newSQLiteTestStore(t, SQLiteConfig{})
+
+	positions, err := s.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("1", "a"), newMemoryTestEvent("2", "b")}, AnyVersion)
+	req.NoError(err, "failed to put batch")
+	req.Equal([]uint64{1, 2}, positions, "positions not expected value")
+
+	_, err = s.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("3", "c"), newMemoryTestEvent("4", "a")}, 0)
+	var conflictErr *VersionConflictError
+	req.ErrorAs(err, &conflictErr, "expected version conflict error")
+
+	var count int
+	err = s.db.QueryRow(`SELECT COUNT(*) FROM events`).Scan(&count)
+	req.NoError(err, "failed to count events")
+	req.Equal(2, count, "no events should have been stored from the failed batch")
+}
+
+func TestSQLite_Get(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	s := newSQLiteTestStore(t, SQLiteConfig{})
+
+	_, err := s.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("1", "a"), newMemoryTestEvent("2", "b")}, AnyVersion)
+	req.NoError(err, "failed to put batch")
+
+	rec, err := s.Get(ctx, "memory_test", "2")
+	req.NoError(err, "failed to get event")
+	req.Equal(uint64(2), rec.Position, "position not expected value")
+	req.Equal("b", rec.Event.Subject(), "subject not expected value")
+
+	_, err = s.Get(ctx, "other_source", "1")
+	var notFoundErr *EventNotFoundError
+	req.ErrorAs(err, &notFoundErr, "expected event not found error")
+}
+
+func TestSQLite_Outbox(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	t.Run("outbox disabled", func(t *testing.T) {
+		s := newSQLiteTestStore(t, SQLiteConfig{})
+		_, err := s.Pending(ctx, 10)
+		var disabledErr *OutboxDisabledError
+		req.ErrorAs(err, &disabledErr, "expected outbox disabled error")
+	})
+
+	s := newSQLiteTestStore(t, SQLiteConfig{Outbox: true})
+
+	_, err := s.AppendBatch(ctx, []*event.Event{newMemoryTestEvent("1", "a"), newMemoryTestEvent("2", "b"), newMemoryTestEvent("3", "c")}, AnyVersion)
+	req.NoError(err, "failed to put batch")
+
+	pending, err := s.Pending(ctx, 2)
+	req.NoError(err, "failed to get pending events")
+	req.Len(pending, 2, "unexpected number of pending events")
+	req.Equal(uint64(1), pending[0].Position, "position not expected value")
+	req.Equal("b", pending[1].Event.Subject(), "subject not expected value")
+
+	req.NoError(s.Dispatched(ctx, pending...), "failed to mark events as dispatched")
+	pending, err = s.Pending(ctx, 10)
+	req.NoError(err, "failed to get pending events")
+	req.Len(pending, 1, "unexpected number of pending events")
+	req.Equal(uint64(3), pending[0].Position, "position not expected value")
+
+	req.NoError(s.Dispatched(ctx, pending...), "failed to mark events as dispatched")
+	pending, err = s.Pending(ctx, 10)
+	req.NoError(err, "failed to get pending events")
+	req.Empty(pending, "every event should have been dispatched")
+}
+
+func TestSQLite_Checkpoint(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+	s := newSQLiteTestStore(t, SQLiteConfig{})
+
+	position, err := s.Checkpoint(ctx, "projector")
+	req.NoError(err, "failed to get checkpoint")
+	req.Zero(position, "a group which has not committed should be at position 0")
+
+	req.NoError(s.Commit(ctx, "projector", 5), "failed to commit checkpoint")
+	req.NoError(s.Commit(ctx, "projector", 3), "failed to commit earlier checkpoint")
+
+	position, err = s.Checkpoint(ctx, "projector")
+	req.NoError(err, "failed to get checkpoint")
+	req.Equal(uint64(5), position, "checkpoint should not have moved back")
+}
+
+func TestSQLite_ReadStream(t *testing.T) {
+	s := newSQLiteTestStore(t, SQLiteConfig{})
+
+	appendStreamTestEvents(t, s)
+	testReadStream(t, s)
+}
+
+func TestSQLite_SubscribeConcurrentAppends(t *testing.T) {
+	s := newSQLiteTestStore(t, SQLiteConfig{})
+
+	testSubscribeConcurrentAppends(t, s)
+}
+
+func TestSQLite_IterateConcurrentAppends(t *testing.T) {
+	s := newSQLiteTestStore(t, SQLiteConfig{})
+
+	testIterateConcurrentAppends(t, s)
+}
+
+func TestSQLite_Iterate(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	s := newSQLiteTestStore(t, SQLiteConfig{})
+
+	now := time.Now().UTC()
+	for i, id := range []string{"1", "2", "3"} {
+		ev := newMemoryTestEvent(id, "test")
+		ev.SetTime(now.Add(time.Duration(i) * time.Minute))
+		ev.SetExtension("retries", i)
+		_, err := s.Append(ctx, ev, AnyVersion)
+		req.NoError(err, "failed to put event")
+	}
+	other := newMemoryTestEvent("4", "other")
+	other.SetType("other")
+	_, err := s.Append(ctx, other, AnyVersion)
+	req.NoError(err, "failed to put event")
+
+	testCases := []struct {
+		name   string
+		filter Filter
+		ids    []string
+	}{
+		{name: "all", filter: Filter{}, ids: []string{"1", "2", "3", "4"}},
+		{name: "after position", filter: Filter{AfterPosition: 2}, ids: []string{"3", "4"}},
+		{name: "types", filter: Filter{Types: []string{"test"}}, ids: []string{"1", "2", "3"}},
+		{name: "subjects", filter: Filter{Subjects: []string{"other", "missing"}}, ids: []string{"4"}},
+		{name: "time range", filter: Filter{StartTime: now.Add(time.Minute), EndTime: now.Add(2 * time.Minute)}, ids: []string{"2"}},
+		{name: "extensions", filter: Filter{Extensions: map[string]string{"retries": "2"}}, ids: []string{"3"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			iter, err := s.Iterate(ctx, testCase.filter)
+			req.NoError(err, "failed to iterate events")
+
+			records, err := readAll(ctx, iter)
+			req.NoError(err, "failed to read events")
+
+			var ids []string
+			for _, rec := range records {
+				ids = append(ids, rec.Event.ID())
+			}
+			req.Equal(testCase.ids, ids, "events not expected value")
+		})
+	}
+
+	t.Run("returns the stored event", func(t *testing.T) {
+		iter, err := s.Iterate(ctx, Filter{})
+		req.NoError(err, "failed to iterate events")
+		records, err := readAll(ctx, iter)
+		req.NoError(err, "failed to read events")
+
+		req.Equal(uint64(4), records[3].Position, "position not expected value")
+		req.True(sameEvent(other, records[3].Event), "event not expected value")
+	})
+}
@@ -1,13 +1,343 @@
 package eventstore
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
 )
 
+// AnyVersion can be passed to Append as the expected version to skip the optimistic concurrency check
+const AnyVersion = math.MaxUint64
+
 // AppendOnly appends an event into an event store
 type AppendOnly interface {
-	// AppendEvent pushes an event to the event store and assumes that the event has already been validated before receiving
-	Append(ctx context.Context, event *event.Event) error
+	// AppendEvent pushes an event to the event store and assumes that the event has already been validated before receiving.
+	// The position assigned to the event is returned and is strictly greater than the position of any previously appended event.
+	//
+	// Unless expectedVersion is AnyVersion, a *VersionConflictError is returned if the stream the event belongs to
+	// is not currently at expectedVersion. A stream with no events, including the stream of an event without a
+	// stream id, is at version 0. An append which keeps conflicting with concurrent appends to the same stream
+	// or event is given up on after a limited number of attempts with a *RetryLimitError, while appends which
+	// only conflict over positions are retried until they succeed or ctx is done.
+	//
+	// Events are unique by their id and source, so appends can be retried safely. Appending an identical event
+	// again returns the position it was originally assigned, without checking expectedVersion since its stream
+	// has moved on once it was appended, while appending a different event with the same id and source returns
+	// a *DuplicateEventError.
+	Append(ctx context.Context, event *event.Event, expectedVersion uint64) (uint64, error)
+}
+
+// BatchAppendOnly appends several events into an event store at once
+type BatchAppendOnly interface {
+	// AppendBatch pushes events to the event store such that either all of them are stored or none of them are,
+	// and assumes that the events have already been validated before receiving. The positions assigned to the
+	// events are returned in the same order as the events.
+	//
+	// Unless expectedVersion is AnyVersion, a *VersionConflictError is returned if any stream the events belong
+	// to is not at expectedVersion before the batch is appended. Events are unique by their id and source like
+	// they are for Append, so appending a batch which has already been appended returns the positions its
+	// events were originally assigned.
+	AppendBatch(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error)
+}
+
+// StreamOf returns the id of the stream an event belongs to. Events are grouped into streams by
+// their subject, or by the value of the named extension attribute if extension is non-empty.
+// An empty string is returned if the event does not belong to any stream.
+func StreamOf(ev *event.Event, extension string) string {
+	if extension == "" {
+		return ev.Subject()
+	}
+
+	v, ok := ev.Extensions()[extension]
+	if !ok {
+		return ""
+	}
+	s, err := types.Format(v)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// PartitionKeyExtension is the extension attribute defined by the CloudEvents partitioning extension
+const PartitionKeyExtension = "partitionkey"
+
+// PartitionOf returns which of n partitions an event belongs to, so that the events of each partition can be
+// processed in order while different partitions are processed in parallel. Events are partitioned by a hash of
+// their partitionkey extension attribute, or of their subject if they do not have one, so the events of an
+// aggregate stay in the same partition. Events with neither are partitioned by their source.
+func PartitionOf(ev *event.Event, n uint32) uint32 {
+	if n <= 1 {
+		return 0
+	}
+
+	key := ev.Subject()
+	if v, ok := ev.Extensions()[PartitionKeyExtension]; ok {
+		if s, err := types.Format(v); err == nil && s != "" {
+			key = s
+		}
+	}
+	if key == "" {
+		key = ev.Source()
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32() % n
+}
+
+// Record is an event along with the position it was assigned when appended to an event store
+type Record struct {
+	// Position is the global position of the event in the event store. Positions start at 1.
+	Position uint64
+
+	// Version is the version of the stream of the event once it was appended, i.e. the first event of a
+	// stream is version 1. It is only set for records returned by ReadStream.
+	Version uint64
+
+	// Event is the stored event
+	Event *event.Event
+}
+
+// Iterator reads events from an event store one at a time
+type Iterator interface {
+	// Next returns the next event from the event store. io.EOF is returned once there are no more events.
+	Next(ctx context.Context) (*Record, error)
+
+	// Close releases any resources held by the iterator
+	Close(ctx context.Context) error
+}
+
+// Iterable iterates over the events in an event store. Event stores commit events in the order of their
+// positions, so iterating again after the position of the last event returned resumes without missing any
+// events, even while events are being appended concurrently.
+type Iterable interface {
+	// Iterate returns an Iterator over the events in the event store which match the filter in the order they were appended
+	Iterate(ctx context.Context, filter Filter) (Iterator, error)
+}
+
+// Subscribable is implemented by event stores which can wait for events to be appended
+type Subscribable interface {
+	// Subscribe returns an Iterator which first returns the events in the event store which match the filter, like
+	// Iterate, and then returns matching events as they are appended instead of io.EOF. Each event is returned once
+	// and in the order they were appended. Next blocks until a matching event has been appended or its context is done.
+	Subscribe(ctx context.Context, filter Filter) (Iterator, error)
+}
+
+// Gettable looks up single events in an event store
+type Gettable interface {
+	// Get returns the event with the given source and id. A *EventNotFoundError is returned if there is no such event.
+	Get(ctx context.Context, source, id string) (*Record, error)
+}
+
+// Checkpointer persists how far consumer groups have processed the log, so the members of a
+// consumer group can resume from where the group left off, e.g. once they reconnect
+type Checkpointer interface {
+	// Checkpoint returns the position a consumer group has committed, or 0 if it has never committed one
+	Checkpoint(ctx context.Context, group string) (uint64, error)
+
+	// Commit records that a consumer group has processed every event up to and including the position.
+	// Checkpoints only move forward, so committing a position before the current checkpoint is ignored.
+	Commit(ctx context.Context, group string, position uint64) error
+}
+
+// Store is an event store which events can be appended to, iterated over, read by stream and
+// looked up in, and which persists the checkpoints of consumer groups, such as the event stores
+// wrapped by caches like RedisCache
+type Store interface {
+	AppendOnly
+	BatchAppendOnly
+	Iterable
+	StreamReader
+	Gettable
+	Checkpointer
+}
+
+// Outbox is implemented by event stores which can record that an event is pending dispatch atomically
+// with appending it, so that a relay can notify of every appended event at least once, even if the
+// process exits between appending an event and notifying of it. Event stores only record pending
+// events when the outbox is enabled in their config, and return a *OutboxDisabledError otherwise.
+type Outbox interface {
+	// Pending returns up to limit events which are pending dispatch, in the order they were appended
+	Pending(ctx context.Context, limit int) ([]*Record, error)
+
+	// Dispatched marks the events as no longer pending dispatch. Events which are not pending are ignored.
+	Dispatched(ctx context.Context, records ...*Record) error
+}
+
+// Filter restricts which events are returned when iterating over an event store.
+// The zero value matches every event.
+type Filter struct {
+	// AfterPosition only matches events appended after this position
+	AfterPosition uint64
+
+	// Types only matches events with one of these types
+	Types []string
+
+	// Sources only matches events from one of these sources
+	Sources []string
+
+	// Subjects only matches events with one of these subjects
+	Subjects []string
+
+	// StartTime only matches events which occurred at or after this time
+	StartTime time.Time
+
+	// EndTime only matches events which occurred before this time
+	EndTime time.Time
+
+	// Extensions only matches events whose extension attributes have all of these values
+	Extensions map[string]string
+}
+
+// match reports whether a record satisfies the filter, for event stores which
+// can not push the filter down into their queries
+func (f Filter) match(rec *Record) bool {
+	if rec.Position <= f.AfterPosition {
+		return false
+	}
+
+	ev := rec.Event
+	if len(f.Types) > 0 && !contains(f.Types, ev.Type()) {
+		return false
+	}
+	if len(f.Sources) > 0 && !contains(f.Sources, ev.Source()) {
+		return false
+	}
+	if len(f.Subjects) > 0 && !contains(f.Subjects, ev.Subject()) {
+		return false
+	}
+
+	if !f.StartTime.IsZero() || !f.EndTime.IsZero() {
+		t := ev.Time()
+		if t.IsZero() {
+			return false
+		}
+		if !f.StartTime.IsZero() && t.Before(f.StartTime) {
+			return false
+		}
+		if !f.EndTime.IsZero() && !t.Before(f.EndTime) {
+			return false
+		}
+	}
+
+	for name, want := range f.Extensions {
+		v, ok := ev.Extensions()[name]
+		if !ok {
+			return false
+		}
+		s, err := types.Format(v)
+		if err != nil || s != want {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sameEvent reports whether two events have the same context attributes and data.
+// JSON data is compared by value since event stores may not preserve its formatting.
+func sameEvent(a, b *event.Event) bool {
+	if a.SpecVersion() != b.SpecVersion() ||
+		a.ID() != b.ID() ||
+		a.Source() != b.Source() ||
+		a.Type() != b.Type() ||
+		a.Subject() != b.Subject() ||
+		a.DataContentType() != b.DataContentType() ||
+		a.DataSchema() != b.DataSchema() ||
+		!a.Time().Equal(b.Time()) {
+		return false
+	}
+
+	if len(a.Extensions()) != len(b.Extensions()) {
+		return false
+	}
+	for name, av := range a.Extensions() {
+		bv, ok := b.Extensions()[name]
+		if !ok {
+			return false
+		}
+		as, aerr := types.Format(av)
+		bs, berr := types.Format(bv)
+		if aerr != nil || berr != nil || as != bs {
+			return false
+		}
+	}
+
+	if bytes.Equal(a.Data(), b.Data()) {
+		return true
+	}
+	var ad, bd interface{}
+	if json.Unmarshal(a.Data(), &ad) != nil || json.Unmarshal(b.Data(), &bd) != nil {
+		return false
+	}
+	return reflect.DeepEqual(ad, bd)
+}
+
+// HashKey returns a fixed length key made of hex digits, for databases and caches which restrict
+// the characters or length of their keys, such as '/' which is common in event sources, and for
+// notifiers deriving deduplication ids. The length of each part is included so that no two lists
+// of parts produce the same key.
+func HashKey(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(strconv.Itoa(len(part))))
+		h.Write([]byte{'#'})
+		h.Write([]byte(part))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cachedRecord is how a Record is stored by caches
+type cachedRecord struct {
+	Position uint64          `json:"position"`
+	Event    json.RawMessage `json:"event"`
+}
+
+func encodeCachedRecord(rec *Record) (string, error) {
+	ev, err := rec.Event.MarshalJSON()
+	if err != nil {
+		return "", NewMarshalError("*event.Event", "json", err)
+	}
+	b, err := json.Marshal(cachedRecord{Position: rec.Position, Event: ev})
+	if err != nil {
+		return "", NewMarshalError("cached record", "json", err)
+	}
+	return string(b), nil
+}
+
+func decodeCachedRecord(s string) (*Record, error) {
+	var rec cachedRecord
+	err := json.Unmarshal([]byte(s), &rec)
+	if err != nil {
+		return nil, NewMarshalError("json", "cached record", err)
+	}
+
+	var ev event.Event
+	err = ev.UnmarshalJSON(rec.Event)
+	if err != nil {
+		return nil, NewMarshalError("json", "*event.Event", err)
+	}
+	return &Record{
+		Position: rec.Position,
+		Event:    &ev,
+	}, nil
 }
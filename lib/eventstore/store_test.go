@@ -0,0 +1,171 @@
+package eventstore
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamOf(t *testing.T) {
+	req := require.New(t)
+
+	ev := event.New()
+	ev.SetSubject("order-123")
+	ev.SetExtension("aggregate", "customer-456")
+	ev.SetExtension("shard", 7)
+
+	t.Run("subject", func(t *testing.T) {
+		req.Equal("order-123", StreamOf(&ev, ""), "stream not expected value")
+	})
+
+	t.Run("string extension", func(t *testing.T) {
+		req.Equal("customer-456", StreamOf(&ev, "aggregate"), "stream not expected value")
+	})
+
+	t.Run("integer extension", func(t *testing.T) {
+		req.Equal("7", StreamOf(&ev, "shard"), "stream not expected value")
+	})
+
+	t.Run("missing extension", func(t *testing.T) {
+		req.Equal("", StreamOf(&ev, "missing"), "stream not expected value")
+	})
+}
+
+func TestPartitionOf(t *testing.T) {
+	req := require.New(t)
+
+	newEvent := func(source, subject, partitionKey string) *event.Event {
+		ev := event.New()
+		ev.SetSource(source)
+		ev.SetSubject(subject)
+		if partitionKey != "" {
+			ev.SetExtension(PartitionKeyExtension, partitionKey)
+		}
+		return &ev
+	}
+
+	t.Run("single partition", func(t *testing.T) {
+		req.Equal(uint32(0), PartitionOf(newEvent("a", "order-1", ""), 1), "partition not expected value")
+	})
+
+	t.Run("partition key", func(t *testing.T) {
+		ev := newEvent("a", "order-1", "customer-1")
+		req.Equal(PartitionOf(newEvent("b", "order-2", "customer-1"), 16), PartitionOf(ev, 16), "events with the same partition key should share a partition")
+	})
+
+	t.Run("subject", func(t *testing.T) {
+		ev := newEvent("a", "order-1", "")
+		req.Equal(PartitionOf(newEvent("b", "order-1", ""), 16), PartitionOf(ev, 16), "events with the same subject should share a partition")
+	})
+
+	t.Run("source", func(t *testing.T) {
+		ev := newEvent("a", "", "")
+		req.Equal(PartitionOf(newEvent("a", "", ""), 16), PartitionOf(ev, 16), "events from the same source should share a partition")
+	})
+
+	t.Run("spread over partitions", func(t *testing.T) {
+		seen := make(map[uint32]bool)
+		for i := 0; i < 100; i++ {
+			p := PartitionOf(newEvent("a", "order-"+strconv.Itoa(i), ""), 4)
+			req.Less(p, uint32(4), "partition out of range")
+			seen[p] = true
+		}
+		req.Len(seen, 4, "events should be spread over every partition")
+	})
+}
+
+func TestSameEvent(t *testing.T) {
+	req := require.New(t)
+
+	newEvent := func() *event.Event {
+		ev := event.New()
+		ev.SetID("1")
+		ev.SetSource("test")
+		ev.SetType("test")
+		ev.SetSubject("test")
+		ev.SetExtension("tenant", "acme")
+		ev.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": "world", "count": 1})
+		return &ev
+	}
+
+	t.Run("identical events", func(t *testing.T) {
+		req.True(sameEvent(newEvent(), newEvent()), "events should be the same")
+	})
+
+	t.Run("differently formatted json data", func(t *testing.T) {
+		a, b := newEvent(), newEvent()
+		b.DataEncoded = []byte(`{ "count": 1, "hello": "world" }`)
+		req.True(sameEvent(a, b), "events should be the same")
+	})
+
+	t.Run("different data", func(t *testing.T) {
+		a, b := newEvent(), newEvent()
+		b.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": "there"})
+		req.False(sameEvent(a, b), "events should not be the same")
+	})
+
+	t.Run("different attributes", func(t *testing.T) {
+		a, b := newEvent(), newEvent()
+		b.SetType("other")
+		req.False(sameEvent(a, b), "events should not be the same")
+	})
+
+	t.Run("different extensions", func(t *testing.T) {
+		a, b := newEvent(), newEvent()
+		b.SetExtension("tenant", "other")
+		req.False(sameEvent(a, b), "events should not be the same")
+	})
+}
+
+func TestFilter_match(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Now().UTC()
+	ev := event.New()
+	ev.SetID("1")
+	ev.SetSource("test")
+	ev.SetType("test")
+	ev.SetSubject("test")
+	ev.SetTime(now)
+	ev.SetExtension("tenant", "acme")
+	ev.SetExtension("retries", 3)
+	rec := &Record{Position: 5, Event: &ev}
+
+	testCases := []struct {
+		name   string
+		filter Filter
+		match  bool
+	}{
+		{name: "empty filter", filter: Filter{}, match: true},
+		{name: "after earlier position", filter: Filter{AfterPosition: 4}, match: true},
+		{name: "after same position", filter: Filter{AfterPosition: 5}, match: false},
+		{name: "matching type", filter: Filter{Types: []string{"other", "test"}}, match: true},
+		{name: "different type", filter: Filter{Types: []string{"other"}}, match: false},
+		{name: "different source", filter: Filter{Sources: []string{"other"}}, match: false},
+		{name: "different subject", filter: Filter{Subjects: []string{"other"}}, match: false},
+		{name: "within time range", filter: Filter{StartTime: now, EndTime: now.Add(time.Second)}, match: true},
+		{name: "before time range", filter: Filter{StartTime: now.Add(time.Second)}, match: false},
+		{name: "after time range", filter: Filter{EndTime: now}, match: false},
+		{name: "matching extensions", filter: Filter{Extensions: map[string]string{"tenant": "acme", "retries": "3"}}, match: true},
+		{name: "different extension", filter: Filter{Extensions: map[string]string{"tenant": "other"}}, match: false},
+		{name: "missing extension", filter: Filter{Extensions: map[string]string{"missing": "acme"}}, match: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req.Equal(testCase.match, testCase.filter.match(rec), "match not expected value")
+		})
+	}
+}
+
+func TestHashKey(t *testing.T) {
+	req := require.New(t)
+
+	req.Equal(HashKey("a", "b"), HashKey("a", "b"), "key should be deterministic")
+	req.NotEqual(HashKey("a#1", "b"), HashKey("a", "1#b"), "key should not be ambiguous")
+	req.NotContains(HashKey("https://example.com/source", "id"), "/", "key should only contain hex digits")
+	req.Len(HashKey("https://example.com/source", "id"), 64, "key should have a fixed length")
+}
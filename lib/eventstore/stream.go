@@ -0,0 +1,125 @@
+package eventstore
+
+import (
+	"context"
+	"io"
+	"math"
+)
+
+// StreamReader reads the events of a single stream in the order of their versions
+type StreamReader interface {
+	// ReadStream returns an Iterator over the events of the stream, as returned by StreamOf, in the order of their
+	// versions, or in reverse if the options read backward. The records returned have their Version set. Reading
+	// a stream with no events, including the empty stream, returns no events.
+	ReadStream(ctx context.Context, stream string, opts ReadStreamOptions) (Iterator, error)
+}
+
+// ReadStreamOptions selects which events of a stream are read
+type ReadStreamOptions struct {
+	// FromVersion is the version of the first event read. If it is 0, reading forward starts
+	// from the first event of the stream and reading backward starts from the latest event.
+	FromVersion uint64
+
+	// MaxCount is the most events which are read, or 0 to read every event from FromVersion.
+	MaxCount uint64
+
+	// Backward reads the stream from FromVersion back towards the first event of the stream.
+	Backward bool
+}
+
+// span returns the first and last version, inclusive, of a stream at the version which are read.
+// The first version is greater than the last if no events are read.
+func (o ReadStreamOptions) span(version uint64) (first, last uint64) {
+	if !o.Backward {
+		first, last = o.FromVersion, version
+		if first == 0 {
+			first = 1
+		}
+		if o.MaxCount > 0 && first <= last && last-first >= o.MaxCount {
+			last = first + o.MaxCount - 1
+		}
+		return first, last
+	}
+
+	first, last = 1, version
+	if o.FromVersion > 0 && o.FromVersion < last {
+		last = o.FromVersion
+	}
+	if o.MaxCount > 0 && last >= first && last-first >= o.MaxCount {
+		first = last - o.MaxCount + 1
+	}
+	return first, last
+}
+
+// from returns the version reading starts from, inclusive, for event stores which query a range of
+// versions, which store versions as signed 64 bit integers
+func (o ReadStreamOptions) from() int64 {
+	if (o.Backward && o.FromVersion == 0) || o.FromVersion > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(o.FromVersion)
+}
+
+// limit returns the most events which are read, or 0 if the number of events is not limited,
+// for event stores which take the limit as a signed 64 bit integer
+func (o ReadStreamOptions) limit() int64 {
+	if o.MaxCount > math.MaxInt64 {
+		return 0
+	}
+	return int64(o.MaxCount)
+}
+
+// readStreamRecords selects the events of a stream which are read, given every record of the stream in the
+// order they were appended, so the record of each version is at the index before it. A copy of each selected
+// record is returned with its version set.
+func readStreamRecords(records []*Record, opts ReadStreamOptions) []*Record {
+	first, last := opts.span(uint64(len(records)))
+	if first > last {
+		return nil
+	}
+
+	selected := make([]*Record, 0, last-first+1)
+	for version := first; version <= last; version++ {
+		rec := records[version-1]
+		ev := rec.Event.Clone()
+		selected = append(selected, &Record{
+			Position: rec.Position,
+			Version:  version,
+			Event:    &ev,
+		})
+	}
+	if opts.Backward {
+		reverseRecords(selected)
+	}
+	return selected
+}
+
+func reverseRecords(records []*Record) {
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+}
+
+// recordsIterator iterates over records which have already been read from an event store
+type recordsIterator struct {
+	records []*Record
+}
+
+// Next returns the next record and implements the interface Iterator
+func (it *recordsIterator) Next(ctx context.Context) (*Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(it.records) == 0 {
+		return nil, io.EOF
+	}
+	rec := it.records[0]
+	it.records = it.records[1:]
+	return rec, nil
+}
+
+// Close implements the interface Iterator
+func (it *recordsIterator) Close(ctx context.Context) error {
+	it.records = nil
+	return nil
+}
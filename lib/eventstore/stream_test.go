@@ -0,0 +1,119 @@
+package eventstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// streamTestStore is an event store which can be tested by testReadStream
+type streamTestStore interface {
+	AppendOnly
+	StreamReader
+}
+
+// appendStreamTestEvents appends the events read by testReadStream, which are the events
+// of two streams interleaved with each other and with an event which has no stream
+func appendStreamTestEvents(t *testing.T, store streamTestStore) {
+	ctx := context.Background()
+	for _, ev := range [][2]string{
+		{"read-stream-1", "read-stream-a"},
+		{"read-stream-2", "read-stream-b"},
+		{"read-stream-3", "read-stream-a"},
+		{"read-stream-4", ""},
+		{"read-stream-5", "read-stream-a"},
+		{"read-stream-6", "read-stream-b"},
+		{"read-stream-7", "read-stream-a"},
+	} {
+		_, err := store.Append(ctx, newMemoryTestEvent(ev[0], ev[1]), AnyVersion)
+		require.NoError(t, err, "failed to put event")
+	}
+}
+
+// testReadStream reads the events appended by appendStreamTestEvents
+func testReadStream(t *testing.T, store streamTestStore) {
+	ctx := context.Background()
+	readStream := func(stream string, opts ReadStreamOptions) ([]string, []uint64) {
+		iter, err := store.ReadStream(ctx, stream, opts)
+		require.NoError(t, err, "failed to read stream")
+		records, err := readAll(ctx, iter)
+		require.NoError(t, err, "failed to read events")
+
+		var ids []string
+		var versions []uint64
+		for _, rec := range records {
+			ids = append(ids, rec.Event.ID())
+			versions = append(versions, rec.Version)
+		}
+		return ids, versions
+	}
+
+	t.Run("forward", func(t *testing.T) {
+		ids, versions := readStream("read-stream-a", ReadStreamOptions{})
+		require.Equal(t, []string{"read-stream-1", "read-stream-3", "read-stream-5", "read-stream-7"}, ids, "ids not expected value")
+		require.Equal(t, []uint64{1, 2, 3, 4}, versions, "versions not expected value")
+
+		ids, versions = readStream("read-stream-b", ReadStreamOptions{})
+		require.Equal(t, []string{"read-stream-2", "read-stream-6"}, ids, "ids not expected value")
+		require.Equal(t, []uint64{1, 2}, versions, "versions not expected value")
+	})
+
+	t.Run("forward from version with max count", func(t *testing.T) {
+		ids, versions := readStream("read-stream-a", ReadStreamOptions{FromVersion: 2, MaxCount: 2})
+		require.Equal(t, []string{"read-stream-3", "read-stream-5"}, ids, "ids not expected value")
+		require.Equal(t, []uint64{2, 3}, versions, "versions not expected value")
+	})
+
+	t.Run("backward", func(t *testing.T) {
+		ids, versions := readStream("read-stream-a", ReadStreamOptions{Backward: true})
+		require.Equal(t, []string{"read-stream-7", "read-stream-5", "read-stream-3", "read-stream-1"}, ids, "ids not expected value")
+		require.Equal(t, []uint64{4, 3, 2, 1}, versions, "versions not expected value")
+	})
+
+	t.Run("backward from version with max count", func(t *testing.T) {
+		ids, versions := readStream("read-stream-a", ReadStreamOptions{FromVersion: 3, MaxCount: 2, Backward: true})
+		require.Equal(t, []string{"read-stream-5", "read-stream-3"}, ids, "ids not expected value")
+		require.Equal(t, []uint64{3, 2}, versions, "versions not expected value")
+	})
+
+	t.Run("past the end of the stream", func(t *testing.T) {
+		ids, _ := readStream("read-stream-a", ReadStreamOptions{FromVersion: 5})
+		require.Empty(t, ids, "no events should have been read")
+	})
+
+	t.Run("stream with no events", func(t *testing.T) {
+		ids, _ := readStream("read-stream-c", ReadStreamOptions{})
+		require.Empty(t, ids, "no events should have been read")
+
+		ids, _ = readStream("", ReadStreamOptions{})
+		require.Empty(t, ids, "events without a stream should not have been read")
+	})
+}
+
+func TestReadStreamOptions_span(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Opts  ReadStreamOptions
+		First uint64
+		Last  uint64
+	}{
+		{Name: "forward", Opts: ReadStreamOptions{}, First: 1, Last: 10},
+		{Name: "forward from version", Opts: ReadStreamOptions{FromVersion: 4}, First: 4, Last: 10},
+		{Name: "forward with max count", Opts: ReadStreamOptions{FromVersion: 4, MaxCount: 3}, First: 4, Last: 6},
+		{Name: "forward with max count past the end", Opts: ReadStreamOptions{FromVersion: 9, MaxCount: 3}, First: 9, Last: 10},
+		{Name: "forward past the end", Opts: ReadStreamOptions{FromVersion: 11}, First: 11, Last: 10},
+		{Name: "backward", Opts: ReadStreamOptions{Backward: true}, First: 1, Last: 10},
+		{Name: "backward from version", Opts: ReadStreamOptions{FromVersion: 4, Backward: true}, First: 1, Last: 4},
+		{Name: "backward with max count", Opts: ReadStreamOptions{MaxCount: 3, Backward: true}, First: 8, Last: 10},
+		{Name: "backward from past the end", Opts: ReadStreamOptions{FromVersion: 20, MaxCount: 3, Backward: true}, First: 8, Last: 10},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			first, last := testCase.Opts.span(10)
+			require.Equal(t, testCase.First, first, "first version not expected value")
+			require.Equal(t, testCase.Last, last, "last version not expected value")
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package eventstore
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// subscribePollInterval is how often a subscription reads the event store again while waiting for events,
+// in case it is not notified of them, e.g. since they were appended by another process
+const subscribePollInterval = time.Second
+
+// Subscribe returns an Iterator which first returns the events in the store which match the filter and then
+// waits for matching events to be appended, using the mechanism of the store if it is Subscribable. Otherwise
+// the store is polled for appended events.
+func Subscribe(ctx context.Context, store Iterable, filter Filter) (Iterator, error) {
+	if s, ok := store.(Subscribable); ok {
+		return s.Subscribe(ctx, filter)
+	}
+	return newSubscription(store, filter, nil, nil), nil
+}
+
+// appendSignal lets any number of subscriptions wait for events to be appended
+type appendSignal struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// wait returns a channel which is closed once events are appended after wait was called
+func (s *appendSignal) wait() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ch == nil {
+		s.ch = make(chan struct{})
+	}
+	return s.ch
+}
+
+// broadcast wakes every subscription which is waiting for events to be appended
+func (s *appendSignal) broadcast() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ch != nil {
+		close(s.ch)
+		s.ch = nil
+	}
+}
+
+// subscription implements Subscribe on top of Iterate. Whenever it reaches the end of the log it waits
+// to be signalled, or for the poll interval, and then iterates again after the position of the last event
+// it returned. Events are therefore never returned twice, and since stores commit events in the order of
+// their positions, none are missed.
+type subscription struct {
+	store  Iterable
+	filter Filter
+	signal *appendSignal
+	stop   func()
+
+	iter     Iterator
+	appended <-chan struct{}
+}
+
+// newSubscription constructs a *subscription which is woken by signal, if it is non-nil, and calls stop,
+// if it is non-nil, once it is closed
+func newSubscription(store Iterable, filter Filter, signal *appendSignal, stop func()) *subscription {
+	return &subscription{
+		store:  store,
+		filter: filter,
+		signal: signal,
+		stop:   stop,
+	}
+}
+
+// Next returns the next matching event, waiting for one to be appended if there are none, and implements the interface Iterator
+func (s *subscription) Next(ctx context.Context) (*Record, error) {
+	for {
+		if s.iter == nil {
+			// the signal is taken before iterating, so an event appended
+			// after the iterator reaches the end of the log is not missed
+			if s.signal != nil {
+				s.appended = s.signal.wait()
+			}
+			iter, err := s.store.Iterate(ctx, s.filter)
+			if err != nil {
+				return nil, err
+			}
+			s.iter = iter
+		}
+
+		rec, err := s.iter.Next(ctx)
+		if err == nil {
+			s.filter.AfterPosition = rec.Position
+			return rec, nil
+		}
+		if err != io.EOF {
+			return nil, err
+		}
+
+		err = s.iter.Close(ctx)
+		s.iter = nil
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *subscription) wait(ctx context.Context) error {
+	timer := time.NewTimer(subscribePollInterval)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.appended:
+	case <-timer.C:
+	}
+	return nil
+}
+
+// Close closes the current iterator and stops waiting for events to be appended, and implements the interface Iterator
+func (s *subscription) Close(ctx context.Context) error {
+	if s.stop != nil {
+		s.stop()
+		s.stop = nil
+	}
+	if s.iter == nil {
+		return nil
+	}
+	err := s.iter.Close(ctx)
+	s.iter = nil
+	return err
+}
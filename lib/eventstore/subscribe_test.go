@@ -0,0 +1,175 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+// iterableOnly hides every method of an event store except Iterate
+type iterableOnly struct {
+	Iterable
+}
+
+// concurrentTestStore is an event store which can be tested by testSubscribeConcurrentAppends
+// and testIterateConcurrentAppends
+type concurrentTestStore interface {
+	AppendOnly
+	Iterable
+}
+
+const (
+	concurrentAppenders = 4
+	concurrentAppends   = 25
+)
+
+// appendConcurrently appends events from the source from several goroutines at once,
+// returning the positions they were assigned in order
+func appendConcurrently(ctx context.Context, store AppendOnly, source string) ([]uint64, error) {
+	var mu sync.Mutex
+	var positions []uint64
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i := 0; i < concurrentAppenders; i++ {
+		i := i
+		g.Go(func() error {
+			for j := 0; j < concurrentAppends; j++ {
+				ev := newMemoryTestEvent(fmt.Sprintf("%s-%d-%d", source, i, j), fmt.Sprintf("%s-%d", source, i))
+				ev.SetSource(source)
+				position, err := store.Append(gctx, ev, AnyVersion)
+				if err != nil {
+					return err
+				}
+
+				mu.Lock()
+				positions = append(positions, position)
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	err := g.Wait()
+
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+	return positions, err
+}
+
+// testSubscribeConcurrentAppends subscribes to the store while events are appended to it
+// concurrently and checks every event is returned in the order of their positions
+func testSubscribeConcurrentAppends(t *testing.T, store concurrentTestStore) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	source := "subscribe_concurrent_test"
+	iter, err := Subscribe(ctx, store, Filter{Sources: []string{source}})
+	require.NoError(t, err, "failed to subscribe")
+	defer iter.Close(ctx)
+
+	var positions []uint64
+	appended := make(chan error, 1)
+	go func() {
+		var err error
+		positions, err = appendConcurrently(ctx, store, source)
+		appended <- err
+	}()
+
+	var received []uint64
+	for len(received) < concurrentAppenders*concurrentAppends {
+		rec, err := iter.Next(ctx)
+		require.NoError(t, err, "failed to read event, %d of the appended events were returned", len(received))
+		received = append(received, rec.Position)
+	}
+	require.NoError(t, <-appended, "failed to put events")
+	require.Equal(t, positions, received, "every appended event should have been returned in order")
+}
+
+// testIterateConcurrentAppends iterates over the store again after the last event returned while events
+// are appended to it concurrently and checks every event is returned in the order of their positions
+func testIterateConcurrentAppends(t *testing.T, store concurrentTestStore) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	source := "iterate_concurrent_test"
+	var positions []uint64
+	appended := make(chan error, 1)
+	go func() {
+		var err error
+		positions, err = appendConcurrently(ctx, store, source)
+		appended <- err
+	}()
+
+	var received []uint64
+	filter := Filter{Sources: []string{source}}
+	for len(received) < concurrentAppenders*concurrentAppends {
+		iter, err := store.Iterate(ctx, filter)
+		require.NoError(t, err, "failed to iterate")
+		records, err := readAll(ctx, iter)
+		require.NoError(t, err, "failed to read events, %d of the appended events were returned", len(received))
+
+		if len(records) == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		for _, rec := range records {
+			received = append(received, rec.Position)
+			filter.AfterPosition = rec.Position
+		}
+	}
+	require.NoError(t, <-appended, "failed to put events")
+	require.Equal(t, positions, received, "every appended event should have been returned in order")
+}
+
+func TestSubscribe(t *testing.T) {
+	req := require.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	m, err := NewMemory(MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	_, err = m.Append(ctx, newMemoryTestEvent("1", "subject"), AnyVersion)
+	req.NoError(err, "failed to put event")
+
+	t.Run("uses the mechanism of subscribable stores", func(t *testing.T) {
+		iter, err := Subscribe(ctx, m, Filter{})
+		req.NoError(err, "failed to subscribe")
+		defer iter.Close(ctx)
+
+		sub, ok := iter.(*subscription)
+		req.True(ok, "expected a subscription")
+		req.NotNil(sub.signal, "subscription should be signalled of appended events")
+	})
+
+	t.Run("polls other stores", func(t *testing.T) {
+		iter, err := Subscribe(ctx, iterableOnly{m}, Filter{})
+		req.NoError(err, "failed to subscribe")
+		defer iter.Close(ctx)
+
+		rec, err := iter.Next(ctx)
+		req.NoError(err, "failed to read event")
+		req.Equal(uint64(1), rec.Position, "position not expected value")
+
+		_, err = m.Append(ctx, newMemoryTestEvent("2", "subject"), AnyVersion)
+		req.NoError(err, "failed to put event")
+
+		rec, err = iter.Next(ctx)
+		req.NoError(err, "failed to read event")
+		req.Equal(uint64(2), rec.Position, "position not expected value")
+	})
+
+	t.Run("calls stop once closed", func(t *testing.T) {
+		stopped := false
+		iter := newSubscription(m, Filter{}, nil, func() { stopped = true })
+
+		_, err := iter.Next(ctx)
+		req.NoError(err, "failed to read event")
+		req.NoError(iter.Close(ctx), "failed to close subscription")
+		req.True(stopped, "stop should have been called")
+		req.Nil(iter.iter, "iterator should have been closed")
+	})
+}
@@ -0,0 +1,188 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azqueue"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azqueue/queueerror"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+const (
+	// azureQueueMaxMessageSize is the largest message, after encoding, a storage queue accepts
+	azureQueueMaxMessageSize = 64 * 1024
+
+	// azureQueueDataRefExtension is the CloudEvents extension attribute which reference
+	// messages are sent with in place of the event data
+	azureQueueDataRefExtension = "dataref"
+)
+
+// AzureQueueConfig defines the configuration to connect to Azure Queue Storage and which queue to enqueue events to
+type AzureQueueConfig struct {
+	// ConnectionString is the connection string of the storage account, e.g. the well known connection
+	// string of the Azurite emulator
+	ConnectionString string `mapstructure:"connection_string" validate:"required"`
+
+	// Queue is the queue every event is enqueued to. It is created if it does not exist.
+	Queue string `mapstructure:"queue" validate:"required"`
+
+	// MessageEncoding is how messages are encoded, one of: base64, none. It defaults to base64,
+	// which is what Azure Functions queue triggers expect by default.
+	MessageEncoding string `mapstructure:"message_encoding" validate:"omitempty,oneof=base64 none"`
+
+	// DataRef is the base URL of where the data of events can be retrieved from. Events which are
+	// too large to enqueue are sent without their data and with a "dataref" extension attribute of
+	// DataRef followed by "/events/", their escaped source, "/" and their escaped id.
+	DataRef string `mapstructure:"data_ref" validate:"omitempty,url"`
+}
+
+// Validate ensures azure queue config is correct
+func (a *AzureQueueConfig) Validate() error {
+	return validator.New().Struct(a)
+}
+
+// azureEnqueuer is the subset of the queue client used to enqueue events
+type azureEnqueuer interface {
+	EnqueueMessage(ctx context.Context, content string, o *azqueue.EnqueueMessageOptions) (azqueue.EnqueueMessagesResponse, error)
+}
+
+// AzureQueue enqueues events to an Azure storage queue as structured mode CloudEvents JSON.
+//
+// A storage queue message can be at most 64KB, so events which are too large are enqueued as a
+// reference message instead, which is the event without its data and with the "dataref" extension
+// attribute set to where the data can be retrieved from. Consumers can then get the whole event
+// from the event log by its source and id.
+type AzureQueue struct {
+	config AzureQueueConfig
+	logger *zap.Logger
+	queue  azureEnqueuer
+}
+
+// NewAzureQueue constructs a *AzureQueue and creates its queue if it does not exist
+func NewAzureQueue(ctx context.Context, config AzureQueueConfig) (*AzureQueue, error) {
+	if ctx == nil {
+		return nil, errors.New("context can not be nil")
+	}
+
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config, %w", err)
+	}
+
+	impl := &AzureQueue{
+		config: config,
+		logger: zap.L().With(zap.String("source", "AzureQueueNotifierImpl")),
+	}
+
+	err = impl.init(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init, %w", err)
+	}
+
+	return impl, nil
+}
+
+func (a *AzureQueue) init(ctx context.Context) error {
+	client, err := azqueue.NewQueueClientFromConnectionString(a.config.ConnectionString, a.config.Queue, nil)
+	if err != nil {
+		a.logger.Error("failed to create queue client", zap.Error(err))
+		return eventstore.NewConnectionError("azurequeue", err)
+	}
+
+	a.logger.Debug("attempting to create queue")
+	_, err = client.Create(ctx, nil)
+	if err != nil && !queueerror.HasCode(err, queueerror.QueueAlreadyExists) {
+		a.logger.Error("failed to create queue", zap.Error(err))
+		return eventstore.NewConnectionError("azurequeue", err)
+	}
+	a.logger.Debug("successfully created queue")
+
+	a.queue = client
+	return nil
+}
+
+// Notify enqueues the event to the configured queue and implements the interface Notifier
+func (a *AzureQueue) Notify(ctx context.Context, event *event.Event) error {
+	if event == nil {
+		return errors.New("event can not be nil")
+	}
+
+	content, err := a.message(event)
+	if err != nil {
+		return NewNotifyError("azurequeue", event.ID(), event.Source(), NewPermanentError(err))
+	}
+
+	_, err = a.queue.EnqueueMessage(ctx, content, nil)
+	if err != nil {
+		a.logger.Error(
+			"failed to enqueue event",
+			zap.String("queue", a.config.Queue),
+			zap.String("event_id", event.ID()),
+			zap.String("event_source", event.Source()),
+			zap.Error(err),
+		)
+		return NewNotifyError("azurequeue", event.ID(), event.Source(), err)
+	}
+	a.logger.Debug(
+		"enqueued event",
+		zap.String("queue", a.config.Queue),
+		zap.String("event_id", event.ID()),
+		zap.String("event_source", event.Source()),
+	)
+	return nil
+}
+
+// message returns the encoded message for the event, which is a reference message if the event is too large
+func (a *AzureQueue) message(event *event.Event) (string, error) {
+	content, err := a.encode(event)
+	if err != nil {
+		return "", err
+	}
+	if len(content) <= azureQueueMaxMessageSize {
+		return content, nil
+	}
+
+	a.logger.Debug(
+		"event is too large to enqueue, so enqueueing a reference to it",
+		zap.String("event_id", event.ID()),
+		zap.String("event_source", event.Source()),
+		zap.Int("size", len(content)),
+	)
+	ref := event.Clone()
+	ref.DataEncoded = nil
+	ref.DataBase64 = false
+	ref.SetDataContentType("")
+	ref.SetExtension(azureQueueDataRefExtension, a.dataRef(event))
+
+	content, err = a.encode(&ref)
+	if err != nil {
+		return "", err
+	}
+	if len(content) > azureQueueMaxMessageSize {
+		return "", fmt.Errorf("reference message is %d bytes but at most %d are allowed", len(content), azureQueueMaxMessageSize)
+	}
+	return content, nil
+}
+
+func (a *AzureQueue) encode(event *event.Event) (string, error) {
+	b, err := event.MarshalJSON()
+	if err != nil {
+		return "", eventstore.NewMarshalError("*event.Event", "json", err)
+	}
+	if a.config.MessageEncoding == "none" {
+		return string(b), nil
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func (a *AzureQueue) dataRef(event *event.Event) string {
+	return a.config.DataRef + "/events/" + url.PathEscape(event.Source()) + "/" + url.PathEscape(event.ID())
+}
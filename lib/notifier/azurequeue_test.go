@@ -0,0 +1,244 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azqueue"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.uber.org/zap"
+)
+
+// azuriteConnectionString is the well known connection string of the Azurite emulator
+const azuriteConnectionString = "DefaultEndpointsProtocol=http;AccountName=devstoreaccount1;" +
+	"AccountKey=Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw==;" +
+	"QueueEndpoint=http://127.0.0.1:10001/devstoreaccount1;"
+
+type fakeEnqueuer struct {
+	messages []string
+	err      error
+}
+
+func (q *fakeEnqueuer) EnqueueMessage(ctx context.Context, content string, o *azqueue.EnqueueMessageOptions) (azqueue.EnqueueMessagesResponse, error) {
+	if q.err != nil {
+		return azqueue.EnqueueMessagesResponse{}, q.err
+	}
+	q.messages = append(q.messages, content)
+	return azqueue.EnqueueMessagesResponse{}, nil
+}
+
+func decodeAzureMessage(t *testing.T, content string, encoding string) *event.Event {
+	b := []byte(content)
+	if encoding != "none" {
+		var err error
+		b, err = base64.StdEncoding.DecodeString(content)
+		require.NoError(t, err, "message should be base64 encoded")
+	}
+	var ev event.Event
+	require.NoError(t, ev.UnmarshalJSON(b), "message should be a cloudevent")
+	return &ev
+}
+
+func newLargeTestEvent(id string) *event.Event {
+	ev := newTestEvent(id, "test")
+	ev.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": strings.Repeat("world", 20*1024)})
+	return ev
+}
+
+func TestAzureQueueConfig_Validate(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config - no connection string", func(t *testing.T) {
+		conf := AzureQueueConfig{Queue: "events"}
+		req.ErrorAs(conf.Validate(), &eventstore.ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - no queue", func(t *testing.T) {
+		conf := AzureQueueConfig{ConnectionString: azuriteConnectionString}
+		req.ErrorAs(conf.Validate(), &eventstore.ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - unknown message encoding", func(t *testing.T) {
+		conf := AzureQueueConfig{ConnectionString: azuriteConnectionString, Queue: "events", MessageEncoding: "utf8"}
+		req.ErrorAs(conf.Validate(), &eventstore.ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		conf := AzureQueueConfig{
+			ConnectionString: azuriteConnectionString,
+			Queue:            "events",
+			MessageEncoding:  "none",
+			DataRef:          "https://evrys.example.com",
+		}
+		req.NoError(conf.Validate(), "config should have validated")
+	})
+}
+
+func TestNewAzureQueue(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("nil ctx", func(t *testing.T) {
+		_, err := NewAzureQueue(nil, AzureQueueConfig{})
+		req.ErrorContains(err, "context can not be nil", "error is not target error")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewAzureQueue(context.TODO(), AzureQueueConfig{Queue: "events"})
+		req.ErrorAs(err, &eventstore.ValidationErrors, "expected validation error")
+	})
+
+	t.Run("azure queue connection error", func(t *testing.T) {
+		conf := AzureQueueConfig{
+			ConnectionString: strings.Replace(azuriteConnectionString, "10001", "1", 1),
+			Queue:            "events",
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, err := NewAzureQueue(ctx, conf)
+		var connErr *eventstore.ConnectionError
+		req.ErrorAs(err, &connErr, "expected connection error")
+	})
+}
+
+func TestAzureQueue_Notify(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	queue := &fakeEnqueuer{}
+	a := &AzureQueue{
+		config: AzureQueueConfig{Queue: "events", DataRef: "https://evrys.example.com"},
+		logger: zap.NewNop(),
+		queue:  queue,
+	}
+
+	t.Run("base64 encoding", func(t *testing.T) {
+		req.NoError(a.Notify(ctx, newTestEvent("1", "test")), "failed to notify")
+
+		ev := decodeAzureMessage(t, queue.messages[len(queue.messages)-1], "base64")
+		req.Equal("1", ev.ID(), "id not expected value")
+		req.Equal("notifier_test", ev.Source(), "source not expected value")
+		req.JSONEq(`{"hello":"world"}`, string(ev.Data()), "data not expected value")
+	})
+
+	t.Run("no encoding", func(t *testing.T) {
+		a.config.MessageEncoding = "none"
+		defer func() { a.config.MessageEncoding = "" }()
+
+		req.NoError(a.Notify(ctx, newTestEvent("2", "test")), "failed to notify")
+
+		ev := decodeAzureMessage(t, queue.messages[len(queue.messages)-1], "none")
+		req.Equal("2", ev.ID(), "id not expected value")
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		req.NoError(a.Notify(ctx, newLargeTestEvent("a/3")), "failed to notify")
+
+		content := queue.messages[len(queue.messages)-1]
+		req.LessOrEqual(len(content), azureQueueMaxMessageSize, "message should fit in a storage queue")
+
+		ev := decodeAzureMessage(t, content, "base64")
+		req.Equal("a/3", ev.ID(), "id not expected value")
+		req.Equal("test", ev.Type(), "type not expected value")
+		req.Empty(ev.Data(), "reference message should not have data")
+		req.Empty(ev.DataContentType(), "reference message should not have a data content type")
+		req.Equal(
+			"https://evrys.example.com/events/notifier_test/a%2F3",
+			ev.Extensions()["dataref"],
+			"dataref not expected value",
+		)
+	})
+
+	t.Run("reference too large", func(t *testing.T) {
+		a.config.DataRef = "https://evrys.example.com/" + strings.Repeat("a", azureQueueMaxMessageSize)
+		defer func() { a.config.DataRef = "https://evrys.example.com" }()
+
+		err := a.Notify(ctx, newLargeTestEvent("5"))
+		var permanentErr *PermanentError
+		req.ErrorAs(err, &permanentErr, "expected permanent error")
+	})
+
+	t.Run("enqueue error", func(t *testing.T) {
+		queue.err = errors.New("unavailable")
+		defer func() { queue.err = nil }()
+
+		err := a.Notify(ctx, newTestEvent("4", "test"))
+		var notifyErr *NotifyError
+		req.ErrorAs(err, &notifyErr, "expected notify error")
+		req.ErrorIs(err, queue.err, "expected enqueue error to be wrapped")
+		var permanentErr *PermanentError
+		req.False(errors.As(err, &permanentErr), "enqueue errors should be retried")
+	})
+}
+
+func TestAzureQueueIntegration(t *testing.T) {
+	// setup
+	req := require.New(t)
+	ctx := context.Background()
+
+	// container init
+	contReq := testcontainers.ContainerRequest{
+		Image:        "mcr.microsoft.com/azure-storage/azurite:3.23.0",
+		Cmd:          []string{"azurite-queue", "--queueHost", "0.0.0.0"},
+		ExposedPorts: []string{"10001:10001"},
+		WaitingFor:   wait.ForLog("Azurite Queue service is successfully listening"),
+	}
+	azuriteC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: contReq,
+		Started:          true,
+	})
+	req.NoError(err, "failed to create azurite container")
+	defer azuriteC.Terminate(ctx)
+
+	// impl setup
+	config := AzureQueueConfig{
+		ConnectionString: azuriteConnectionString,
+		Queue:            "events",
+		DataRef:          "https://evrys.example.com",
+	}
+	notifier, err := NewAzureQueue(ctx, config)
+	req.NoError(err, "failed to create azure queue notifier")
+
+	_, err = NewAzureQueue(ctx, config)
+	req.NoError(err, "creating the queue again should succeed")
+
+	inner, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+	store, err := NewStore(inner, notifier)
+	req.NoError(err, "failed to create notifier store")
+
+	// actual test
+	ev := newTestEvent("1", "test")
+	_, err = store.Append(ctx, ev, eventstore.AnyVersion)
+	req.NoError(err, "failed to put event")
+
+	_, err = store.Append(ctx, newLargeTestEvent("2"), eventstore.AnyVersion)
+	req.NoError(err, "failed to put large event")
+
+	// queue verification
+	client, err := azqueue.NewQueueClientFromConnectionString(azuriteConnectionString, "events", nil)
+	req.NoError(err, "failed to create queue client")
+	n := int32(2)
+	resp, err := client.DequeueMessages(ctx, &azqueue.DequeueMessagesOptions{NumberOfMessages: &n})
+	req.NoError(err, "failed to dequeue messages")
+	req.Len(resp.Messages, 2, "unexpected number of messages")
+
+	published := decodeAzureMessage(t, *resp.Messages[0].MessageText, "base64")
+	req.Equal(ev.ID(), published.ID(), "id not expected value")
+	req.Equal(ev.Time(), published.Time(), "time is not equal")
+	req.JSONEq(`{"hello":"world"}`, string(published.Data()), "data not expected value")
+
+	ref := decodeAzureMessage(t, *resp.Messages[1].MessageText, "base64")
+	req.Equal("2", ref.ID(), "id not expected value")
+	req.Empty(ref.Data(), "reference message should not have data")
+	req.Equal("https://evrys.example.com/events/notifier_test/2", ref.Extensions()["dataref"], "dataref not expected value")
+}
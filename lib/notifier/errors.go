@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"fmt"
+)
+
+// NotifyError defines an error when notifying of an event which has already been appended
+type NotifyError struct {
+	Notifier string
+	EventID  string
+	Source   string
+	Err      error
+}
+
+// NewNotifyError creates a new NotifyError
+func NewNotifyError(notifier, eventID, source string, err error) *NotifyError {
+	return &NotifyError{
+		Notifier: notifier,
+		EventID:  eventID,
+		Source:   source,
+		Err:      err,
+	}
+}
+
+// Error returns a string form of the error and implements the error interface
+func (n *NotifyError) Error() string {
+	return fmt.Sprintf("failed to notify %s of event with id %q from source %q. %s", n.Notifier, n.EventID, n.Source, n.Err)
+}
+
+// Unwrap returns the inner error, making it compatible with errors.Unwrap
+func (n *NotifyError) Unwrap() error {
+	return n.Err
+}
+
+// PermanentError defines an error for an event which can never be notified of, e.g. since it is too
+// large or its type can not be mapped to the notifier. Retrying the event will always fail the same way.
+type PermanentError struct {
+	Err error
+}
+
+// NewPermanentError creates a new PermanentError
+func NewPermanentError(err error) *PermanentError {
+	return &PermanentError{
+		Err: err,
+	}
+}
+
+// Error returns a string form of the error and implements the error interface
+func (p *PermanentError) Error() string {
+	return fmt.Sprintf("event can never be notified of. %s", p.Err)
+}
+
+// Unwrap returns the inner error, making it compatible with errors.Unwrap
+func (p *PermanentError) Unwrap() error {
+	return p.Err
+}
@@ -0,0 +1,135 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// kafkaAcks maps the acks which can be configured to how many replicas must acknowledge a message
+var kafkaAcks = map[string]sarama.RequiredAcks{
+	"":       sarama.WaitForAll,
+	"all":    sarama.WaitForAll,
+	"leader": sarama.WaitForLocal,
+	"none":   sarama.NoResponse,
+}
+
+// KafkaConfig defines the configuration to connect to Kafka and which topic to publish events to
+type KafkaConfig struct {
+	// Brokers are the addresses of the brokers used to discover the cluster
+	Brokers []string `mapstructure:"brokers" validate:"required,min=1,dive,hostname_port"`
+
+	// Topic is the topic every event is published to
+	Topic string `mapstructure:"topic" validate:"required"`
+
+	// Acks is how many replicas must acknowledge an event before it is considered published,
+	// one of: all, leader, none. It defaults to all.
+	Acks string `mapstructure:"acks" validate:"omitempty,oneof=all leader none"`
+}
+
+// Validate ensures kafka config is correct
+func (k *KafkaConfig) Validate() error {
+	return validator.New().Struct(k)
+}
+
+// Kafka publishes events to a Kafka topic using the binary content mode of the CloudEvents
+// Kafka protocol binding, so the event attributes are sent as "ce_" prefixed headers and the
+// event data as the message value. Events with the "partitionkey" extension are sent with it
+// as the message key, so events with the same partition key are published to the same partition.
+type Kafka struct {
+	config KafkaConfig
+	logger *zap.Logger
+	sender *kafka_sarama.Sender
+}
+
+// NewKafka constructs a *Kafka and connects to the Kafka cluster
+func NewKafka(config KafkaConfig) (*Kafka, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config, %w", err)
+	}
+
+	impl := &Kafka{
+		config: config,
+		logger: zap.L().With(zap.String("source", "KafkaNotifierImpl")),
+	}
+
+	err = impl.init()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init, %w", err)
+	}
+
+	return impl, nil
+}
+
+// producerConfig returns the configuration of the sarama producer events are published with
+func (k *Kafka) producerConfig() *sarama.Config {
+	saramaConfig := sarama.NewConfig()
+	// headers, which events are sent with, were added in Kafka 0.11
+	saramaConfig.Version = sarama.V2_0_0_0
+	saramaConfig.Producer.RequiredAcks = kafkaAcks[k.config.Acks]
+	// a sync producer waits for the result of every message, so successes must be returned as well as errors
+	saramaConfig.Producer.Return.Successes = true
+	return saramaConfig
+}
+
+func (k *Kafka) init() error {
+	k.logger.Debug("attempting to connect to kafka")
+	producer, err := sarama.NewSyncProducer(k.config.Brokers, k.producerConfig())
+	if err != nil {
+		k.logger.Error("failed to connect to kafka", zap.Error(err))
+		return eventstore.NewConnectionError("kafka", err)
+	}
+	k.logger.Debug("successfully connected to kafka")
+
+	return k.initSender(producer)
+}
+
+func (k *Kafka) initSender(producer sarama.SyncProducer) error {
+	sender, err := kafka_sarama.NewSenderFromSyncProducer(k.config.Topic, producer)
+	if err != nil {
+		return err
+	}
+	k.sender = sender
+	return nil
+}
+
+// Close closes the connection to the Kafka cluster
+func (k *Kafka) Close() error {
+	return k.sender.Close(context.Background())
+}
+
+// Notify publishes the event to the configured topic and implements the interface Notifier
+func (k *Kafka) Notify(ctx context.Context, event *event.Event) error {
+	if event == nil {
+		return errors.New("event can not be nil")
+	}
+
+	err := k.sender.Send(binding.WithForceBinary(ctx), binding.ToMessage(event))
+	if err != nil {
+		k.logger.Error(
+			"failed to publish event",
+			zap.String("topic", k.config.Topic),
+			zap.String("event_id", event.ID()),
+			zap.String("event_source", event.Source()),
+			zap.Error(err),
+		)
+		return NewNotifyError("kafka", event.ID(), event.Source(), err)
+	}
+	k.logger.Debug(
+		"published event",
+		zap.String("topic", k.config.Topic),
+		zap.String("event_id", event.ID()),
+		zap.String("event_source", event.Source()),
+	)
+	return nil
+}
@@ -0,0 +1,238 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.uber.org/zap"
+)
+
+type fakeSyncProducer struct {
+	messages []*sarama.ProducerMessage
+	err      error
+}
+
+func (p *fakeSyncProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	if p.err != nil {
+		return 0, 0, p.err
+	}
+	p.messages = append(p.messages, msg)
+	return 0, int64(len(p.messages) - 1), nil
+}
+
+func (p *fakeSyncProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	for _, msg := range msgs {
+		_, _, err := p.SendMessage(msg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *fakeSyncProducer) Close() error {
+	return nil
+}
+
+func headersOf(msg *sarama.ProducerMessage) map[string]string {
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[string(h.Key)] = string(h.Value)
+	}
+	return headers
+}
+
+func TestKafkaConfig_Validate(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config - no brokers", func(t *testing.T) {
+		conf := KafkaConfig{Topic: "events"}
+		req.ErrorAs(conf.Validate(), &eventstore.ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - no topic", func(t *testing.T) {
+		conf := KafkaConfig{Brokers: []string{"localhost:9092"}}
+		req.ErrorAs(conf.Validate(), &eventstore.ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - unknown acks", func(t *testing.T) {
+		conf := KafkaConfig{Brokers: []string{"localhost:9092"}, Topic: "events", Acks: "some"}
+		req.ErrorAs(conf.Validate(), &eventstore.ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		conf := KafkaConfig{Brokers: []string{"localhost:9092"}, Topic: "events", Acks: "leader"}
+		req.NoError(conf.Validate(), "config should have validated")
+	})
+}
+
+func TestNewKafka(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewKafka(KafkaConfig{Topic: "events"})
+		req.ErrorAs(err, &eventstore.ValidationErrors, "expected validation error")
+	})
+
+	t.Run("kafka connection error", func(t *testing.T) {
+		_, err := NewKafka(KafkaConfig{Brokers: []string{"localhost:1"}, Topic: "events"})
+		var connErr *eventstore.ConnectionError
+		req.ErrorAs(err, &connErr, "expected connection error")
+
+		var confErr sarama.ConfigurationError
+		req.False(errors.As(err, &confErr), "producer config should have been valid, got %v", err)
+	})
+}
+
+func TestKafka_producerConfig(t *testing.T) {
+	for _, acks := range []string{"", "all", "leader", "none"} {
+		t.Run("acks "+acks, func(t *testing.T) {
+			k := &Kafka{config: KafkaConfig{Brokers: []string{"localhost:9092"}, Topic: "events", Acks: acks}}
+			conf := k.producerConfig()
+			require.NoError(t, conf.Validate(), "producer config should have validated")
+			require.True(t, conf.Producer.Return.Successes, "sync producer requires successes to be returned")
+			require.Equal(t, kafkaAcks[acks], conf.Producer.RequiredAcks, "required acks not expected value")
+		})
+	}
+}
+
+func TestKafka_Notify(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	producer := &fakeSyncProducer{}
+	k := &Kafka{config: KafkaConfig{Topic: "events"}, logger: zap.NewNop()}
+	req.NoError(k.initSender(producer), "failed to create sender")
+
+	t.Run("binary mode", func(t *testing.T) {
+		ev := newTestEvent("1", "test")
+		req.NoError(k.Notify(ctx, ev), "failed to notify")
+
+		msg := producer.messages[len(producer.messages)-1]
+		req.Equal("events", msg.Topic, "topic not expected value")
+		req.Nil(msg.Key, "event without a partition key should not have a message key")
+
+		headers := headersOf(msg)
+		req.Equal("1", headers["ce_id"], "id not expected value")
+		req.Equal("notifier_test", headers["ce_source"], "source not expected value")
+		req.Equal("test", headers["ce_type"], "type not expected value")
+		req.Equal("test", headers["ce_subject"], "subject not expected value")
+		req.Equal("1.0", headers["ce_specversion"], "spec version not expected value")
+		req.Equal(event.ApplicationJSON, headers["content-type"], "content type not expected value")
+
+		value, err := msg.Value.Encode()
+		req.NoError(err, "failed to encode message value")
+		req.JSONEq(`{"hello":"world"}`, string(value), "message value should be the event data")
+	})
+
+	t.Run("partition key", func(t *testing.T) {
+		ev := newTestEvent("2", "test")
+		ev.SetExtension("partitionkey", "some-key")
+		req.NoError(k.Notify(ctx, ev), "failed to notify")
+
+		msg := producer.messages[len(producer.messages)-1]
+		req.Equal(sarama.StringEncoder("some-key"), msg.Key, "message key not expected value")
+		req.Equal("some-key", headersOf(msg)["ce_partitionkey"], "partition key header not expected value")
+	})
+
+	t.Run("publish error", func(t *testing.T) {
+		producer.err = sarama.ErrNotLeaderForPartition
+		defer func() { producer.err = nil }()
+
+		err := k.Notify(ctx, newTestEvent("3", "test"))
+		var notifyErr *NotifyError
+		req.ErrorAs(err, &notifyErr, "expected notify error")
+		req.True(errors.Is(err, sarama.ErrNotLeaderForPartition), "expected producer error to be wrapped")
+	})
+}
+
+func TestKafkaIntegration(t *testing.T) {
+	// setup
+	req := require.New(t)
+	ctx := context.Background()
+
+	// container init
+	contReq := testcontainers.ContainerRequest{
+		Image: "bitnami/kafka:3.4",
+		Env: map[string]string{
+			"KAFKA_ENABLE_KRAFT":                       "yes",
+			"KAFKA_CFG_NODE_ID":                        "0",
+			"KAFKA_CFG_PROCESS_ROLES":                  "controller,broker",
+			"KAFKA_CFG_LISTENERS":                      "PLAINTEXT://:9092,CONTROLLER://:9093",
+			"KAFKA_CFG_ADVERTISED_LISTENERS":           "PLAINTEXT://localhost:9092",
+			"KAFKA_CFG_LISTENER_SECURITY_PROTOCOL_MAP": "CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT",
+			"KAFKA_CFG_CONTROLLER_QUORUM_VOTERS":       "0@localhost:9093",
+			"KAFKA_CFG_CONTROLLER_LISTENER_NAMES":      "CONTROLLER",
+			"KAFKA_CFG_AUTO_CREATE_TOPICS_ENABLE":      "true",
+			"ALLOW_PLAINTEXT_LISTENER":                 "yes",
+		},
+		ExposedPorts: []string{"9092:9092"},
+		WaitingFor:   wait.ForLog("Kafka Server started").WithStartupTimeout(2 * time.Minute),
+	}
+	kafkaC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: contReq,
+		Started:          true,
+	})
+	req.NoError(err, "failed to create kafka container")
+	defer kafkaC.Terminate(ctx)
+
+	// impl setup
+	notifier, err := NewKafka(KafkaConfig{
+		Brokers: []string{"localhost:9092"},
+		Topic:   "events",
+	})
+	req.NoError(err, "failed to create kafka notifier")
+	defer notifier.Close()
+
+	inner, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+	store, err := NewStore(inner, notifier)
+	req.NoError(err, "failed to create notifier store")
+
+	// actual test
+	ev := newTestEvent("1", "test")
+	ev.SetExtension("partitionkey", "some-key")
+	_, err = store.Append(ctx, ev, eventstore.AnyVersion)
+	req.NoError(err, "failed to put event")
+
+	// kafka verification
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V2_0_0_0
+	consumer, err := sarama.NewConsumer([]string{"localhost:9092"}, saramaConfig)
+	req.NoError(err, "failed to create kafka consumer")
+	defer consumer.Close()
+
+	partitions, err := consumer.Partitions("events")
+	req.NoError(err, "failed to list partitions")
+	req.Len(partitions, 1, "unexpected number of partitions")
+
+	pc, err := consumer.ConsumePartition("events", partitions[0], sarama.OffsetOldest)
+	req.NoError(err, "failed to consume partition")
+	defer pc.Close()
+
+	var msg *sarama.ConsumerMessage
+	select {
+	case msg = <-pc.Messages():
+	case <-time.After(30 * time.Second):
+		req.FailNow("timed out waiting for event to be published")
+	}
+	req.Equal("some-key", string(msg.Key), "message key not expected value")
+
+	published, err := binding.ToEvent(ctx, kafka_sarama.NewMessageFromConsumerMessage(msg))
+	req.NoError(err, "failed to read event from message")
+	req.Equal(ev.ID(), published.ID(), "id not expected value")
+	req.Equal(ev.Source(), published.Source(), "source not expected value")
+	req.Equal(ev.Time(), published.Time(), "time is not equal")
+	req.JSONEq(`{"hello":"world"}`, string(published.Data()), "data not expected value")
+}
@@ -0,0 +1,181 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/go-playground/validator/v10"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// natsDefaultSubjectPrefix is the prefix of the subject events are published to if none is configured
+const natsDefaultSubjectPrefix = "evrys"
+
+// NATSConfig defines the configuration to connect to NATS and which JetStream stream to publish events to
+type NATSConfig struct {
+	// URL is the url of the NATS server, e.g. nats://localhost:4222. Several servers
+	// of a cluster can be given separated by commas.
+	URL string `mapstructure:"url" validate:"required"`
+
+	// Stream is the JetStream stream events are stored in. It is created, capturing every subject
+	// starting with the subject prefix, if it does not exist.
+	Stream string `mapstructure:"stream" validate:"required,excludesall=.*> "`
+
+	// SubjectPrefix is the prefix of the subject each event is published to, which is followed by
+	// the type of the event. It defaults to "evrys".
+	SubjectPrefix string `mapstructure:"subject_prefix" validate:"omitempty,excludesall=*> "`
+}
+
+// Validate ensures nats config is correct
+func (n *NATSConfig) Validate() error {
+	return validator.New().Struct(n)
+}
+
+// NATS publishes events to NATS JetStream as structured mode CloudEvents JSON. Each event is published
+// to a subject of the subject prefix followed by its type, e.g. "evrys.com.example.order.created", so
+// subscribers can use wildcards to select events by their type.
+//
+// Each message has a Nats-Msg-Id header derived from the id and source of its event, since ids are
+// only unique per source, so JetStream drops events which are notified of more than once within the
+// duplicate window of the stream.
+type NATS struct {
+	config NATSConfig
+	logger *zap.Logger
+	conn   *nats.Conn
+	js     nats.JetStreamContext
+}
+
+// NewNATS constructs a *NATS, connects to NATS and creates its stream if it does not exist
+func NewNATS(config NATSConfig) (*NATS, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config, %w", err)
+	}
+	if config.SubjectPrefix == "" {
+		config.SubjectPrefix = natsDefaultSubjectPrefix
+	}
+
+	impl := &NATS{
+		config: config,
+		logger: zap.L().With(zap.String("source", "NATSNotifierImpl")),
+	}
+
+	err = impl.init()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init, %w", err)
+	}
+
+	return impl, nil
+}
+
+func (n *NATS) init() error {
+	n.logger.Debug("attempting to connect to nats")
+	conn, err := nats.Connect(n.config.URL)
+	if err != nil {
+		n.logger.Error("failed to connect to nats", zap.Error(err))
+		return eventstore.NewConnectionError("nats", err)
+	}
+	n.logger.Debug("successfully connected to nats")
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return eventstore.NewConnectionError("nats", err)
+	}
+
+	n.logger.Debug("attempting to create stream")
+	_, err = js.StreamInfo(n.config.Stream)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		_, err = js.AddStream(&nats.StreamConfig{
+			Name:     n.config.Stream,
+			Subjects: []string{n.config.SubjectPrefix + ".>"},
+		})
+	}
+	if err != nil {
+		n.logger.Error("failed to create stream", zap.Error(err))
+		conn.Close()
+		return eventstore.NewConnectionError("nats", err)
+	}
+	n.logger.Debug("successfully created stream")
+
+	n.conn = conn
+	n.js = js
+	return nil
+}
+
+// Close closes the connection to NATS
+func (n *NATS) Close() error {
+	n.conn.Close()
+	return nil
+}
+
+// Notify publishes the event to the subject for its type and implements the interface Notifier
+func (n *NATS) Notify(ctx context.Context, event *event.Event) error {
+	if event == nil {
+		return errors.New("event can not be nil")
+	}
+
+	msg, err := n.message(event)
+	if err != nil {
+		return NewNotifyError("nats", event.ID(), event.Source(), NewPermanentError(err))
+	}
+
+	ack, err := n.js.PublishMsg(msg, nats.Context(ctx))
+	if err != nil {
+		n.logger.Error(
+			"failed to publish event",
+			zap.String("subject", msg.Subject),
+			zap.String("event_id", event.ID()),
+			zap.String("event_source", event.Source()),
+			zap.Error(err),
+		)
+		return NewNotifyError("nats", event.ID(), event.Source(), err)
+	}
+	n.logger.Debug(
+		"published event",
+		zap.String("subject", msg.Subject),
+		zap.String("event_id", event.ID()),
+		zap.String("event_source", event.Source()),
+		zap.Uint64("sequence", ack.Sequence),
+		zap.Bool("duplicate", ack.Duplicate),
+	)
+	return nil
+}
+
+func (n *NATS) message(event *event.Event) (*nats.Msg, error) {
+	subject, err := n.subject(event.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := event.MarshalJSON()
+	if err != nil {
+		return nil, eventstore.NewMarshalError("*event.Event", "json", err)
+	}
+
+	msg := nats.NewMsg(subject)
+	msg.Data = b
+	msg.Header.Set("Content-Type", "application/cloudevents+json")
+	msg.Header.Set(nats.MsgIdHdr, eventstore.HashKey(event.Source(), event.ID()))
+	return msg, nil
+}
+
+// subject returns the subject events of the given type are published to. Types which would
+// not be a valid subject, e.g. since they contain wildcards or whitespace, are rejected.
+func (n *NATS) subject(typ string) (string, error) {
+	if strings.ContainsAny(typ, "*> \t\r\n") {
+		return "", fmt.Errorf("event type %q can not be used in a subject", typ)
+	}
+	for _, token := range strings.Split(typ, ".") {
+		if token == "" {
+			return "", fmt.Errorf("event type %q can not be used in a subject", typ)
+		}
+	}
+	return n.config.SubjectPrefix + "." + typ, nil
+}
@@ -0,0 +1,152 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+)
+
+// runNATSServer runs a local nats-server with JetStream enabled for the duration of the test
+func runNATSServer(t *testing.T) string {
+	s, err := natsserver.NewServer(&natsserver.Options{
+		Host:      "127.0.0.1",
+		Port:      natsserver.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	require.NoError(t, err, "failed to create nats server")
+
+	go s.Start()
+	t.Cleanup(s.Shutdown)
+	require.True(t, s.ReadyForConnections(5*time.Second), "nats server is not ready for connections")
+	return s.ClientURL()
+}
+
+func TestNATSConfig_Validate(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config - no url", func(t *testing.T) {
+		conf := NATSConfig{Stream: "events"}
+		req.ErrorAs(conf.Validate(), &eventstore.ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - no stream", func(t *testing.T) {
+		conf := NATSConfig{URL: nats.DefaultURL}
+		req.ErrorAs(conf.Validate(), &eventstore.ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - invalid stream", func(t *testing.T) {
+		conf := NATSConfig{URL: nats.DefaultURL, Stream: "evrys.events"}
+		req.ErrorAs(conf.Validate(), &eventstore.ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - wildcard subject prefix", func(t *testing.T) {
+		conf := NATSConfig{URL: nats.DefaultURL, Stream: "events", SubjectPrefix: "evrys.*"}
+		req.ErrorAs(conf.Validate(), &eventstore.ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		conf := NATSConfig{URL: nats.DefaultURL, Stream: "events", SubjectPrefix: "acme.evrys"}
+		req.NoError(conf.Validate(), "config should have validated")
+	})
+}
+
+func TestNewNATS(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewNATS(NATSConfig{Stream: "events"})
+		req.ErrorAs(err, &eventstore.ValidationErrors, "expected validation error")
+	})
+
+	t.Run("nats connection error", func(t *testing.T) {
+		_, err := NewNATS(NATSConfig{URL: "nats://localhost:1", Stream: "events"})
+		var connErr *eventstore.ConnectionError
+		req.ErrorAs(err, &connErr, "expected connection error")
+	})
+
+	t.Run("existing stream", func(t *testing.T) {
+		url := runNATSServer(t)
+
+		n, err := NewNATS(NATSConfig{URL: url, Stream: "events"})
+		req.NoError(err, "failed to create nats notifier")
+		defer n.Close()
+
+		n, err = NewNATS(NATSConfig{URL: url, Stream: "events"})
+		req.NoError(err, "an existing stream should be used")
+		defer n.Close()
+	})
+}
+
+func TestNATS_Notify(t *testing.T) {
+	// setup
+	req := require.New(t)
+	ctx := context.Background()
+	url := runNATSServer(t)
+
+	notifier, err := NewNATS(NATSConfig{URL: url, Stream: "events"})
+	req.NoError(err, "failed to create nats notifier")
+	defer notifier.Close()
+
+	inner, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+	store, err := NewStore(inner, notifier)
+	req.NoError(err, "failed to create notifier store")
+
+	conn, err := nats.Connect(url)
+	req.NoError(err, "failed to connect to nats")
+	defer conn.Close()
+	js, err := conn.JetStream()
+	req.NoError(err, "failed to get jetstream context")
+
+	sub, err := js.SubscribeSync("evrys.com.example.>", nats.DeliverAll())
+	req.NoError(err, "failed to subscribe")
+
+	// actual test
+	ev := newTestEvent("1", "test")
+	ev.SetType("com.example.created")
+	_, err = store.Append(ctx, ev, eventstore.AnyVersion)
+	req.NoError(err, "failed to put event")
+
+	t.Run("publishes to the subject for the event type", func(t *testing.T) {
+		msg, err := sub.NextMsg(5 * time.Second)
+		req.NoError(err, "failed to receive message")
+		req.Equal("evrys.com.example.created", msg.Subject, "subject not expected value")
+		req.Equal(eventstore.HashKey("notifier_test", "1"), msg.Header.Get(nats.MsgIdHdr), "message id not expected value")
+		req.Equal("application/cloudevents+json", msg.Header.Get("Content-Type"), "content type not expected value")
+
+		var published event.Event
+		req.NoError(published.UnmarshalJSON(msg.Data), "message should be a cloudevent")
+		req.Equal(ev.ID(), published.ID(), "id not expected value")
+		req.Equal(ev.Time(), published.Time(), "time is not equal")
+		req.JSONEq(`{"hello":"world"}`, string(published.Data()), "data not expected value")
+	})
+
+	t.Run("deduplicates events notified of again", func(t *testing.T) {
+		req.NoError(notifier.Notify(ctx, ev), "failed to notify again")
+
+		info, err := js.StreamInfo("events")
+		req.NoError(err, "failed to get stream info")
+		req.Equal(uint64(1), info.State.Msgs, "duplicate event should have been dropped")
+	})
+
+	t.Run("rejects types which are not valid subjects", func(t *testing.T) {
+		invalid := newTestEvent("2", "test")
+		invalid.SetType("com.example.*")
+
+		err := notifier.Notify(ctx, invalid)
+		var notifyErr *NotifyError
+		req.ErrorAs(err, &notifyErr, "expected notify error")
+		var permanentErr *PermanentError
+		req.ErrorAs(err, &permanentErr, "expected permanent error")
+	})
+}
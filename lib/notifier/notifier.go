@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.uber.org/zap"
+)
+
+// Notifier publishes events to a notification bus, so downstream services can react to
+// new events without polling an event store. Events which can never be published, e.g. since
+// they are too large for the bus, are rejected with a *PermanentError.
+type Notifier interface {
+	Notify(ctx context.Context, event *event.Event) error
+}
+
+// Store wraps an event store and notifies of every event once it has been appended.
+//
+// If notifying fails, the event has still been appended and a *NotifyError is returned along
+// with its position. Appending the same event again is safe, since event stores return the
+// position it was originally appended at, and notifies of it again. Downstream services should
+// therefore expect to be notified of an event more than once, which they can detect by its id
+// and source.
+type Store struct {
+	logger   *zap.Logger
+	store    eventstore.Store
+	notifier Notifier
+}
+
+// NewStore constructs a *Store which notifies of events appended to the given event store
+func NewStore(store eventstore.Store, notifier Notifier) (*Store, error) {
+	if store == nil {
+		return nil, errors.New("store can not be nil")
+	}
+	if notifier == nil {
+		return nil, errors.New("notifier can not be nil")
+	}
+
+	return &Store{
+		logger:   zap.L().With(zap.String("source", "NotifierStoreImpl")),
+		store:    store,
+		notifier: notifier,
+	}, nil
+}
+
+// Close closes the notifier and the wrapped event store if they are io.Closers
+func (s *Store) Close() error {
+	var err error
+	if closer, ok := s.notifier.(io.Closer); ok {
+		err = closer.Close()
+	}
+	if closer, ok := s.store.(io.Closer); ok {
+		storeErr := closer.Close()
+		if err == nil {
+			err = storeErr
+		}
+	}
+	return err
+}
+
+// Append appends the event to the wrapped event store and then notifies of it. Append implements the interface AppendOnly.
+func (s *Store) Append(ctx context.Context, event *event.Event, expectedVersion uint64) (uint64, error) {
+	position, err := s.store.Append(ctx, event, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	return position, s.notify(ctx, event)
+}
+
+// AppendBatch appends the events to the wrapped event store and then notifies of each of them in order.
+// AppendBatch implements the interface BatchAppendOnly.
+func (s *Store) AppendBatch(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+	positions, err := s.store.AppendBatch(ctx, events, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		err = s.notify(ctx, event)
+		if err != nil {
+			return positions, err
+		}
+	}
+	return positions, nil
+}
+
+func (s *Store) notify(ctx context.Context, event *event.Event) error {
+	err := s.notifier.Notify(ctx, event)
+	if err != nil {
+		s.logger.Error(
+			"failed to notify of appended event",
+			zap.String("event_id", event.ID()),
+			zap.String("event_source", event.Source()),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// Iterate iterates over the events in the wrapped event store and implements the interface Iterable
+func (s *Store) Iterate(ctx context.Context, filter eventstore.Filter) (eventstore.Iterator, error) {
+	return s.store.Iterate(ctx, filter)
+}
+
+// Subscribe subscribes to the wrapped event store and implements the interface Subscribable
+func (s *Store) Subscribe(ctx context.Context, filter eventstore.Filter) (eventstore.Iterator, error) {
+	return eventstore.Subscribe(ctx, s.store, filter)
+}
+
+// ReadStream reads the stream from the wrapped event store and implements the interface StreamReader
+func (s *Store) ReadStream(ctx context.Context, stream string, opts eventstore.ReadStreamOptions) (eventstore.Iterator, error) {
+	return s.store.ReadStream(ctx, stream, opts)
+}
+
+// Get looks up an event in the wrapped event store and implements the interface Gettable
+func (s *Store) Get(ctx context.Context, source, id string) (*eventstore.Record, error) {
+	return s.store.Get(ctx, source, id)
+}
+
+// Checkpoint returns the checkpoint of a consumer group from the wrapped event store and implements the interface Checkpointer
+func (s *Store) Checkpoint(ctx context.Context, group string) (uint64, error) {
+	return s.store.Checkpoint(ctx, group)
+}
+
+// Commit commits the checkpoint of a consumer group to the wrapped event store and implements the interface Checkpointer
+func (s *Store) Commit(ctx context.Context, group string, position uint64) error {
+	return s.store.Commit(ctx, group, position)
+}
@@ -0,0 +1,115 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(id, subject string) *event.Event {
+	ev := event.New()
+	ev.SetID(id)
+	ev.SetSubject(subject)
+	ev.SetSource("notifier_test")
+	ev.SetTime(time.Now().UTC())
+	ev.SetSpecVersion(event.CloudEventsVersionV1)
+	ev.SetType("test")
+	ev.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": "world"})
+	return &ev
+}
+
+type notifierFunc func(context.Context, *event.Event) error
+
+func (f notifierFunc) Notify(ctx context.Context, ev *event.Event) error {
+	return f(ctx, ev)
+}
+
+func TestNewStore(t *testing.T) {
+	req := require.New(t)
+	store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	t.Run("nil store", func(t *testing.T) {
+		_, err := NewStore(nil, notifierFunc(nil))
+		req.ErrorContains(err, "store can not be nil", "error is not target error")
+	})
+
+	t.Run("nil notifier", func(t *testing.T) {
+		_, err := NewStore(store, nil)
+		req.ErrorContains(err, "notifier can not be nil", "error is not target error")
+	})
+}
+
+func TestStore_Append(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	inner, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	var notified []string
+	var notifyErr error
+	store, err := NewStore(inner, notifierFunc(func(ctx context.Context, ev *event.Event) error {
+		if notifyErr != nil {
+			return notifyErr
+		}
+		notified = append(notified, ev.ID())
+		return nil
+	}))
+	req.NoError(err, "failed to create notifier store")
+
+	first := newTestEvent("1", "test")
+	position, err := store.Append(ctx, first, eventstore.AnyVersion)
+	req.NoError(err, "failed to put event")
+	req.Equal(uint64(1), position, "position not expected value")
+	req.Equal([]string{"1"}, notified, "appended event should be notified of")
+
+	_, err = store.Append(ctx, newTestEvent("2", "test"), 0)
+	var conflictErr *eventstore.VersionConflictError
+	req.ErrorAs(err, &conflictErr, "expected version conflict error")
+	req.Equal([]string{"1"}, notified, "events which failed to append should not be notified of")
+
+	third := newTestEvent("3", "test")
+	notifyErr = NewNotifyError("test", "3", "notifier_test", errors.New("unavailable"))
+	position, err = store.Append(ctx, third, eventstore.AnyVersion)
+	var nErr *NotifyError
+	req.ErrorAs(err, &nErr, "expected notify error")
+	req.Equal(uint64(2), position, "position of the appended event should be returned")
+
+	notifyErr = nil
+	position, err = store.Append(ctx, third, eventstore.AnyVersion)
+	req.NoError(err, "re-appending the event should succeed")
+	req.Equal(uint64(2), position, "position not expected value")
+	req.Equal([]string{"1", "3"}, notified, "re-appended event should be notified of")
+}
+
+func TestStore_AppendBatch(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	inner, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+	req.NoError(err, "failed to create memory event store")
+
+	var notified []string
+	store, err := NewStore(inner, notifierFunc(func(ctx context.Context, ev *event.Event) error {
+		notified = append(notified, ev.ID())
+		return nil
+	}))
+	req.NoError(err, "failed to create notifier store")
+
+	positions, err := store.AppendBatch(ctx, []*event.Event{newTestEvent("1", "test"), newTestEvent("2", "test")}, 0)
+	req.NoError(err, "failed to put batch")
+	req.Equal([]uint64{1, 2}, positions, "positions not expected value")
+	req.Equal([]string{"1", "2"}, notified, "appended events should be notified of in order")
+
+	_, err = store.AppendBatch(ctx, []*event.Event{newTestEvent("3", "test"), newTestEvent("4", "test")}, 0)
+	var conflictErr *eventstore.VersionConflictError
+	req.ErrorAs(err, &conflictErr, "expected version conflict error")
+	req.Equal([]string{"1", "2"}, notified, "events which failed to append should not be notified of")
+}
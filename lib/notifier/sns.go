@@ -0,0 +1,209 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+const (
+	snsTypeAttribute    = "ce_type"
+	snsSourceAttribute  = "ce_source"
+	snsSubjectAttribute = "ce_subject"
+
+	// snsMaxMessageGroupLen is the longest message group id a FIFO topic accepts
+	snsMaxMessageGroupLen = 128
+
+	// snsMaxMessageSize is the most bytes sns accepts for a message, counting its attributes
+	snsMaxMessageSize = 256 * 1024
+)
+
+// SNSConfig defines the configuration to connect to Amazon SNS and which topic to publish events to.
+// Credentials are loaded from the default AWS credential chain, e.g. the AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY environment variables.
+type SNSConfig struct {
+	Region string `mapstructure:"region" validate:"required"`
+	// Endpoint overrides the sns endpoint, e.g. to use LocalStack
+	Endpoint string `mapstructure:"endpoint" validate:"omitempty,url"`
+
+	// TopicARN is the ARN of the topic every event is published to
+	TopicARN string `mapstructure:"topic_arn" validate:"required"`
+}
+
+// Validate ensures sns config is correct
+func (s *SNSConfig) Validate() error {
+	return validator.New().Struct(s)
+}
+
+// snsPublisher is the subset of the sns client used to publish events
+type snsPublisher interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+// SNS publishes events to an Amazon SNS topic as structured mode CloudEvents JSON. The type, source
+// and subject of each event are also sent as the "ce_type", "ce_source" and "ce_subject" message
+// attributes, so subscriptions can select events with filter policies.
+//
+// FIFO topics are published to with the subject of each event as its message group, so events with
+// the same subject are delivered in the order they were appended. Events without a subject are grouped
+// by their source instead. The id and source of each event are used to deduplicate messages.
+type SNS struct {
+	config    SNSConfig
+	logger    *zap.Logger
+	publisher snsPublisher
+	fifo      bool
+}
+
+// NewSNS constructs a *SNS and checks the topic exists
+func NewSNS(ctx context.Context, config SNSConfig) (*SNS, error) {
+	if ctx == nil {
+		return nil, errors.New("context can not be nil")
+	}
+
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config, %w", err)
+	}
+
+	impl := &SNS{
+		config: config,
+		logger: zap.L().With(zap.String("source", "SNSNotifierImpl")),
+	}
+
+	err = impl.init(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init, %w", err)
+	}
+
+	return impl, nil
+}
+
+func (s *SNS) init(ctx context.Context) error {
+	s.logger.Debug("attempting to load aws config")
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(s.config.Region))
+	if err != nil {
+		s.logger.Error("failed to load aws config", zap.Error(err))
+		return eventstore.NewConnectionError("sns", err)
+	}
+
+	client := sns.NewFromConfig(cfg, func(o *sns.Options) {
+		if s.config.Endpoint != "" {
+			o.EndpointResolver = sns.EndpointResolverFromURL(s.config.Endpoint)
+		}
+	})
+
+	s.logger.Debug("attempting to get topic attributes")
+	out, err := client.GetTopicAttributes(ctx, &sns.GetTopicAttributesInput{TopicArn: aws.String(s.config.TopicARN)})
+	if err != nil {
+		s.logger.Error("failed to get topic attributes", zap.Error(err))
+		return eventstore.NewConnectionError("sns", err)
+	}
+	s.logger.Debug("successfully got topic attributes")
+
+	s.publisher = client
+	s.fifo, _ = strconv.ParseBool(out.Attributes["FifoTopic"])
+	return nil
+}
+
+// Notify publishes the event to the configured topic and implements the interface Notifier
+func (s *SNS) Notify(ctx context.Context, event *event.Event) error {
+	if event == nil {
+		return errors.New("event can not be nil")
+	}
+
+	input, err := s.publishInput(event)
+	if err != nil {
+		return NewNotifyError("sns", event.ID(), event.Source(), NewPermanentError(err))
+	}
+
+	_, err = s.publisher.Publish(ctx, input)
+	if err != nil {
+		s.logger.Error(
+			"failed to publish event",
+			zap.String("topic_arn", s.config.TopicARN),
+			zap.String("event_id", event.ID()),
+			zap.String("event_source", event.Source()),
+			zap.Error(err),
+		)
+		return NewNotifyError("sns", event.ID(), event.Source(), err)
+	}
+	s.logger.Debug(
+		"published event",
+		zap.String("topic_arn", s.config.TopicARN),
+		zap.String("event_id", event.ID()),
+		zap.String("event_source", event.Source()),
+	)
+	return nil
+}
+
+func (s *SNS) publishInput(event *event.Event) (*sns.PublishInput, error) {
+	b, err := event.MarshalJSON()
+	if err != nil {
+		return nil, eventstore.NewMarshalError("*event.Event", "json", err)
+	}
+
+	// sns rejects attributes with empty values
+	attrs := map[string]types.MessageAttributeValue{
+		snsTypeAttribute:   snsString(event.Type()),
+		snsSourceAttribute: snsString(event.Source()),
+	}
+	if event.Subject() != "" {
+		attrs[snsSubjectAttribute] = snsString(event.Subject())
+	}
+
+	size := len(b)
+	for name, attr := range attrs {
+		size += len(name) + len(aws.ToString(attr.DataType)) + len(aws.ToString(attr.StringValue))
+	}
+	if size > snsMaxMessageSize {
+		return nil, fmt.Errorf("message is %d bytes but at most %d are allowed", size, snsMaxMessageSize)
+	}
+
+	input := &sns.PublishInput{
+		TopicArn:          aws.String(s.config.TopicARN),
+		Message:           aws.String(string(b)),
+		MessageAttributes: attrs,
+	}
+	if s.fifo {
+		input.MessageGroupId = aws.String(snsMessageGroup(event))
+		input.MessageDeduplicationId = aws.String(eventstore.HashKey(event.Source(), event.ID()))
+	}
+	return input, nil
+}
+
+func snsString(s string) types.MessageAttributeValue {
+	return types.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(s),
+	}
+}
+
+// snsMessageGroup returns the message group of an event, which is its subject or, if it has none, its source.
+// Groups which sns does not accept, since they are too long or contain characters other than alphanumerics
+// and punctuation, are hashed so events with the same subject are still grouped together.
+func snsMessageGroup(event *event.Event) string {
+	group := event.Subject()
+	if group == "" {
+		group = event.Source()
+	}
+	if len(group) > snsMaxMessageGroupLen {
+		return eventstore.HashKey(group)
+	}
+	for i := 0; i < len(group); i++ {
+		if group[i] <= ' ' || group[i] > '~' {
+			return eventstore.HashKey(group)
+		}
+	}
+	return group
+}
@@ -0,0 +1,294 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.uber.org/zap"
+)
+
+type fakeSNSPublisher struct {
+	inputs []*sns.PublishInput
+	err    error
+}
+
+func (p *fakeSNSPublisher) Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.inputs = append(p.inputs, params)
+	return &sns.PublishOutput{MessageId: aws.String("message")}, nil
+}
+
+func TestSNSConfig_Validate(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("invalid config - no region", func(t *testing.T) {
+		conf := SNSConfig{TopicARN: "arn:aws:sns:us-east-1:000000000000:events"}
+		req.ErrorAs(conf.Validate(), &eventstore.ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("invalid config - no topic", func(t *testing.T) {
+		conf := SNSConfig{Region: "us-east-1"}
+		req.ErrorAs(conf.Validate(), &eventstore.ValidationErrors, "config should not have validated")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		conf := SNSConfig{
+			Region:   "us-east-1",
+			Endpoint: "http://localhost:4566",
+			TopicARN: "arn:aws:sns:us-east-1:000000000000:events",
+		}
+		req.NoError(conf.Validate(), "config should have validated")
+	})
+}
+
+func TestNewSNS(t *testing.T) {
+	req := require.New(t)
+
+	t.Run("nil ctx", func(t *testing.T) {
+		_, err := NewSNS(nil, SNSConfig{})
+		req.ErrorContains(err, "context can not be nil", "error is not target error")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewSNS(context.TODO(), SNSConfig{Region: "us-east-1"})
+		req.ErrorAs(err, &eventstore.ValidationErrors, "expected validation error")
+	})
+
+	t.Run("sns connection error", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "local")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "local")
+
+		conf := SNSConfig{
+			Region:   "us-east-1",
+			Endpoint: "http://localhost:1",
+			TopicARN: "arn:aws:sns:us-east-1:000000000000:events",
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, err := NewSNS(ctx, conf)
+		var connErr *eventstore.ConnectionError
+		req.ErrorAs(err, &connErr, "expected connection error")
+	})
+}
+
+func TestSNS_Notify(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	publisher := &fakeSNSPublisher{}
+	s := &SNS{
+		config:    SNSConfig{TopicARN: "arn:aws:sns:us-east-1:000000000000:events"},
+		logger:    zap.NewNop(),
+		publisher: publisher,
+	}
+
+	t.Run("standard topic", func(t *testing.T) {
+		ev := newTestEvent("1", "test")
+		req.NoError(s.Notify(ctx, ev), "failed to notify")
+
+		input := publisher.inputs[len(publisher.inputs)-1]
+		req.Equal(s.config.TopicARN, aws.ToString(input.TopicArn), "topic not expected value")
+		req.Nil(input.MessageGroupId, "standard topics should not be given a message group")
+		req.Nil(input.MessageDeduplicationId, "standard topics should not be given a deduplication id")
+
+		req.Equal("test", aws.ToString(input.MessageAttributes["ce_type"].StringValue), "type not expected value")
+		req.Equal("notifier_test", aws.ToString(input.MessageAttributes["ce_source"].StringValue), "source not expected value")
+		req.Equal("test", aws.ToString(input.MessageAttributes["ce_subject"].StringValue), "subject not expected value")
+		req.Equal("String", aws.ToString(input.MessageAttributes["ce_type"].DataType), "data type not expected value")
+
+		var published event.Event
+		req.NoError(published.UnmarshalJSON([]byte(aws.ToString(input.Message))), "message should be a cloudevent")
+		req.Equal("1", published.ID(), "id not expected value")
+		req.JSONEq(`{"hello":"world"}`, string(published.Data()), "data not expected value")
+	})
+
+	t.Run("no subject", func(t *testing.T) {
+		req.NoError(s.Notify(ctx, newTestEvent("2", "")), "failed to notify")
+
+		input := publisher.inputs[len(publisher.inputs)-1]
+		req.NotContains(input.MessageAttributes, "ce_subject", "empty subject should not be sent as an attribute")
+	})
+
+	t.Run("fifo topic", func(t *testing.T) {
+		s.fifo = true
+		defer func() { s.fifo = false }()
+
+		req.NoError(s.Notify(ctx, newTestEvent("3", "test")), "failed to notify")
+		input := publisher.inputs[len(publisher.inputs)-1]
+		req.Equal("test", aws.ToString(input.MessageGroupId), "message group should be the subject")
+		req.Equal(eventstore.HashKey("notifier_test", "3"), aws.ToString(input.MessageDeduplicationId), "deduplication id not expected value")
+
+		req.NoError(s.Notify(ctx, newTestEvent("4", "")), "failed to notify")
+		input = publisher.inputs[len(publisher.inputs)-1]
+		req.Equal("notifier_test", aws.ToString(input.MessageGroupId), "message group should fall back to the source")
+
+		req.NoError(s.Notify(ctx, newTestEvent("5", "some subject")), "failed to notify")
+		input = publisher.inputs[len(publisher.inputs)-1]
+		req.Equal(eventstore.HashKey("some subject"), aws.ToString(input.MessageGroupId), "invalid message group should be hashed")
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		ev := newTestEvent("7", "test")
+		ev.SetData(*event.StringOfApplicationJSON(), map[string]interface{}{"hello": strings.Repeat("world", 60*1024)})
+
+		err := s.Notify(ctx, ev)
+		var permanentErr *PermanentError
+		req.ErrorAs(err, &permanentErr, "expected permanent error")
+	})
+
+	t.Run("publish error", func(t *testing.T) {
+		publisher.err = errors.New("throttled")
+		defer func() { publisher.err = nil }()
+
+		err := s.Notify(ctx, newTestEvent("6", "test"))
+		var notifyErr *NotifyError
+		req.ErrorAs(err, &notifyErr, "expected notify error")
+		req.ErrorIs(err, publisher.err, "expected publish error to be wrapped")
+		var permanentErr *PermanentError
+		req.False(errors.As(err, &permanentErr), "publish errors should be retried")
+	})
+}
+
+func TestSNSIntegration(t *testing.T) {
+	// setup
+	req := require.New(t)
+	ctx := context.Background()
+	t.Setenv("AWS_ACCESS_KEY_ID", "local")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "local")
+
+	// container init
+	contReq := testcontainers.ContainerRequest{
+		Image:        "localstack/localstack:1.3",
+		Env:          map[string]string{"SERVICES": "sns,sqs"},
+		ExposedPorts: []string{"4566:4566"},
+		WaitingFor:   wait.ForLog("Ready.").WithStartupTimeout(2 * time.Minute),
+	}
+	localstackC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: contReq,
+		Started:          true,
+	})
+	req.NoError(err, "failed to create localstack container")
+	defer localstackC.Terminate(ctx)
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion("us-east-1"))
+	req.NoError(err, "failed to load aws config")
+	snsClient := sns.NewFromConfig(cfg, func(o *sns.Options) {
+		o.EndpointResolver = sns.EndpointResolverFromURL("http://localhost:4566")
+	})
+	sqsClient := sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+		o.EndpointResolver = sqs.EndpointResolverFromURL("http://localhost:4566")
+	})
+
+	// subscribe creates a topic and a queue which receives the messages published to it
+	subscribe := func(name string, fifo bool, filterPolicy string) (string, string) {
+		topicAttrs := map[string]string{}
+		queueAttrs := map[string]string{}
+		if fifo {
+			topicAttrs["FifoTopic"] = "true"
+			queueAttrs["FifoQueue"] = "true"
+		}
+		topic, err := snsClient.CreateTopic(ctx, &sns.CreateTopicInput{Name: aws.String(name), Attributes: topicAttrs})
+		req.NoError(err, "failed to create topic")
+		queue, err := sqsClient.CreateQueue(ctx, &sqs.CreateQueueInput{QueueName: aws.String(name), Attributes: queueAttrs})
+		req.NoError(err, "failed to create queue")
+		attrs, err := sqsClient.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+			QueueUrl:       queue.QueueUrl,
+			AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameQueueArn},
+		})
+		req.NoError(err, "failed to get queue arn")
+
+		subAttrs := map[string]string{"RawMessageDelivery": "true"}
+		if filterPolicy != "" {
+			subAttrs["FilterPolicy"] = filterPolicy
+		}
+		_, err = snsClient.Subscribe(ctx, &sns.SubscribeInput{
+			TopicArn:   topic.TopicArn,
+			Protocol:   aws.String("sqs"),
+			Endpoint:   aws.String(attrs.Attributes[string(sqstypes.QueueAttributeNameQueueArn)]),
+			Attributes: subAttrs,
+		})
+		req.NoError(err, "failed to subscribe queue to topic")
+		return aws.ToString(topic.TopicArn), aws.ToString(queue.QueueUrl)
+	}
+
+	receive := func(queueURL string) []sqstypes.Message {
+		out, err := sqsClient.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:              aws.String(queueURL),
+			MaxNumberOfMessages:   10,
+			WaitTimeSeconds:       5,
+			AttributeNames:        []sqstypes.QueueAttributeName{"MessageGroupId"},
+			MessageAttributeNames: []string{"All"},
+		})
+		req.NoError(err, "failed to receive messages")
+		return out.Messages
+	}
+
+	t.Run("standard topic", func(t *testing.T) {
+		topicARN, queueURL := subscribe("events", false, `{"ce_type": ["test"]}`)
+
+		notifier, err := NewSNS(ctx, SNSConfig{Region: "us-east-1", Endpoint: "http://localhost:4566", TopicARN: topicARN})
+		req.NoError(err, "failed to create sns notifier")
+
+		inner, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+		req.NoError(err, "failed to create memory event store")
+		store, err := NewStore(inner, notifier)
+		req.NoError(err, "failed to create notifier store")
+
+		// actual test
+		ev := newTestEvent("1", "test")
+		_, err = store.Append(ctx, ev, eventstore.AnyVersion)
+		req.NoError(err, "failed to put event")
+
+		filtered := newTestEvent("2", "test")
+		filtered.SetType("other")
+		_, err = store.Append(ctx, filtered, eventstore.AnyVersion)
+		req.NoError(err, "failed to put event")
+
+		// sqs verification
+		messages := receive(queueURL)
+		req.Len(messages, 1, "only events matching the filter policy should be received")
+		req.Equal("test", aws.ToString(messages[0].MessageAttributes["ce_type"].StringValue), "type not expected value")
+		req.Equal("notifier_test", aws.ToString(messages[0].MessageAttributes["ce_source"].StringValue), "source not expected value")
+
+		var published event.Event
+		req.NoError(published.UnmarshalJSON([]byte(aws.ToString(messages[0].Body))), "message should be a cloudevent")
+		req.Equal(ev.ID(), published.ID(), "id not expected value")
+		req.Equal(ev.Time(), published.Time(), "time is not equal")
+		req.JSONEq(`{"hello":"world"}`, string(published.Data()), "data not expected value")
+	})
+
+	t.Run("fifo topic", func(t *testing.T) {
+		topicARN, queueURL := subscribe("events.fifo", true, "")
+
+		notifier, err := NewSNS(ctx, SNSConfig{Region: "us-east-1", Endpoint: "http://localhost:4566", TopicARN: topicARN})
+		req.NoError(err, "failed to create sns notifier")
+		req.True(notifier.fifo, "topic should have been detected as fifo")
+
+		// actual test
+		ev := newTestEvent("1", "some-subject")
+		req.NoError(notifier.Notify(ctx, ev), "failed to notify")
+		req.NoError(notifier.Notify(ctx, ev), "failed to notify again")
+
+		// sqs verification
+		messages := receive(queueURL)
+		req.Len(messages, 1, "duplicate notifications should be deduplicated")
+		req.Equal("some-subject", messages[0].Attributes["MessageGroupId"], "message group should be the subject")
+	})
+}
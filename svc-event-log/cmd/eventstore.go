@@ -0,0 +1,178 @@
+// Copyright 2023 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+	"github.com/z5labs/evrys/svc-event-log/grpc"
+
+	"github.com/spf13/viper"
+)
+
+// UnknownEventStoreError
+type UnknownEventStoreError struct {
+	Name string
+}
+
+func (e UnknownEventStoreError) Error() string {
+	return fmt.Sprintf("unknown event store: %s", e.Name)
+}
+
+// UnknownEventStoreCacheError
+type UnknownEventStoreCacheError struct {
+	Name string
+}
+
+func (e UnknownEventStoreCacheError) Error() string {
+	return fmt.Sprintf("unknown event store cache: %s", e.Name)
+}
+
+// UnableToInitializeEventStoreError
+type UnableToInitializeEventStoreError struct {
+	Name  string
+	Cause error
+}
+
+func (e UnableToInitializeEventStoreError) Error() string {
+	return fmt.Sprintf("failed to initialize %s event store: %s", e.Name, e.Cause)
+}
+
+func (e UnableToInitializeEventStoreError) Unwrap() error {
+	return e.Cause
+}
+
+// newEventStore initializes the event store selected by the "event-store" key.
+// Each event store is configured by the config section of the same name.
+func newEventStore(ctx context.Context, v *viper.Viper) (grpc.EventStore, error) {
+	name := v.GetString("event-store")
+	switch name {
+	case "memory":
+		var cfg eventstore.MemoryConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		store, err := eventstore.NewMemory(cfg)
+		if err != nil {
+			return nil, UnableToInitializeEventStoreError{Name: name, Cause: err}
+		}
+		return store, nil
+	case "cosmosdb":
+		var cfg eventstore.CosmosDBConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		store, err := eventstore.NewCosmosDB(ctx, cfg)
+		if err != nil {
+			return nil, UnableToInitializeEventStoreError{Name: name, Cause: err}
+		}
+		return store, nil
+	case "dynamodb":
+		var cfg eventstore.DynamoDBConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		store, err := eventstore.NewDynamoDB(ctx, cfg)
+		if err != nil {
+			return nil, UnableToInitializeEventStoreError{Name: name, Cause: err}
+		}
+		return store, nil
+	case "file":
+		var cfg eventstore.FileConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		store, err := eventstore.NewFile(cfg)
+		if err != nil {
+			return nil, UnableToInitializeEventStoreError{Name: name, Cause: err}
+		}
+		return store, nil
+	case "mongo":
+		var cfg eventstore.MongoConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		store, err := eventstore.NewMongo(ctx, cfg)
+		if err != nil {
+			return nil, UnableToInitializeEventStoreError{Name: name, Cause: err}
+		}
+		return store, nil
+	case "postgres":
+		var cfg eventstore.PostgresConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		store, err := eventstore.NewPostgres(ctx, cfg)
+		if err != nil {
+			return nil, UnableToInitializeEventStoreError{Name: name, Cause: err}
+		}
+		return store, nil
+	case "sqlite":
+		var cfg eventstore.SQLiteConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		store, err := eventstore.NewSQLite(ctx, cfg)
+		if err != nil {
+			return nil, UnableToInitializeEventStoreError{Name: name, Cause: err}
+		}
+		return store, nil
+	default:
+		return nil, UnknownEventStoreError{Name: name}
+	}
+}
+
+// newEventStoreCache wraps the event store with the cache selected by the "event-store-cache" key,
+// if one is selected. Each cache is configured by the config section of the same name.
+func newEventStoreCache(ctx context.Context, v *viper.Viper, store grpc.EventStore) (grpc.EventStore, error) {
+	name := v.GetString("event-store-cache")
+	switch name {
+	case "":
+		return store, nil
+	case "memcached":
+		var cfg eventstore.MemcachedCacheConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		cache, err := eventstore.NewMemcachedCache(ctx, store, cfg)
+		if err != nil {
+			return nil, UnableToInitializeEventStoreError{Name: name, Cause: err}
+		}
+		return cache, nil
+	case "redis":
+		var cfg eventstore.RedisCacheConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		cache, err := eventstore.NewRedisCache(ctx, store, cfg)
+		if err != nil {
+			return nil, UnableToInitializeEventStoreError{Name: name, Cause: err}
+		}
+		return cache, nil
+	default:
+		return nil, UnknownEventStoreCacheError{Name: name}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+	"github.com/z5labs/evrys/lib/notifier"
+	"github.com/z5labs/evrys/svc-event-log/grpc"
+	"github.com/z5labs/evrys/svc-event-log/relay"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// UnknownNotifierError
+type UnknownNotifierError struct {
+	Name string
+}
+
+func (e UnknownNotifierError) Error() string {
+	return fmt.Sprintf("unknown notifier: %s", e.Name)
+}
+
+// UnableToInitializeNotifierError
+type UnableToInitializeNotifierError struct {
+	Name  string
+	Cause error
+}
+
+func (e UnableToInitializeNotifierError) Error() string {
+	return fmt.Sprintf("failed to initialize %s notifier: %s", e.Name, e.Cause)
+}
+
+func (e UnableToInitializeNotifierError) Unwrap() error {
+	return e.Cause
+}
+
+// newNotifier initializes the notifier selected by the "notifier" key, returning nil if none is selected.
+// Each notifier is configured by the config section of the same name.
+func newNotifier(ctx context.Context, v *viper.Viper) (notifier.Notifier, error) {
+	name := v.GetString("notifier")
+	switch name {
+	case "":
+		return nil, nil
+	case "azurequeue":
+		var cfg notifier.AzureQueueConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		n, err := notifier.NewAzureQueue(ctx, cfg)
+		if err != nil {
+			return nil, UnableToInitializeNotifierError{Name: name, Cause: err}
+		}
+		return n, nil
+	case "kafka":
+		var cfg notifier.KafkaConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		n, err := notifier.NewKafka(cfg)
+		if err != nil {
+			return nil, UnableToInitializeNotifierError{Name: name, Cause: err}
+		}
+		return n, nil
+	case "nats":
+		var cfg notifier.NATSConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		n, err := notifier.NewNATS(cfg)
+		if err != nil {
+			return nil, UnableToInitializeNotifierError{Name: name, Cause: err}
+		}
+		return n, nil
+	case "sns":
+		var cfg notifier.SNSConfig
+		err := v.UnmarshalKey(name, &cfg)
+		if err != nil {
+			return nil, UnableToLoadConfigFileError{Cause: err}
+		}
+		n, err := notifier.NewSNS(ctx, cfg)
+		if err != nil {
+			return nil, UnableToInitializeNotifierError{Name: name, Cause: err}
+		}
+		return n, nil
+	default:
+		return nil, UnknownNotifierError{Name: name}
+	}
+}
+
+// withNotifier publishes the events appended to the event store with the notifier, if one is given.
+//
+// If the outbox of the event store is enabled, by the "outbox" key of its config section, the events
+// are published by a relay reading them from the outbox, whose config is returned so it can be run
+// alongside the service. Otherwise the event store is wrapped so events are published as they are appended.
+func withNotifier(v *viper.Viper, store grpc.EventStore, n notifier.Notifier) (grpc.EventStore, *relay.Config, error) {
+	if n == nil {
+		return store, nil, nil
+	}
+
+	outbox, ok := store.(eventstore.Outbox)
+	if ok && v.GetBool(v.GetString("event-store")+".outbox") {
+		return store, &relay.Config{
+			Logger:       zap.L(),
+			Outbox:       outbox,
+			Notifier:     n,
+			PollInterval: v.GetDuration("relay.poll_interval"),
+			BatchSize:    v.GetInt("relay.batch_size"),
+		}, nil
+	}
+
+	notifyingStore, err := notifier.NewStore(store, n)
+	if err != nil {
+		return nil, nil, UnableToInitializeNotifierError{Name: v.GetString("notifier"), Cause: err}
+	}
+	return notifyingStore, nil, nil
+}
@@ -15,9 +15,18 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/z5labs/evrys/svc-event-log/grpc"
+	"github.com/z5labs/evrys/svc-event-log/relay"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
 func withServeGrpcCmd() func(*viper.Viper) *cobra.Command {
@@ -28,11 +37,72 @@ func withServeGrpcCmd() func(*viper.Viper) *cobra.Command {
 			PersistentPreRunE: withPersistentPreRun(
 				loadConfigFile(v),
 			)(v),
-			Run: func(cmd *cobra.Command, args []string) {
-				zap.L().Info("hello")
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+
+				store, err := newEventStore(ctx, v)
+				if err != nil {
+					return Error{Cmd: cmd, Cause: err}
+				}
+				n, err := newNotifier(ctx, v)
+				if err != nil {
+					return Error{Cmd: cmd, Cause: err}
+				}
+				store, relayCfg, err := withNotifier(v, store, n)
+				if err != nil {
+					return Error{Cmd: cmd, Cause: err}
+				}
+				store, err = newEventStoreCache(ctx, v, store)
+				if err != nil {
+					return Error{Cmd: cmd, Cause: err}
+				}
+				if closer, ok := store.(io.Closer); ok {
+					defer closer.Close()
+				}
+				if closer, ok := n.(io.Closer); ok && relayCfg != nil {
+					// the notifier is only closed along with the event store when it wraps it
+					defer closer.Close()
+				}
+
+				addr := v.GetString("addr")
+				ls, err := net.Listen("tcp", addr)
+				if err != nil {
+					return Error{Cmd: cmd, Cause: err}
+				}
+				zap.L().Info("serving grpc", zap.String("addr", ls.Addr().String()))
+
+				g, gctx := errgroup.WithContext(ctx)
+				g.Go(func() error {
+					return grpc.Serve(gctx, grpc.ServiceConfig{
+						Logger:         zap.L(),
+						EventStore:     store,
+						Listener:       ls,
+						Partitions:     v.GetUint32("partitions"),
+						SingleInstance: v.GetBool("single-instance"),
+					})
+				})
+				if relayCfg != nil {
+					zap.L().Info("relaying events from outbox", zap.String("notifier", v.GetString("notifier")))
+					g.Go(func() error {
+						return relay.Run(gctx, *relayCfg)
+					})
+				}
+
+				err = g.Wait()
+				if err != nil && !errors.Is(err, context.Canceled) {
+					return Error{Cmd: cmd, Cause: err}
+				}
+				return nil
 			},
 		}
 
+		// Flags
+		cmd.Flags().String("event-store", "mongo", "Specify event store, one of: cosmosdb, dynamodb, file, memory, mongo, postgres, sqlite")
+		cmd.Flags().String("event-store-cache", "", "Specify event store cache, one of: memcached, redis")
+		cmd.Flags().String("notifier", "", "Specify notifier to publish appended events with, one of: azurequeue, kafka, nats, sns")
+		cmd.Flags().Uint32("partitions", grpc.DefaultPartitions, "Number of partitions the events of a consumer group are split into between its members")
+		cmd.Flags().Bool("single-instance", false, "Declare this the only instance serving the event store, which lets clients subscribe as members of consumer groups")
+
 		return cmd
 	}
 }
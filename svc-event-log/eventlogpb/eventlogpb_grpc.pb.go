@@ -8,11 +8,9 @@ package eventlogpb
 
 import (
 	context "context"
-	pb "github.com/cloudevents/sdk-go/binding/format/protobuf/v2/pb"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -24,10 +22,33 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type EventLogClient interface {
-	// Append will append a new event to the log.
-	Append(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Append will append a new event to the log. If the stream the event
+	// belongs to is not at the expected version, an ABORTED status is returned.
+	Append(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResponse, error)
+	// AppendBatch will append several events to the log such that either
+	// all of them are appended or none of them are.
+	AppendBatch(ctx context.Context, in *AppendBatchRequest, opts ...grpc.CallOption) (*AppendBatchResponse, error)
+	// AppendStream will append every event sent on the stream to the log and
+	// acknowledge each of them, in the order they were sent, with either its
+	// position or why it was not appended. Events which arrive together are
+	// appended to the log in batches, and the server stops receiving events
+	// while too many are waiting to be acknowledged.
+	AppendStream(ctx context.Context, opts ...grpc.CallOption) (EventLog_AppendStreamClient, error)
+	// GetEvent will return the event with the given source and id. If there
+	// is no such event, a NOT_FOUND status is returned.
+	GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*Record, error)
 	// Iterate will iterate over the event log.
 	Iterate(ctx context.Context, in *IterateRequest, opts ...grpc.CallOption) (EventLog_IterateClient, error)
+	// ReadStream will read the events of a single stream in the order of
+	// their versions, or in reverse if reading backward.
+	ReadStream(ctx context.Context, in *ReadStreamRequest, opts ...grpc.CallOption) (EventLog_ReadStreamClient, error)
+	// Subscribe will iterate over the event log, like Iterate, and then
+	// keep the stream open and send events as they are appended. Each
+	// event is sent once and in the order they were appended.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (EventLog_SubscribeClient, error)
+	// Ack will commit the checkpoint of a consumer group, recording that
+	// the group has processed every event up to and including a position.
+	Ack(ctx context.Context, in *AckRequest, opts ...grpc.CallOption) (*AckResponse, error)
 }
 
 type eventLogClient struct {
@@ -38,8 +59,8 @@ func NewEventLogClient(cc grpc.ClientConnInterface) EventLogClient {
 	return &eventLogClient{cc}
 }
 
-func (c *eventLogClient) Append(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
-	out := new(emptypb.Empty)
+func (c *eventLogClient) Append(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResponse, error) {
+	out := new(AppendResponse)
 	err := c.cc.Invoke(ctx, "/eventlogpb.EventLog/Append", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -47,8 +68,57 @@ func (c *eventLogClient) Append(ctx context.Context, in *AppendRequest, opts ...
 	return out, nil
 }
 
+func (c *eventLogClient) AppendBatch(ctx context.Context, in *AppendBatchRequest, opts ...grpc.CallOption) (*AppendBatchResponse, error) {
+	out := new(AppendBatchResponse)
+	err := c.cc.Invoke(ctx, "/eventlogpb.EventLog/AppendBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventLogClient) AppendStream(ctx context.Context, opts ...grpc.CallOption) (EventLog_AppendStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EventLog_ServiceDesc.Streams[0], "/eventlogpb.EventLog/AppendStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventLogAppendStreamClient{stream}
+	return x, nil
+}
+
+type EventLog_AppendStreamClient interface {
+	Send(*AppendStreamRequest) error
+	Recv() (*AppendStreamResponse, error)
+	grpc.ClientStream
+}
+
+type eventLogAppendStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *eventLogAppendStreamClient) Send(m *AppendStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *eventLogAppendStreamClient) Recv() (*AppendStreamResponse, error) {
+	m := new(AppendStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *eventLogClient) GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*Record, error) {
+	out := new(Record)
+	err := c.cc.Invoke(ctx, "/eventlogpb.EventLog/GetEvent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *eventLogClient) Iterate(ctx context.Context, in *IterateRequest, opts ...grpc.CallOption) (EventLog_IterateClient, error) {
-	stream, err := c.cc.NewStream(ctx, &EventLog_ServiceDesc.Streams[0], "/eventlogpb.EventLog/Iterate", opts...)
+	stream, err := c.cc.NewStream(ctx, &EventLog_ServiceDesc.Streams[1], "/eventlogpb.EventLog/Iterate", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +133,7 @@ func (c *eventLogClient) Iterate(ctx context.Context, in *IterateRequest, opts .
 }
 
 type EventLog_IterateClient interface {
-	Recv() (*pb.CloudEvent, error)
+	Recv() (*Record, error)
 	grpc.ClientStream
 }
 
@@ -71,22 +141,118 @@ type eventLogIterateClient struct {
 	grpc.ClientStream
 }
 
-func (x *eventLogIterateClient) Recv() (*pb.CloudEvent, error) {
-	m := new(pb.CloudEvent)
+func (x *eventLogIterateClient) Recv() (*Record, error) {
+	m := new(Record)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
+func (c *eventLogClient) ReadStream(ctx context.Context, in *ReadStreamRequest, opts ...grpc.CallOption) (EventLog_ReadStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EventLog_ServiceDesc.Streams[2], "/eventlogpb.EventLog/ReadStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventLogReadStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EventLog_ReadStreamClient interface {
+	Recv() (*Record, error)
+	grpc.ClientStream
+}
+
+type eventLogReadStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *eventLogReadStreamClient) Recv() (*Record, error) {
+	m := new(Record)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *eventLogClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (EventLog_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EventLog_ServiceDesc.Streams[3], "/eventlogpb.EventLog/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventLogSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EventLog_SubscribeClient interface {
+	Recv() (*Record, error)
+	grpc.ClientStream
+}
+
+type eventLogSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *eventLogSubscribeClient) Recv() (*Record, error) {
+	m := new(Record)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *eventLogClient) Ack(ctx context.Context, in *AckRequest, opts ...grpc.CallOption) (*AckResponse, error) {
+	out := new(AckResponse)
+	err := c.cc.Invoke(ctx, "/eventlogpb.EventLog/Ack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EventLogServer is the server API for EventLog service.
 // All implementations must embed UnimplementedEventLogServer
 // for forward compatibility
 type EventLogServer interface {
-	// Append will append a new event to the log.
-	Append(context.Context, *AppendRequest) (*emptypb.Empty, error)
+	// Append will append a new event to the log. If the stream the event
+	// belongs to is not at the expected version, an ABORTED status is returned.
+	Append(context.Context, *AppendRequest) (*AppendResponse, error)
+	// AppendBatch will append several events to the log such that either
+	// all of them are appended or none of them are.
+	AppendBatch(context.Context, *AppendBatchRequest) (*AppendBatchResponse, error)
+	// AppendStream will append every event sent on the stream to the log and
+	// acknowledge each of them, in the order they were sent, with either its
+	// position or why it was not appended. Events which arrive together are
+	// appended to the log in batches, and the server stops receiving events
+	// while too many are waiting to be acknowledged.
+	AppendStream(EventLog_AppendStreamServer) error
+	// GetEvent will return the event with the given source and id. If there
+	// is no such event, a NOT_FOUND status is returned.
+	GetEvent(context.Context, *GetEventRequest) (*Record, error)
 	// Iterate will iterate over the event log.
 	Iterate(*IterateRequest, EventLog_IterateServer) error
+	// ReadStream will read the events of a single stream in the order of
+	// their versions, or in reverse if reading backward.
+	ReadStream(*ReadStreamRequest, EventLog_ReadStreamServer) error
+	// Subscribe will iterate over the event log, like Iterate, and then
+	// keep the stream open and send events as they are appended. Each
+	// event is sent once and in the order they were appended.
+	Subscribe(*SubscribeRequest, EventLog_SubscribeServer) error
+	// Ack will commit the checkpoint of a consumer group, recording that
+	// the group has processed every event up to and including a position.
+	Ack(context.Context, *AckRequest) (*AckResponse, error)
 	mustEmbedUnimplementedEventLogServer()
 }
 
@@ -94,12 +260,30 @@ type EventLogServer interface {
 type UnimplementedEventLogServer struct {
 }
 
-func (UnimplementedEventLogServer) Append(context.Context, *AppendRequest) (*emptypb.Empty, error) {
+func (UnimplementedEventLogServer) Append(context.Context, *AppendRequest) (*AppendResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Append not implemented")
 }
+func (UnimplementedEventLogServer) AppendBatch(context.Context, *AppendBatchRequest) (*AppendBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppendBatch not implemented")
+}
+func (UnimplementedEventLogServer) AppendStream(EventLog_AppendStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method AppendStream not implemented")
+}
+func (UnimplementedEventLogServer) GetEvent(context.Context, *GetEventRequest) (*Record, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEvent not implemented")
+}
 func (UnimplementedEventLogServer) Iterate(*IterateRequest, EventLog_IterateServer) error {
 	return status.Errorf(codes.Unimplemented, "method Iterate not implemented")
 }
+func (UnimplementedEventLogServer) ReadStream(*ReadStreamRequest, EventLog_ReadStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReadStream not implemented")
+}
+func (UnimplementedEventLogServer) Subscribe(*SubscribeRequest, EventLog_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedEventLogServer) Ack(context.Context, *AckRequest) (*AckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ack not implemented")
+}
 func (UnimplementedEventLogServer) mustEmbedUnimplementedEventLogServer() {}
 
 // UnsafeEventLogServer may be embedded to opt out of forward compatibility for this service.
@@ -131,6 +315,68 @@ func _EventLog_Append_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EventLog_AppendBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventLogServer).AppendBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eventlogpb.EventLog/AppendBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventLogServer).AppendBatch(ctx, req.(*AppendBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventLog_AppendStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EventLogServer).AppendStream(&eventLogAppendStreamServer{stream})
+}
+
+type EventLog_AppendStreamServer interface {
+	Send(*AppendStreamResponse) error
+	Recv() (*AppendStreamRequest, error)
+	grpc.ServerStream
+}
+
+type eventLogAppendStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *eventLogAppendStreamServer) Send(m *AppendStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *eventLogAppendStreamServer) Recv() (*AppendStreamRequest, error) {
+	m := new(AppendStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _EventLog_GetEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventLogServer).GetEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eventlogpb.EventLog/GetEvent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventLogServer).GetEvent(ctx, req.(*GetEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _EventLog_Iterate_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(IterateRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -140,7 +386,7 @@ func _EventLog_Iterate_Handler(srv interface{}, stream grpc.ServerStream) error
 }
 
 type EventLog_IterateServer interface {
-	Send(*pb.CloudEvent) error
+	Send(*Record) error
 	grpc.ServerStream
 }
 
@@ -148,10 +394,70 @@ type eventLogIterateServer struct {
 	grpc.ServerStream
 }
 
-func (x *eventLogIterateServer) Send(m *pb.CloudEvent) error {
+func (x *eventLogIterateServer) Send(m *Record) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _EventLog_ReadStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventLogServer).ReadStream(m, &eventLogReadStreamServer{stream})
+}
+
+type EventLog_ReadStreamServer interface {
+	Send(*Record) error
+	grpc.ServerStream
+}
+
+type eventLogReadStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *eventLogReadStreamServer) Send(m *Record) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EventLog_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventLogServer).Subscribe(m, &eventLogSubscribeServer{stream})
+}
+
+type EventLog_SubscribeServer interface {
+	Send(*Record) error
+	grpc.ServerStream
+}
+
+type eventLogSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *eventLogSubscribeServer) Send(m *Record) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EventLog_Ack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventLogServer).Ack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/eventlogpb.EventLog/Ack",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventLogServer).Ack(ctx, req.(*AckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // EventLog_ServiceDesc is the grpc.ServiceDesc for EventLog service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -163,13 +469,41 @@ var EventLog_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Append",
 			Handler:    _EventLog_Append_Handler,
 		},
+		{
+			MethodName: "AppendBatch",
+			Handler:    _EventLog_AppendBatch_Handler,
+		},
+		{
+			MethodName: "GetEvent",
+			Handler:    _EventLog_GetEvent_Handler,
+		},
+		{
+			MethodName: "Ack",
+			Handler:    _EventLog_Ack_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "AppendStream",
+			Handler:       _EventLog_AppendStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "Iterate",
 			Handler:       _EventLog_Iterate_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ReadStream",
+			Handler:       _EventLog_ReadStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _EventLog_Subscribe_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "svc-event-log/eventlogpb/eventlogpb.proto",
 }
@@ -0,0 +1,167 @@
+// Copyright 2023 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultPartitions is the number of partitions the events of a consumer group
+// are split into between its members if none is configured
+const DefaultPartitions = 16
+
+// partitionCheckpoint returns the name the checkpoint of a partition of a consumer group is committed under
+func partitionCheckpoint(group string, partition uint32) string {
+	return group + "#" + strconv.FormatUint(uint64(partition), 10)
+}
+
+// groups tracks the members of each consumer group and assigns the partitions of the group
+// between them. Membership is only tracked in memory, so the members of a group must all be
+// connected to the same instance of the service.
+//
+// Each assignment of partitions to a member is identified by a generation, which is unique across
+// every member of every group, so an ack can be traced to the member its event was sent to and
+// deliveries and acks made under an earlier assignment can be told apart.
+type groups struct {
+	partitions uint32
+
+	mu         sync.Mutex
+	nextID     uint64
+	generation uint64
+	members    map[string][]*member
+}
+
+func newGroups(partitions uint32) *groups {
+	return &groups{
+		partitions: partitions,
+		members:    make(map[string][]*member),
+	}
+}
+
+// member is a client which has joined a consumer group. Its fields are guarded by the lock of groups.
+type member struct {
+	id    uint64
+	name  string
+	group string
+
+	assigned   []uint32
+	generation uint64
+	rebalanced chan struct{}
+}
+
+// join adds a member to the group and reassigns the partitions of the group
+func (g *groups) join(group, name string) *member {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	m := &member{
+		id:         g.nextID,
+		name:       name,
+		group:      group,
+		rebalanced: make(chan struct{}),
+	}
+	g.nextID++
+	g.members[group] = append(g.members[group], m)
+	g.rebalance(group)
+	return m
+}
+
+// leave removes a member from its group and reassigns the partitions of the group
+func (g *groups) leave(m *member) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	members := g.members[m.group]
+	for i, other := range members {
+		if other == m {
+			members = append(members[:i], members[i+1:]...)
+			break
+		}
+	}
+	g.members[m.group] = members
+	g.rebalance(m.group)
+}
+
+// assignment returns the partitions currently assigned to the member and the generation of
+// the assignment, along with a channel which is closed once the partitions of its group are reassigned
+func (g *groups) assignment(m *member) ([]uint32, uint64, <-chan struct{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	partitions := make([]uint32, len(m.assigned))
+	copy(partitions, m.assigned)
+	return partitions, m.generation, m.rebalanced
+}
+
+// owns reports whether the partitions assigned to the member are still those of the generation
+func (g *groups) owns(m *member, generation uint64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return m.generation == generation
+}
+
+// assigned reports whether the partition is currently assigned to the member of the group which
+// was given the generation, i.e. whether that member may still commit the checkpoint of the partition
+func (g *groups) assigned(group string, partition uint32, generation uint64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, m := range g.members[group] {
+		if m.generation != generation {
+			continue
+		}
+		for _, p := range m.assigned {
+			if p == partition {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// rebalance assigns the partitions of a group round robin between its members, ordered by
+// their names and then by when they joined, under a new generation for each member and notifies
+// every member. The caller must hold the lock.
+func (g *groups) rebalance(group string) {
+	members := g.members[group]
+	if len(members) == 0 {
+		delete(g.members, group)
+		return
+	}
+
+	sort.SliceStable(members, func(i, j int) bool {
+		if members[i].name != members[j].name {
+			return members[i].name < members[j].name
+		}
+		return members[i].id < members[j].id
+	})
+	for _, m := range members {
+		m.assigned = nil
+	}
+	for p := uint32(0); p < g.partitions; p++ {
+		m := members[int(p)%len(members)]
+		m.assigned = append(m.assigned, p)
+	}
+	for _, m := range members {
+		g.generation++
+		m.generation = g.generation
+		close(m.rebalanced)
+		m.rebalanced = make(chan struct{})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroups(t *testing.T) {
+	t.Run("will assign every partition under a new generation", func(t *testing.T) {
+		t.Run("if a member joins or leaves the group", func(t *testing.T) {
+			g := newGroups(4)
+
+			a := g.join("projector", "a")
+			partitionsA, generationA, _ := g.assignment(a)
+			if !assert.Equal(t, []uint32{0, 1, 2, 3}, partitionsA) {
+				return
+			}
+			if !assert.True(t, g.owns(a, generationA)) {
+				return
+			}
+
+			b := g.join("projector", "b")
+			partitionsA, generation, _ := g.assignment(a)
+			if !assert.Equal(t, []uint32{0, 2}, partitionsA) {
+				return
+			}
+			partitionsB, generationB, _ := g.assignment(b)
+			if !assert.Equal(t, []uint32{1, 3}, partitionsB) {
+				return
+			}
+			if !assert.NotEqual(t, generation, generationB) {
+				return
+			}
+			if !assert.NotEqual(t, generationA, generation) {
+				return
+			}
+			if !assert.False(t, g.owns(a, generationA)) {
+				return
+			}
+			if !assert.False(t, g.assigned("projector", 0, generationA)) {
+				return
+			}
+			if !assert.True(t, g.assigned("projector", 0, generation)) {
+				return
+			}
+
+			g.leave(b)
+			if !assert.False(t, g.owns(a, generation)) {
+				return
+			}
+			g.leave(a)
+			_, generation, _ = g.assignment(a)
+			if !assert.False(t, g.assigned("projector", 0, generation)) {
+				return
+			}
+		})
+	})
+
+	t.Run("will not share generations between groups", func(t *testing.T) {
+		t.Run("if members join different groups", func(t *testing.T) {
+			g := newGroups(4)
+
+			a := g.join("projector", "a")
+			_, generationA, _ := g.assignment(a)
+			b := g.join("mailer", "b")
+			_, generationB, _ := g.assignment(b)
+
+			if !assert.NotEqual(t, generationA, generationB) {
+				return
+			}
+			if !assert.True(t, g.owns(a, generationA)) {
+				return
+			}
+			if !assert.False(t, g.assigned("mailer", 0, generationA)) {
+				return
+			}
+		})
+	})
+
+	t.Run("will only report a partition as assigned to its member", func(t *testing.T) {
+		t.Run("if another member of the group acks it", func(t *testing.T) {
+			g := newGroups(4)
+
+			a := g.join("projector", "a")
+			b := g.join("projector", "b")
+			_, generationA, _ := g.assignment(a)
+			_, generationB, _ := g.assignment(b)
+
+			if !assert.True(t, g.assigned("projector", 1, generationB)) {
+				return
+			}
+			if !assert.False(t, g.assigned("projector", 1, generationA)) {
+				return
+			}
+		})
+	})
+}
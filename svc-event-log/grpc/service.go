@@ -17,23 +17,33 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net"
+	"strings"
 
 	"github.com/z5labs/evrys/lib/eventstore"
 	"github.com/z5labs/evrys/svc-event-log/eventlogpb"
 
 	format "github.com/cloudevents/sdk-go/binding/format/protobuf/v2"
+	"github.com/cloudevents/sdk-go/binding/format/protobuf/v2/pb"
+	"github.com/cloudevents/sdk-go/v2/event"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 // EventStore
 type EventStore interface {
 	eventstore.AppendOnly
+	eventstore.BatchAppendOnly
+	eventstore.Iterable
+	eventstore.StreamReader
+	eventstore.Gettable
+	eventstore.Checkpointer
 }
 
 // ServiceConfig
@@ -41,6 +51,15 @@ type ServiceConfig struct {
 	Logger     *zap.Logger
 	EventStore EventStore
 	Listener   net.Listener
+
+	// Partitions is the number of partitions the events of a consumer group are split
+	// into between its members, which defaults to DefaultPartitions
+	Partitions uint32
+
+	// SingleInstance declares that this is the only instance of the service serving the
+	// event store. The members of consumer groups are only tracked in memory, so clients
+	// may only subscribe as members when it is set.
+	SingleInstance bool
 }
 
 // Serve
@@ -51,9 +70,14 @@ func Serve(ctx context.Context, cfg ServiceConfig) error {
 	if cfg.Listener == nil {
 		return errors.New("listener must be set")
 	}
+	if cfg.Partitions == 0 {
+		cfg.Partitions = DefaultPartitions
+	}
 	s := &service{
-		log:   cfg.Logger,
-		store: cfg.EventStore,
+		log:            cfg.Logger,
+		store:          cfg.EventStore,
+		groups:         newGroups(cfg.Partitions),
+		singleInstance: cfg.SingleInstance,
 	}
 	if s.log == nil {
 		s.log = zap.NewNop()
@@ -86,12 +110,14 @@ func Serve(ctx context.Context, cfg ServiceConfig) error {
 type service struct {
 	eventlogpb.UnimplementedEventLogServer
 
-	log   *zap.Logger
-	store EventStore
+	log            *zap.Logger
+	store          EventStore
+	groups         *groups
+	singleInstance bool
 }
 
 // Append
-func (s *service) Append(ctx context.Context, req *eventlogpb.AppendRequest) (*emptypb.Empty, error) {
+func (s *service) Append(ctx context.Context, req *eventlogpb.AppendRequest) (*eventlogpb.AppendResponse, error) {
 	if req.Event == nil {
 		s.log.Warn("client attempted to append nil cloudevent to log")
 		return nil, status.Error(codes.InvalidArgument, "cloudevent must be non-nil")
@@ -121,7 +147,7 @@ func (s *service) Append(ctx context.Context, req *eventlogpb.AppendRequest) (*e
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	err = s.store.Append(ctx, ev)
+	position, err := s.store.Append(ctx, ev, expectedVersion(req.ExpectedVersion))
 	if err != nil {
 		s.log.Error(
 			"failed to append cloudevent to log",
@@ -130,19 +156,725 @@ func (s *service) Append(ctx context.Context, req *eventlogpb.AppendRequest) (*e
 			zap.String("event_source", ev.Source()),
 			zap.Error(err),
 		)
-		return nil, status.Error(codes.Unavailable, err.Error())
+		return nil, status.Error(appendErrorCode(err), err.Error())
 	}
 	s.log.Debug(
 		"appended event to log",
+		zap.Uint64("position", position),
 		zap.String("event_id", ev.ID()),
 		zap.String("event_type", ev.Type()),
 		zap.String("event_source", ev.Source()),
 	)
 
-	return &emptypb.Empty{}, nil
+	return &eventlogpb.AppendResponse{Position: position}, nil
+}
+
+// AppendBatch
+func (s *service) AppendBatch(ctx context.Context, req *eventlogpb.AppendBatchRequest) (*eventlogpb.AppendBatchResponse, error) {
+	if len(req.Events) == 0 {
+		s.log.Warn("client attempted to append an empty batch to log")
+		return nil, status.Error(codes.InvalidArgument, "at least one cloudevent must be provided")
+	}
+
+	events := make([]*event.Event, len(req.Events))
+	for i, pbEvent := range req.Events {
+		if pbEvent == nil {
+			s.log.Warn("client attempted to append nil cloudevent to log", zap.Int("index", i))
+			return nil, status.Error(codes.InvalidArgument, "cloudevent must be non-nil")
+		}
+
+		ev, err := format.FromProto(pbEvent)
+		if err != nil {
+			s.log.Error("failed to convert cloudevent protobuf to generic cloudevent", zap.Int("index", i))
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		err = ev.Validate()
+		if err != nil {
+			s.log.Error(
+				"received invalid cloudevent",
+				zap.Int("index", i),
+				zap.String("event_id", ev.ID()),
+				zap.String("event_type", ev.Type()),
+				zap.String("event_source", ev.Source()),
+				zap.Error(err),
+			)
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		events[i] = ev
+	}
+	s.log.Debug("received batch of events to append to log", zap.Int("events", len(events)))
+
+	positions, err := s.store.AppendBatch(ctx, events, expectedVersion(req.ExpectedVersion))
+	if err != nil {
+		s.log.Error(
+			"failed to append batch of cloudevents to log",
+			zap.Int("events", len(events)),
+			zap.Error(err),
+		)
+		return nil, status.Error(appendErrorCode(err), err.Error())
+	}
+	s.log.Debug("appended batch of events to log", zap.Int("events", len(events)))
+
+	return &eventlogpb.AppendBatchResponse{Positions: positions}, nil
+}
+
+// maxAppendStreamBatch is the most events AppendStream appends to the event store at once, which
+// is also how many events it receives ahead of acknowledging them before it stops receiving
+const maxAppendStreamBatch = 500
+
+// streamedEvent is an event received by AppendStream along with its index within the stream
+type streamedEvent struct {
+	sequence uint64
+	event    *event.Event
+
+	// err is a status error if the event could not be converted or is invalid
+	err error
+}
+
+// AppendStream
+func (s *service) AppendStream(stream eventlogpb.EventLog_AppendStreamServer) error {
+	ctx := stream.Context()
+
+	events := make(chan streamedEvent, maxAppendStreamBatch)
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		defer close(events)
+		for sequence := uint64(0); ; sequence++ {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			se := streamedEvent{sequence: sequence}
+			se.event, se.err = eventFromProto(req.Event)
+			if se.err != nil {
+				s.log.Warn("received invalid cloudevent", zap.Uint64("sequence", sequence), zap.Error(se.err))
+			}
+
+			select {
+			case <-gctx.Done():
+				return gctx.Err()
+			case events <- se:
+			}
+		}
+	})
+	g.Go(func() error {
+		batch := make([]streamedEvent, 0, maxAppendStreamBatch)
+		for se := range events {
+			// every event which has already been received is appended along with the first
+			batch = append(batch[:0], se)
+		drain:
+			for len(batch) < maxAppendStreamBatch {
+				select {
+				case se, ok := <-events:
+					if !ok {
+						break drain
+					}
+					batch = append(batch, se)
+				default:
+					break drain
+				}
+			}
+
+			err := s.appendStreamed(gctx, batch, stream.Send)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	err := g.Wait()
+	if ctx.Err() != nil {
+		s.log.Debug("client stopped appending to log", zap.Error(ctx.Err()))
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return err
+}
+
+// appendStreamed appends the valid events of the batch to the event store and acknowledges every event
+// of the batch. If the batch can not be appended as a whole because of some of its events, or since the
+// event store can not append them at once, the events are appended one by one so that only the events
+// which can not be appended are rejected. Any other failure is acknowledged as such for every event.
+func (s *service) appendStreamed(ctx context.Context, batch []streamedEvent, send func(*eventlogpb.AppendStreamResponse) error) error {
+	resps := make([]*eventlogpb.AppendStreamResponse, len(batch))
+	var valid []int
+	var events []*event.Event
+	for i, se := range batch {
+		resps[i] = &eventlogpb.AppendStreamResponse{Sequence: se.sequence}
+		if se.err != nil {
+			resps[i].Error = appendError(se.err)
+			continue
+		}
+		valid = append(valid, i)
+		events = append(events, se.event)
+	}
+
+	if len(events) > 0 {
+		positions, err := s.store.AppendBatch(ctx, events, eventstore.AnyVersion)
+		switch {
+		case err == nil:
+			for j, position := range positions {
+				resps[valid[j]].Position = position
+			}
+		case ctx.Err() != nil:
+		case !appendIndividually(err):
+			s.log.Error("failed to append batch of cloudevents to log", zap.Int("events", len(events)), zap.Error(err))
+			for _, i := range valid {
+				resps[i].Error = appendError(status.Error(appendErrorCode(err), err.Error()))
+			}
+		default:
+			s.log.Warn(
+				"failed to append batch of cloudevents to log, appending them individually",
+				zap.Int("events", len(events)),
+				zap.Error(err),
+			)
+			for j, i := range valid {
+				ev := events[j]
+				position, err := s.store.Append(ctx, ev, eventstore.AnyVersion)
+				if err != nil {
+					s.log.Error(
+						"failed to append cloudevent to log",
+						zap.Uint64("sequence", batch[i].sequence),
+						zap.String("event_id", ev.ID()),
+						zap.String("event_type", ev.Type()),
+						zap.String("event_source", ev.Source()),
+						zap.Error(err),
+					)
+					resps[i].Error = appendError(status.Error(appendErrorCode(err), err.Error()))
+					continue
+				}
+				resps[i].Position = position
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.log.Debug("appended batch of streamed events to log", zap.Int("events", len(events)))
+	}
+
+	for _, resp := range resps {
+		err := send(resp)
+		if err != nil {
+			s.log.Error("failed to send append acknowledgement to client", zap.Uint64("sequence", resp.Sequence), zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// eventFromProto converts a protobuf cloudevent and validates it, returning an InvalidArgument status error if it is not valid
+func eventFromProto(pbEvent *pb.CloudEvent) (*event.Event, error) {
+	if pbEvent == nil {
+		return nil, status.Error(codes.InvalidArgument, "cloudevent must be non-nil")
+	}
+	ev, err := format.FromProto(pbEvent)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	err = ev.Validate()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return ev, nil
+}
+
+func appendError(err error) *eventlogpb.AppendError {
+	s := status.Convert(err)
+	return &eventlogpb.AppendError{
+		Code:    uint32(s.Code()),
+		Message: s.Message(),
+	}
+}
+
+func expectedVersion(v *uint64) uint64 {
+	if v == nil {
+		return eventstore.AnyVersion
+	}
+	return *v
+}
+
+// appendErrorCode maps an error from appending to the event store to a grpc status code
+func appendErrorCode(err error) codes.Code {
+	var conflictErr *eventstore.VersionConflictError
+	if errors.As(err, &conflictErr) {
+		return codes.Aborted
+	}
+	var dupErr *eventstore.DuplicateEventError
+	if errors.As(err, &dupErr) {
+		return codes.AlreadyExists
+	}
+	var limitErr *eventstore.RetryLimitError
+	if errors.As(err, &limitErr) {
+		return codes.Aborted
+	}
+	var unbatchableErr *eventstore.UnbatchableError
+	if errors.As(err, &unbatchableErr) {
+		return codes.FailedPrecondition
+	}
+	return codes.Unavailable
+}
+
+// appendIndividually reports whether a batch which failed to be appended with the error may still be
+// appended one event at a time, i.e. whether it failed because of some of its events or since the event
+// store can not append them at once, rather than the event store being unavailable
+func appendIndividually(err error) bool {
+	var conflictErr *eventstore.VersionConflictError
+	var dupErr *eventstore.DuplicateEventError
+	var unbatchableErr *eventstore.UnbatchableError
+	return errors.As(err, &conflictErr) || errors.As(err, &dupErr) || errors.As(err, &unbatchableErr)
+}
+
+// GetEvent
+func (s *service) GetEvent(ctx context.Context, req *eventlogpb.GetEventRequest) (*eventlogpb.Record, error) {
+	if req.Source == "" || req.Id == "" {
+		s.log.Warn("client attempted to get an event without its source and id")
+		return nil, status.Error(codes.InvalidArgument, "source and id must be provided")
+	}
+
+	rec, err := s.store.Get(ctx, req.Source, req.Id)
+	var notFoundErr *eventstore.EventNotFoundError
+	if errors.As(err, &notFoundErr) {
+		s.log.Debug("event does not exist", zap.String("event_id", req.Id), zap.String("event_source", req.Source))
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		s.log.Error(
+			"failed to get cloudevent from log",
+			zap.String("event_id", req.Id),
+			zap.String("event_source", req.Source),
+			zap.Error(err),
+		)
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	pbEvent, err := format.ToProto(rec.Event)
+	if err != nil {
+		s.log.Error(
+			"failed to convert generic cloudevent to cloudevent protobuf",
+			zap.String("event_id", req.Id),
+			zap.String("event_source", req.Source),
+			zap.Error(err),
+		)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &eventlogpb.Record{
+		Position: rec.Position,
+		Event:    pbEvent,
+	}, nil
 }
 
 // Iterate
 func (s *service) Iterate(req *eventlogpb.IterateRequest, stream eventlogpb.EventLog_IterateServer) error {
+	ctx := stream.Context()
+
+	filter, err := filterFromProto(req.Filter)
+	if err != nil {
+		s.log.Warn("client provided an invalid filter", zap.Error(err))
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	filter.AfterPosition, err = s.startPosition(ctx, req.Group, req.AfterPosition)
+	if err != nil {
+		return err
+	}
+
+	iter, err := s.store.Iterate(ctx, filter)
+	if err != nil {
+		s.log.Error("failed to iterate over log", zap.Error(err))
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer func() {
+		err := iter.Close(ctx)
+		if err != nil {
+			s.log.Warn("failed to close log iterator", zap.Error(err))
+		}
+	}()
+
+	return s.send(ctx, iter, stream.Send)
+}
+
+// ReadStream
+func (s *service) ReadStream(req *eventlogpb.ReadStreamRequest, stream eventlogpb.EventLog_ReadStreamServer) error {
+	ctx := stream.Context()
+
+	if req.Stream == "" {
+		s.log.Warn("client attempted to read a stream without its id")
+		return status.Error(codes.InvalidArgument, "stream must be provided")
+	}
+
+	iter, err := s.store.ReadStream(ctx, req.Stream, eventstore.ReadStreamOptions{
+		FromVersion: req.FromVersion,
+		MaxCount:    req.MaxCount,
+		Backward:    req.Backward,
+	})
+	if err != nil {
+		s.log.Error("failed to read stream", zap.String("stream", req.Stream), zap.Error(err))
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer func() {
+		err := iter.Close(ctx)
+		if err != nil {
+			s.log.Warn("failed to close stream iterator", zap.Error(err))
+		}
+	}()
+
+	return s.send(ctx, iter, stream.Send)
+}
+
+// Subscribe
+func (s *service) Subscribe(req *eventlogpb.SubscribeRequest, stream eventlogpb.EventLog_SubscribeServer) error {
+	ctx := stream.Context()
+
+	filter, err := filterFromProto(req.Filter)
+	if err != nil {
+		s.log.Warn("client provided an invalid filter", zap.Error(err))
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.Member != "" {
+		err = validateGroup(req.Group)
+		if err != nil {
+			s.log.Warn("client provided an invalid consumer group", zap.Error(err))
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if !s.singleInstance {
+			s.log.Warn("client subscribed as a member of a consumer group to a service which may not be the only instance", zap.String("group", req.Group))
+			return status.Error(codes.FailedPrecondition, "consumer group members are only supported by a service configured as the only instance")
+		}
+		filter.AfterPosition = req.AfterPosition
+		return s.subscribeMember(ctx, req.Group, req.Member, filter, stream.Send)
+	}
+	filter.AfterPosition, err = s.startPosition(ctx, req.Group, req.AfterPosition)
+	if err != nil {
+		return err
+	}
+
+	iter, err := eventstore.Subscribe(ctx, s.store, filter)
+	if err != nil {
+		s.log.Error("failed to subscribe to log", zap.Error(err))
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer func() {
+		err := iter.Close(ctx)
+		if err != nil {
+			s.log.Warn("failed to close log subscription", zap.Error(err))
+		}
+	}()
+
+	return s.send(ctx, iter, stream.Send)
+}
+
+// errRebalanced is returned by sendPartitions once the partitions of the group of the member are reassigned
+var errRebalanced = errors.New("partitions of consumer group were reassigned")
+
+// subscribeMember joins the consumer group and sends the events of the partitions assigned to the
+// member until the client disconnects. Whenever the partitions of the group are reassigned the
+// subscription is restarted from the earliest position of the partitions the member now owns.
+func (s *service) subscribeMember(ctx context.Context, group, name string, filter eventstore.Filter, send func(*eventlogpb.Record) error) error {
+	m := s.groups.join(group, name)
+	defer s.groups.leave(m)
+	s.log.Debug("member joined consumer group", zap.String("group", group), zap.String("member", name))
+
+	// delivered tracks the position of the last event sent for each partition owned by the member,
+	// so events are not sent twice when the subscription is restarted on a rebalance
+	delivered := make(map[uint32]uint64)
+	for {
+		partitions, generation, rebalanced := s.groups.assignment(m)
+		if len(partitions) == 0 {
+			s.log.Debug("member has no partitions assigned", zap.String("group", group), zap.String("member", name))
+			select {
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			case <-rebalanced:
+				continue
+			}
+		}
+
+		owned := make(map[uint32]uint64, len(partitions))
+		start := uint64(math.MaxUint64)
+		for _, p := range partitions {
+			checkpoint, err := s.store.Checkpoint(ctx, partitionCheckpoint(group, p))
+			if err != nil {
+				s.log.Error("failed to get checkpoint", zap.String("group", group), zap.Uint32("partition", p), zap.Error(err))
+				return status.Error(codes.Unavailable, err.Error())
+			}
+			position := filter.AfterPosition
+			if checkpoint > position {
+				position = checkpoint
+			}
+			if delivered[p] > position {
+				position = delivered[p]
+			}
+			owned[p] = position
+			if position < start {
+				start = position
+			}
+		}
+		delivered = owned
+		s.log.Debug(
+			"member assigned partitions",
+			zap.String("group", group),
+			zap.String("member", name),
+			zap.Uint32s("partitions", partitions),
+			zap.Uint64("generation", generation),
+		)
+
+		partitionFilter := filter
+		partitionFilter.AfterPosition = start
+		err := s.sendPartitions(ctx, m, generation, partitionFilter, delivered, rebalanced, send)
+		if err != errRebalanced {
+			return err
+		}
+	}
+}
+
+// sendPartitions sends every event after the delivered position of its partition, for the partitions
+// in delivered which are assigned to the member under the generation, until the partitions are reassigned,
+// in which case errRebalanced is returned. Each event is sent tagged with the generation, so an ack of an
+// event sent just before the partitions were reassigned can be rejected.
+func (s *service) sendPartitions(ctx context.Context, m *member, generation uint64, filter eventstore.Filter, delivered map[uint32]uint64, rebalanced <-chan struct{}, send func(*eventlogpb.Record) error) error {
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-subCtx.Done():
+		case <-rebalanced:
+			cancel()
+		}
+	}()
+
+	iter, err := eventstore.Subscribe(subCtx, s.store, filter)
+	if err != nil {
+		s.log.Error("failed to subscribe to log", zap.Error(err))
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer func() {
+		err := iter.Close(ctx)
+		if err != nil {
+			s.log.Warn("failed to close log subscription", zap.Error(err))
+		}
+	}()
+
+	for {
+		rec, err := iter.Next(subCtx)
+		if ctx.Err() != nil {
+			s.log.Debug("client stopped reading from log", zap.Error(ctx.Err()))
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		select {
+		case <-rebalanced:
+			// the event may belong to a partition which is now assigned to another member
+			return errRebalanced
+		default:
+		}
+		if err != nil {
+			s.log.Error("failed to read next cloudevent from log", zap.Error(err))
+			return status.Error(codes.Unavailable, err.Error())
+		}
+
+		p := eventstore.PartitionOf(rec.Event, s.groups.partitions)
+		position, ok := delivered[p]
+		if !ok || rec.Position <= position {
+			continue
+		}
+		if !s.groups.owns(m, generation) {
+			return errRebalanced
+		}
+
+		err = s.sendRecord(rec, &p, &generation, send)
+		if err != nil {
+			return err
+		}
+		delivered[p] = rec.Position
+	}
+}
+
+// Ack
+func (s *service) Ack(ctx context.Context, req *eventlogpb.AckRequest) (*eventlogpb.AckResponse, error) {
+	err := validateGroup(req.Group)
+	if err != nil {
+		s.log.Warn("client provided an invalid consumer group", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	checkpoint := req.Group
+	if req.Partition != nil {
+		if *req.Partition >= s.groups.partitions {
+			s.log.Warn("client provided an invalid partition", zap.Uint32("partition", *req.Partition))
+			return nil, status.Errorf(codes.InvalidArgument, "partition must be less than %d", s.groups.partitions)
+		}
+		if req.Generation == nil {
+			s.log.Warn("client acked a partition without its generation", zap.Uint32("partition", *req.Partition))
+			return nil, status.Error(codes.InvalidArgument, "generation must be provided along with partition")
+		}
+		if !s.groups.assigned(req.Group, *req.Partition, *req.Generation) {
+			s.log.Warn(
+				"client acked a partition which is not assigned to it",
+				zap.String("group", req.Group),
+				zap.Uint32("partition", *req.Partition),
+				zap.Uint64("generation", *req.Generation),
+			)
+			return nil, status.Error(codes.FailedPrecondition, "partition is not assigned to the member the event was sent to, since the partitions of consumer group were reassigned")
+		}
+		checkpoint = partitionCheckpoint(req.Group, *req.Partition)
+	}
+
+	err = s.store.Commit(ctx, checkpoint, req.Position)
+	if err != nil {
+		s.log.Error(
+			"failed to commit checkpoint",
+			zap.String("checkpoint", checkpoint),
+			zap.Uint64("position", req.Position),
+			zap.Error(err),
+		)
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	position, err := s.store.Checkpoint(ctx, checkpoint)
+	if err != nil {
+		s.log.Error("failed to get checkpoint", zap.String("checkpoint", checkpoint), zap.Error(err))
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	s.log.Debug(
+		"committed checkpoint",
+		zap.String("checkpoint", checkpoint),
+		zap.Uint64("position", position),
+	)
+
+	return &eventlogpb.AckResponse{Position: position}, nil
+}
+
+// maxGroupLength is the longest consumer group name, in bytes, which every event store can persist
+// a checkpoint for, leaving room for the suffix of the checkpoints of its partitions
+const maxGroupLength = 240
+
+func validateGroup(group string) error {
+	if group == "" {
+		return errors.New("consumer group must be provided")
+	}
+	if len(group) > maxGroupLength {
+		return fmt.Errorf("consumer group must be at most %d bytes", maxGroupLength)
+	}
+	if strings.Contains(group, "#") {
+		return errors.New("consumer group must not contain '#'")
+	}
+	return nil
+}
+
+// startPosition returns the position to read the log after, which is the checkpoint of the
+// consumer group if the client is a member of one and it is later than the requested position.
+// Event stores commit events in the order of their positions, so no event after the checkpoint
+// can still be committed at or before it.
+func (s *service) startPosition(ctx context.Context, group string, after uint64) (uint64, error) {
+	if group == "" {
+		return after, nil
+	}
+	err := validateGroup(group)
+	if err != nil {
+		s.log.Warn("client provided an invalid consumer group", zap.Error(err))
+		return 0, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	checkpoint, err := s.store.Checkpoint(ctx, group)
+	if err != nil {
+		s.log.Error("failed to get checkpoint", zap.String("group", group), zap.Error(err))
+		return 0, status.Error(codes.Unavailable, err.Error())
+	}
+	if checkpoint > after {
+		s.log.Debug("resuming from checkpoint", zap.String("group", group), zap.Uint64("position", checkpoint))
+		return checkpoint, nil
+	}
+	return after, nil
+}
+
+// send sends every record read from the iterator to the client until it reaches the end of the log
+func (s *service) send(ctx context.Context, iter eventstore.Iterator, send func(*eventlogpb.Record) error) error {
+	for {
+		rec, err := iter.Next(ctx)
+		if err == io.EOF {
+			s.log.Debug("finished iterating over log")
+			return nil
+		}
+		if ctx.Err() != nil {
+			s.log.Debug("client stopped reading from log", zap.Error(ctx.Err()))
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if err != nil {
+			s.log.Error("failed to read next cloudevent from log", zap.Error(err))
+			return status.Error(codes.Unavailable, err.Error())
+		}
+
+		err = s.sendRecord(rec, nil, nil, send)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// sendRecord converts the record to protobuf, along with the partition of its event and the generation
+// of the assignment of the partition if they are non-nil, and sends it to the client
+func (s *service) sendRecord(rec *eventstore.Record, partition *uint32, generation *uint64, send func(*eventlogpb.Record) error) error {
+	ev := rec.Event
+	pbEvent, err := format.ToProto(ev)
+	if err != nil {
+		s.log.Error(
+			"failed to convert generic cloudevent to cloudevent protobuf",
+			zap.String("event_id", ev.ID()),
+			zap.String("event_type", ev.Type()),
+			zap.String("event_source", ev.Source()),
+			zap.Error(err),
+		)
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	err = send(&eventlogpb.Record{
+		Position:   rec.Position,
+		Event:      pbEvent,
+		Partition:  partition,
+		Version:    rec.Version,
+		Generation: generation,
+	})
+	if err != nil {
+		s.log.Error(
+			"failed to send cloudevent to client",
+			zap.String("event_id", ev.ID()),
+			zap.String("event_type", ev.Type()),
+			zap.String("event_source", ev.Source()),
+			zap.Error(err),
+		)
+		return err
+	}
 	return nil
 }
+
+func filterFromProto(f *eventlogpb.Filter) (eventstore.Filter, error) {
+	if f == nil {
+		return eventstore.Filter{}, nil
+	}
+
+	filter := eventstore.Filter{
+		Types:      f.Types,
+		Sources:    f.Sources,
+		Subjects:   f.Subjects,
+		Extensions: f.Extensions,
+	}
+	if f.StartTime != nil {
+		err := f.StartTime.CheckValid()
+		if err != nil {
+			return filter, err
+		}
+		filter.StartTime = f.StartTime.AsTime()
+	}
+	if f.EndTime != nil {
+		err := f.EndTime.CheckValid()
+		if err != nil {
+			return filter, err
+		}
+		filter.EndTime = f.EndTime.AsTime()
+	}
+	return filter, nil
+}
@@ -18,10 +18,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/z5labs/evrys/lib/eventstore"
 	"github.com/z5labs/evrys/svc-event-log/eventlogpb"
 
 	"github.com/cloudevents/sdk-go/binding/format/protobuf/v2/pb"
@@ -32,14 +35,77 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type mockEventStore struct {
-	append func(context.Context, *event.Event) error
+	append      func(context.Context, *event.Event, uint64) (uint64, error)
+	appendBatch func(context.Context, []*event.Event, uint64) ([]uint64, error)
+	iterate     func(context.Context, eventstore.Filter) (eventstore.Iterator, error)
+	readStream  func(context.Context, string, eventstore.ReadStreamOptions) (eventstore.Iterator, error)
+	get         func(context.Context, string, string) (*eventstore.Record, error)
+	subscribe   func(context.Context, eventstore.Filter) (eventstore.Iterator, error)
+	checkpoint  func(context.Context, string) (uint64, error)
+	commit      func(context.Context, string, uint64) error
 }
 
-func (s mockEventStore) Append(ctx context.Context, ev *event.Event) error {
-	return s.append(ctx, ev)
+func (s mockEventStore) Append(ctx context.Context, ev *event.Event, expectedVersion uint64) (uint64, error) {
+	return s.append(ctx, ev, expectedVersion)
+}
+
+func (s mockEventStore) AppendBatch(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+	return s.appendBatch(ctx, events, expectedVersion)
+}
+
+func (s mockEventStore) Iterate(ctx context.Context, filter eventstore.Filter) (eventstore.Iterator, error) {
+	return s.iterate(ctx, filter)
+}
+
+func (s mockEventStore) ReadStream(ctx context.Context, stream string, opts eventstore.ReadStreamOptions) (eventstore.Iterator, error) {
+	return s.readStream(ctx, stream, opts)
+}
+
+func (s mockEventStore) Get(ctx context.Context, source, id string) (*eventstore.Record, error) {
+	return s.get(ctx, source, id)
+}
+
+func (s mockEventStore) Subscribe(ctx context.Context, filter eventstore.Filter) (eventstore.Iterator, error) {
+	return s.subscribe(ctx, filter)
+}
+
+func (s mockEventStore) Checkpoint(ctx context.Context, group string) (uint64, error) {
+	return s.checkpoint(ctx, group)
+}
+
+func (s mockEventStore) Commit(ctx context.Context, group string, position uint64) error {
+	return s.commit(ctx, group, position)
+}
+
+type mockIterator struct {
+	next  func(context.Context) (*eventstore.Record, error)
+	close func(context.Context) error
+}
+
+func (it mockIterator) Next(ctx context.Context) (*eventstore.Record, error) {
+	return it.next(ctx)
+}
+
+func (it mockIterator) Close(ctx context.Context) error {
+	return it.close(ctx)
+}
+
+func sliceIterator(records ...*eventstore.Record) mockIterator {
+	return mockIterator{
+		next: func(ctx context.Context) (*eventstore.Record, error) {
+			if len(records) == 0 {
+				return nil, io.EOF
+			}
+			rec := records[0]
+			records = records[1:]
+			return rec, nil
+		},
+		close: func(ctx context.Context) error { return nil },
+	}
 }
 
 func ExampleServe() {
@@ -319,8 +385,8 @@ func TestService_Append(t *testing.T) {
 				defer close(errCh)
 				err := Serve(ctx, ServiceConfig{
 					EventStore: mockEventStore{
-						append: func(ctx context.Context, e *event.Event) error {
-							return errors.New("append failed")
+						append: func(ctx context.Context, e *event.Event, expectedVersion uint64) (uint64, error) {
+							return 0, errors.New("append failed")
 						},
 					},
 					Listener: ls,
@@ -358,9 +424,190 @@ func TestService_Append(t *testing.T) {
 				return
 			}
 		})
+
+		t.Run("if the stream is not at the expected version", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						append: func(ctx context.Context, e *event.Event, expectedVersion uint64) (uint64, error) {
+							return 0, eventstore.NewVersionConflictError(e.Subject(), expectedVersion, expectedVersion+1)
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			ev := &pb.CloudEvent{
+				Id:          "123",
+				Type:        "test",
+				Source:      "test",
+				SpecVersion: "1.0",
+			}
+			expectedVersion := uint64(1)
+			req := &eventlogpb.AppendRequest{Event: ev, ExpectedVersion: &expectedVersion}
+			_, err = client.Append(ctx, req)
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.Aborted, s.Code()) {
+				t.Log(err)
+				return
+			}
+		})
+
+		t.Run("if a different event with the same id and source already exists", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						append: func(ctx context.Context, e *event.Event, expectedVersion uint64) (uint64, error) {
+							return 0, eventstore.NewDuplicateEventError(e.Source(), e.ID())
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			ev := &pb.CloudEvent{
+				Id:          "123",
+				Type:        "test",
+				Source:      "test",
+				SpecVersion: "1.0",
+			}
+			req := &eventlogpb.AppendRequest{Event: ev}
+			_, err = client.Append(ctx, req)
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.AlreadyExists, s.Code()) {
+				t.Log(err)
+				return
+			}
+		})
+
+		t.Run("if the append keeps conflicting with concurrent appends", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						append: func(ctx context.Context, e *event.Event, expectedVersion uint64) (uint64, error) {
+							return 0, eventstore.NewRetryLimitError("test", 10, errors.New("conflict"))
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			ev := &pb.CloudEvent{
+				Id:          "123",
+				Type:        "test",
+				Source:      "test",
+				SpecVersion: "1.0",
+			}
+			req := &eventlogpb.AppendRequest{Event: ev}
+			_, err = client.Append(ctx, req)
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.Aborted, s.Code()) {
+				t.Log(err)
+				return
+			}
+		})
 	})
 
-	t.Run("will return an empty response", func(t *testing.T) {
+	t.Run("will return the appended event position", func(t *testing.T) {
 		t.Run("if the event is valid and the event store append operation is successful", func(t *testing.T) {
 			ls, err := net.Listen("tcp", ":0")
 			if !assert.Nil(t, err) {
@@ -381,8 +628,11 @@ func TestService_Append(t *testing.T) {
 				defer close(errCh)
 				err := Serve(ctx, ServiceConfig{
 					EventStore: mockEventStore{
-						append: func(ctx context.Context, e *event.Event) error {
-							return nil
+						append: func(ctx context.Context, e *event.Event, expectedVersion uint64) (uint64, error) {
+							if expectedVersion != eventstore.AnyVersion {
+								return 0, errors.New("unexpected version")
+							}
+							return 1, nil
 						},
 					},
 					Listener: ls,
@@ -409,7 +659,2431 @@ func TestService_Append(t *testing.T) {
 			if !assert.Nil(t, err) {
 				return
 			}
-			if !assert.NotNil(t, resp) {
+			if !assert.Equal(t, uint64(1), resp.Position) {
+				return
+			}
+		})
+
+		t.Run("if the stream is at the expected version", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						append: func(ctx context.Context, e *event.Event, expectedVersion uint64) (uint64, error) {
+							if expectedVersion != 3 {
+								return 0, eventstore.NewVersionConflictError(e.Subject(), expectedVersion, 3)
+							}
+							return 10, nil
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			ev := &pb.CloudEvent{
+				Id:          "123",
+				Type:        "test",
+				Source:      "test",
+				SpecVersion: "1.0",
+			}
+			expectedVersion := uint64(3)
+			req := &eventlogpb.AppendRequest{Event: ev, ExpectedVersion: &expectedVersion}
+			resp, err := client.Append(ctx, req)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, uint64(10), resp.Position) {
+				return
+			}
+		})
+	})
+}
+
+func TestService_AppendBatch(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if no cloudevents are provided in the request", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{},
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			req := &eventlogpb.AppendBatchRequest{}
+			_, err = client.AppendBatch(ctx, req)
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.InvalidArgument, s.Code()) {
+				t.Log(err)
+				return
+			}
+		})
+
+		t.Run("if any cloudevent is invalid", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						appendBatch: func(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+							return nil, errors.New("no events should have been appended")
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			events := []*pb.CloudEvent{
+				{
+					Id:          "1",
+					Type:        "test",
+					Source:      "test",
+					SpecVersion: "1.0",
+				},
+				{},
+			}
+			req := &eventlogpb.AppendBatchRequest{Events: events}
+			_, err = client.AppendBatch(ctx, req)
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.InvalidArgument, s.Code()) {
+				t.Log(err)
+				return
+			}
+		})
+
+		t.Run("if the streams are not at the expected version", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						appendBatch: func(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+							return nil, eventstore.NewVersionConflictError("test", expectedVersion, expectedVersion+1)
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			events := []*pb.CloudEvent{
+				{
+					Id:          "1",
+					Type:        "test",
+					Source:      "test",
+					SpecVersion: "1.0",
+				},
+				{
+					Id:          "2",
+					Type:        "test",
+					Source:      "test",
+					SpecVersion: "1.0",
+				},
+			}
+			expectedVersion := uint64(1)
+			req := &eventlogpb.AppendBatchRequest{Events: events, ExpectedVersion: &expectedVersion}
+			_, err = client.AppendBatch(ctx, req)
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.Aborted, s.Code()) {
+				t.Log(err)
+				return
+			}
+		})
+
+		t.Run("if the event store implementation fails to append", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						appendBatch: func(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+							return nil, errors.New("append failed")
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			events := []*pb.CloudEvent{
+				{
+					Id:          "1",
+					Type:        "test",
+					Source:      "test",
+					SpecVersion: "1.0",
+				},
+				{
+					Id:          "2",
+					Type:        "test",
+					Source:      "test",
+					SpecVersion: "1.0",
+				},
+			}
+			req := &eventlogpb.AppendBatchRequest{Events: events}
+			_, err = client.AppendBatch(ctx, req)
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.Unavailable, s.Code()) {
+				t.Log(err)
+				return
+			}
+		})
+	})
+
+	t.Run("will return the appended event positions", func(t *testing.T) {
+		t.Run("if every event is valid and the event store append operation is successful", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						appendBatch: func(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+							if expectedVersion != eventstore.AnyVersion {
+								return nil, errors.New("unexpected version")
+							}
+							positions := make([]uint64, len(events))
+							for i := range events {
+								positions[i] = uint64(i + 1)
+							}
+							return positions, nil
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			events := []*pb.CloudEvent{
+				{
+					Id:          "1",
+					Type:        "test",
+					Source:      "test",
+					SpecVersion: "1.0",
+				},
+				{
+					Id:          "2",
+					Type:        "test",
+					Source:      "test",
+					SpecVersion: "1.0",
+				},
+			}
+			req := &eventlogpb.AppendBatchRequest{Events: events}
+			resp, err := client.AppendBatch(ctx, req)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, []uint64{1, 2}, resp.Positions) {
+				return
+			}
+		})
+	})
+}
+
+func TestService_AppendStream(t *testing.T) {
+	newEvent := func(id string) *pb.CloudEvent {
+		return &pb.CloudEvent{
+			Id:          id,
+			Source:      "test",
+			SpecVersion: "1.0",
+			Type:        "test",
+		}
+	}
+
+	// appendStream sends every event on a new stream and returns the acknowledgements for them
+	appendStream := func(ctx context.Context, client eventlogpb.EventLogClient, events ...*pb.CloudEvent) ([]*eventlogpb.AppendStreamResponse, error) {
+		stream, err := client.AppendStream(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range events {
+			err := stream.Send(&eventlogpb.AppendStreamRequest{Event: ev})
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = stream.CloseSend()
+		if err != nil {
+			return nil, err
+		}
+
+		var resps []*eventlogpb.AppendStreamResponse
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				return resps, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			resps = append(resps, resp)
+		}
+	}
+
+	t.Run("will acknowledge an event with an error", func(t *testing.T) {
+		t.Run("if the cloudevent is invalid", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: store,
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			resps, err := appendStream(ctx, client, newEvent("1"), newEvent(""), newEvent("2"))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Len(t, resps, 3) {
+				return
+			}
+			if !assert.Equal(t, uint64(1), resps[1].Sequence) {
+				return
+			}
+			if !assert.NotNil(t, resps[1].Error) {
+				return
+			}
+			if !assert.Equal(t, uint32(codes.InvalidArgument), resps[1].Error.Code) {
+				return
+			}
+			if !assert.Equal(t, []uint64{1, 2}, []uint64{resps[0].Position, resps[2].Position}) {
+				return
+			}
+		})
+
+		t.Run("if a different event with the same id and source already exists", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: store,
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			duplicate := newEvent("1")
+			duplicate.Type = "other"
+			resps, err := appendStream(ctx, client, newEvent("1"), duplicate, newEvent("2"))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Len(t, resps, 3) {
+				return
+			}
+			if !assert.NotNil(t, resps[1].Error) {
+				return
+			}
+			if !assert.Equal(t, uint32(codes.AlreadyExists), resps[1].Error.Code) {
+				return
+			}
+			if !assert.Nil(t, resps[0].Error) {
+				return
+			}
+			if !assert.Nil(t, resps[2].Error) {
+				return
+			}
+			if !assert.Less(t, resps[0].Position, resps[2].Position) {
+				return
+			}
+		})
+
+		t.Run("if the event store implementation fails to append the events individually", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						append: func(ctx context.Context, ev *event.Event, expectedVersion uint64) (uint64, error) {
+							return 0, errors.New("append failed")
+						},
+						appendBatch: func(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+							return nil, eventstore.NewUnbatchableError("mock", "its events are in different partitions")
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			resps, err := appendStream(ctx, client, newEvent("1"))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Len(t, resps, 1) {
+				return
+			}
+			if !assert.NotNil(t, resps[0].Error) {
+				return
+			}
+			if !assert.Equal(t, uint32(codes.Unavailable), resps[0].Error.Code) {
+				return
+			}
+		})
+
+		t.Run("if the event store implementation fails to append the batch", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						append: func(ctx context.Context, ev *event.Event, expectedVersion uint64) (uint64, error) {
+							return 0, errors.New("events should not have been appended individually")
+						},
+						appendBatch: func(ctx context.Context, events []*event.Event, expectedVersion uint64) ([]uint64, error) {
+							return []uint64{1, 2}, errors.New("append batch failed")
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			resps, err := appendStream(ctx, client, newEvent("1"), newEvent("2"))
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Len(t, resps, 2) {
+				return
+			}
+			for _, resp := range resps {
+				if !assert.NotNil(t, resp.Error) {
+					return
+				}
+				if !assert.Equal(t, uint32(codes.Unavailable), resp.Error.Code) {
+					return
+				}
+				if !assert.Equal(t, "append batch failed", resp.Error.Message) {
+					return
+				}
+				if !assert.Zero(t, resp.Position) {
+					return
+				}
+			}
+		})
+	})
+
+	t.Run("will acknowledge every event with its position", func(t *testing.T) {
+		t.Run("if every event is valid and the event store append operation is successful", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: store,
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			events := make([]*pb.CloudEvent, 2*maxAppendStreamBatch)
+			for i := range events {
+				events[i] = newEvent(strconv.Itoa(i))
+			}
+			resps, err := appendStream(ctx, client, events...)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Len(t, resps, len(events)) {
+				return
+			}
+			for i, resp := range resps {
+				if !assert.Nil(t, resp.Error) {
+					return
+				}
+				if !assert.Equal(t, uint64(i), resp.Sequence) {
+					return
+				}
+				if !assert.Equal(t, uint64(i+1), resp.Position) {
+					return
+				}
+			}
+		})
+	})
+}
+
+func TestService_GetEvent(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if no id is provided in the request", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{},
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			_, err = client.GetEvent(ctx, &eventlogpb.GetEventRequest{Source: "test"})
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.InvalidArgument, s.Code()) {
+				return
+			}
+		})
+
+		t.Run("if the event does not exist", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: store,
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			_, err = client.GetEvent(ctx, &eventlogpb.GetEventRequest{Source: "test", Id: "1"})
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.NotFound, s.Code()) {
+				return
+			}
+		})
+
+		t.Run("if the event store implementation fails to get the event", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						get: func(ctx context.Context, source, id string) (*eventstore.Record, error) {
+							return nil, errors.New("get failed")
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			_, err = client.GetEvent(ctx, &eventlogpb.GetEventRequest{Source: "test", Id: "1"})
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.Unavailable, s.Code()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return the event", func(t *testing.T) {
+		t.Run("if an event with the source and id exists", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			for _, source := range []string{"test", "other"} {
+				ev := event.New()
+				ev.SetID("1")
+				ev.SetType("test")
+				ev.SetSource(source)
+				_, err := store.Append(ctx, &ev, eventstore.AnyVersion)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: store,
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			rec, err := client.GetEvent(ctx, &eventlogpb.GetEventRequest{Source: "other", Id: "1"})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, uint64(2), rec.Position) {
+				return
+			}
+			if !assert.Equal(t, "other", rec.Event.Source) {
+				return
+			}
+			if !assert.Equal(t, "1", rec.Event.Id) {
+				return
+			}
+		})
+	})
+}
+
+func TestService_ReadStream(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the stream is not provided", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{},
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			stream, err := client.ReadStream(ctx, &eventlogpb.ReadStreamRequest{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = stream.Recv()
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.InvalidArgument, s.Code()) {
+				return
+			}
+		})
+
+		t.Run("if the event store implementation fails to read the stream", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						readStream: func(ctx context.Context, stream string, opts eventstore.ReadStreamOptions) (eventstore.Iterator, error) {
+							return nil, errors.New("read stream failed")
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			stream, err := client.ReadStream(ctx, &eventlogpb.ReadStreamRequest{Stream: "a"})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = stream.Recv()
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.Unavailable, s.Code()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will stream the events of the stream in version order", func(t *testing.T) {
+		t.Run("if the client reads the stream backward", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			for i, subject := range []string{"a", "b", "a", "a", "b", "a"} {
+				ev := event.New()
+				ev.SetID(strconv.Itoa(i + 1))
+				ev.SetType("test")
+				ev.SetSource("test")
+				ev.SetSubject(subject)
+				_, err := store.Append(ctx, &ev, eventstore.AnyVersion)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: store,
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			req := &eventlogpb.ReadStreamRequest{
+				Stream:      "a",
+				FromVersion: 3,
+				MaxCount:    2,
+				Backward:    true,
+			}
+			stream, err := client.ReadStream(ctx, req)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			var ids []string
+			var versions []uint64
+			var positions []uint64
+			for {
+				rec, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if !assert.Nil(t, err) {
+					return
+				}
+				ids = append(ids, rec.Event.Id)
+				versions = append(versions, rec.Version)
+				positions = append(positions, rec.Position)
+			}
+			if !assert.Equal(t, []string{"4", "3"}, ids) {
+				return
+			}
+			if !assert.Equal(t, []uint64{3, 2}, versions) {
+				return
+			}
+			if !assert.Equal(t, []uint64{4, 3}, positions) {
+				return
+			}
+		})
+	})
+}
+
+func TestService_Iterate(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the filter contains an invalid timestamp", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{},
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			req := &eventlogpb.IterateRequest{
+				Filter: &eventlogpb.Filter{
+					StartTime: &timestamppb.Timestamp{Nanos: -1},
+				},
+			}
+			stream, err := client.Iterate(ctx, req)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = stream.Recv()
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.InvalidArgument, s.Code()) {
+				return
+			}
+		})
+
+		t.Run("if the event store implementation fails to iterate", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						iterate: func(ctx context.Context, filter eventstore.Filter) (eventstore.Iterator, error) {
+							return nil, errors.New("iterate failed")
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			stream, err := client.Iterate(ctx, &eventlogpb.IterateRequest{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = stream.Recv()
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.Unavailable, s.Code()) {
+				return
+			}
+		})
+
+		t.Run("if the event store iterator fails to read the next event", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						iterate: func(ctx context.Context, filter eventstore.Filter) (eventstore.Iterator, error) {
+							return mockIterator{
+								next: func(ctx context.Context) (*eventstore.Record, error) {
+									return nil, errors.New("next failed")
+								},
+								close: func(ctx context.Context) error { return nil },
+							}, nil
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			stream, err := client.Iterate(ctx, &eventlogpb.IterateRequest{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = stream.Recv()
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.Unavailable, s.Code()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will stream every event", func(t *testing.T) {
+		t.Run("if the event store iterator successfully reads all events", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			records := make([]*eventstore.Record, 0, 3)
+			for i, id := range []string{"1", "2", "3"} {
+				ev := event.New()
+				ev.SetID(id)
+				ev.SetType("test")
+				ev.SetSource("test")
+				records = append(records, &eventstore.Record{
+					Position: uint64(i + 1),
+					Event:    &ev,
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						iterate: func(ctx context.Context, filter eventstore.Filter) (eventstore.Iterator, error) {
+							return sliceIterator(records...), nil
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			stream, err := client.Iterate(ctx, &eventlogpb.IterateRequest{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			var ids []string
+			var positions []uint64
+			for {
+				rec, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if !assert.Nil(t, err) {
+					return
+				}
+				ids = append(ids, rec.Event.Id)
+				positions = append(positions, rec.Position)
+			}
+			if !assert.Equal(t, []string{"1", "2", "3"}, ids) {
+				return
+			}
+			if !assert.Equal(t, []uint64{1, 2, 3}, positions) {
+				return
+			}
+		})
+	})
+	t.Run("will push the filter down to the event store", func(t *testing.T) {
+		t.Run("if the client provides a filter", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+			end := start.Add(time.Hour)
+			filterCh := make(chan eventstore.Filter, 1)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						iterate: func(ctx context.Context, filter eventstore.Filter) (eventstore.Iterator, error) {
+							filterCh <- filter
+							return sliceIterator(), nil
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			req := &eventlogpb.IterateRequest{
+				AfterPosition: 5,
+				Filter: &eventlogpb.Filter{
+					Types:      []string{"a", "b"},
+					Sources:    []string{"c"},
+					Subjects:   []string{"d"},
+					StartTime:  timestamppb.New(start),
+					EndTime:    timestamppb.New(end),
+					Extensions: map[string]string{"tenant": "acme"},
+				},
+			}
+			stream, err := client.Iterate(ctx, req)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = stream.Recv()
+			if !assert.Equal(t, io.EOF, err) {
+				return
+			}
+
+			expected := eventstore.Filter{
+				AfterPosition: 5,
+				Types:         []string{"a", "b"},
+				Sources:       []string{"c"},
+				Subjects:      []string{"d"},
+				StartTime:     start,
+				EndTime:       end,
+				Extensions:    map[string]string{"tenant": "acme"},
+			}
+			if !assert.Equal(t, expected, <-filterCh) {
+				return
+			}
+		})
+	})
+}
+
+func TestService_Subscribe(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the filter contains an invalid timestamp", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{},
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			req := &eventlogpb.SubscribeRequest{
+				Filter: &eventlogpb.Filter{
+					EndTime: &timestamppb.Timestamp{Nanos: -1},
+				},
+			}
+			stream, err := client.Subscribe(ctx, req)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = stream.Recv()
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.InvalidArgument, s.Code()) {
+				return
+			}
+		})
+
+		t.Run("if the client subscribes as a member to a service which may not be the only instance", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{},
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			stream, err := client.Subscribe(ctx, &eventlogpb.SubscribeRequest{Group: "projector", Member: "a"})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = stream.Recv()
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.FailedPrecondition, s.Code()) {
+				return
+			}
+		})
+
+		t.Run("if the event store implementation fails to subscribe", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						subscribe: func(ctx context.Context, filter eventstore.Filter) (eventstore.Iterator, error) {
+							return nil, errors.New("subscribe failed")
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			stream, err := client.Subscribe(ctx, &eventlogpb.SubscribeRequest{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			_, err = stream.Recv()
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.Unavailable, s.Code()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will stream stored and then appended events", func(t *testing.T) {
+		t.Run("if the client subscribes after a position", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			newEvent := func(id string) *event.Event {
+				ev := event.New()
+				ev.SetID(id)
+				ev.SetType("test")
+				ev.SetSource("test")
+				return &ev
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			for _, id := range []string{"1", "2", "3"} {
+				_, err := store.Append(ctx, newEvent(id), eventstore.AnyVersion)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: store,
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			streamCtx, cancelStream := context.WithCancel(ctx)
+			defer cancelStream()
+			stream, err := client.Subscribe(streamCtx, &eventlogpb.SubscribeRequest{AfterPosition: 1})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			var ids []string
+			var positions []uint64
+			for len(ids) < 4 {
+				rec, err := stream.Recv()
+				if !assert.Nil(t, err) {
+					return
+				}
+				ids = append(ids, rec.Event.Id)
+				positions = append(positions, rec.Position)
+
+				if len(ids) == 2 {
+					_, err := store.Append(ctx, newEvent("4"), eventstore.AnyVersion)
+					if !assert.Nil(t, err) {
+						return
+					}
+					_, err = store.Append(ctx, newEvent("5"), eventstore.AnyVersion)
+					if !assert.Nil(t, err) {
+						return
+					}
+				}
+			}
+			if !assert.Equal(t, []string{"2", "3", "4", "5"}, ids) {
+				return
+			}
+			if !assert.Equal(t, []uint64{2, 3, 4, 5}, positions) {
+				return
+			}
+		})
+	})
+
+	t.Run("will resume from the checkpoint of the consumer group", func(t *testing.T) {
+		t.Run("if the client is a member of a group which has acked a later position", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			for _, id := range []string{"1", "2", "3"} {
+				ev := event.New()
+				ev.SetID(id)
+				ev.SetType("test")
+				ev.SetSource("test")
+				_, err := store.Append(ctx, &ev, eventstore.AnyVersion)
+				if !assert.Nil(t, err) {
+					return
+				}
+			}
+
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: store,
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			resp, err := client.Ack(ctx, &eventlogpb.AckRequest{Group: "projector", Position: 2})
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, uint64(2), resp.Position) {
+				return
+			}
+
+			streamCtx, cancelStream := context.WithCancel(ctx)
+			defer cancelStream()
+			stream, err := client.Subscribe(streamCtx, &eventlogpb.SubscribeRequest{Group: "projector", AfterPosition: 1})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			rec, err := stream.Recv()
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, uint64(3), rec.Position) {
+				return
+			}
+		})
+
+		t.Run("if events were appended concurrently while the group acked them", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: store,
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			// several goroutines append events while the group is reading them
+			const appenders, appends = 4, 25
+			appendErrs := make(chan error, appenders)
+			for i := 0; i < appenders; i++ {
+				go func(i int) {
+					for j := 0; j < appends; j++ {
+						ev := event.New()
+						ev.SetID(fmt.Sprintf("%d-%d", i, j))
+						ev.SetType("test")
+						ev.SetSource("test")
+						_, err := store.Append(ctx, &ev, eventstore.AnyVersion)
+						if err != nil {
+							appendErrs <- err
+							return
+						}
+					}
+					appendErrs <- nil
+				}(i)
+			}
+
+			// recvAndAck receives up to n records, acking each of them, and returns their positions
+			recvAndAck := func(n int) ([]uint64, error) {
+				streamCtx, cancelStream := context.WithCancel(ctx)
+				defer cancelStream()
+				stream, err := client.Subscribe(streamCtx, &eventlogpb.SubscribeRequest{Group: "projector"})
+				if err != nil {
+					return nil, err
+				}
+
+				var positions []uint64
+				for len(positions) < n {
+					rec, err := stream.Recv()
+					if err != nil {
+						return nil, err
+					}
+					_, err = client.Ack(ctx, &eventlogpb.AckRequest{Group: "projector", Position: rec.Position})
+					if err != nil {
+						return nil, err
+					}
+					positions = append(positions, rec.Position)
+				}
+				return positions, nil
+			}
+
+			// the group disconnects part way through and resumes from its checkpoint
+			positions, err := recvAndAck(appenders * appends / 2)
+			if !assert.Nil(t, err) {
+				return
+			}
+			resumed, err := recvAndAck(appenders*appends - len(positions))
+			if !assert.Nil(t, err) {
+				return
+			}
+			positions = append(positions, resumed...)
+
+			for i := 0; i < appenders; i++ {
+				if !assert.Nil(t, <-appendErrs) {
+					return
+				}
+			}
+
+			expected := make([]uint64, appenders*appends)
+			for i := range expected {
+				expected[i] = uint64(i + 1)
+			}
+			if !assert.Equal(t, expected, positions) {
+				return
+			}
+		})
+	})
+
+	t.Run("will split the partitions of the group between its members", func(t *testing.T) {
+		t.Run("if several members subscribe to the same group", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			// appendEvents appends an event for each partition and returns their positions by partition
+			var n int
+			appendEvents := func(ctx context.Context) (map[uint32]uint64, error) {
+				positions := make(map[uint32]uint64)
+				for len(positions) < 2 {
+					n++
+					ev := event.New()
+					ev.SetID(strconv.Itoa(n))
+					ev.SetType("test")
+					ev.SetSource("test")
+					ev.SetExtension(eventstore.PartitionKeyExtension, "order-"+strconv.Itoa(n))
+
+					p := eventstore.PartitionOf(&ev, 2)
+					if _, ok := positions[p]; ok {
+						continue
+					}
+					position, err := store.Append(ctx, &ev, eventstore.AnyVersion)
+					if err != nil {
+						return nil, err
+					}
+					positions[p] = position
+				}
+				return positions, nil
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			stored, err := appendEvents(ctx)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore:     store,
+					Listener:       ls,
+					Partitions:     2,
+					SingleInstance: true,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			// recv returns the positions of the next n records by their partition,
+			// keeping the generation the last record was sent under
+			var generation uint64
+			recv := func(stream eventlogpb.EventLog_SubscribeClient, n int) (map[uint32]uint64, error) {
+				positions := make(map[uint32]uint64)
+				for i := 0; i < n; i++ {
+					rec, err := stream.Recv()
+					if err != nil {
+						return nil, err
+					}
+					if rec.Partition == nil {
+						return nil, errors.New("record has no partition")
+					}
+					if rec.Generation == nil {
+						return nil, errors.New("record has no generation")
+					}
+					positions[*rec.Partition] = rec.Position
+					generation = *rec.Generation
+				}
+				return positions, nil
+			}
+
+			streamCtxA, cancelStreamA := context.WithCancel(ctx)
+			defer cancelStreamA()
+			streamA, err := client.Subscribe(streamCtxA, &eventlogpb.SubscribeRequest{Group: "projector", Member: "a"})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			// the only member of the group is assigned every partition
+			positions, err := recv(streamA, 2)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, stored, positions) {
+				return
+			}
+
+			streamCtxB, cancelStreamB := context.WithCancel(ctx)
+			defer cancelStreamB()
+			streamB, err := client.Subscribe(streamCtxB, &eventlogpb.SubscribeRequest{Group: "projector", Member: "b"})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			// partition 1 is reassigned to the new member, which starts from its checkpoint
+			positions, err = recv(streamB, 1)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, map[uint32]uint64{1: stored[1]}, positions) {
+				return
+			}
+
+			appended, err := appendEvents(ctx)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			positions, err = recv(streamA, 1)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, map[uint32]uint64{0: appended[0]}, positions) {
+				return
+			}
+
+			positions, err = recv(streamB, 1)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, map[uint32]uint64{1: appended[1]}, positions) {
+				return
+			}
+
+			partition := uint32(1)
+			staleGeneration := generation
+			_, err = client.Ack(ctx, &eventlogpb.AckRequest{Group: "projector", Position: appended[1], Partition: &partition, Generation: &staleGeneration})
+			if !assert.Nil(t, err) {
+				return
+			}
+			cancelStreamB()
+
+			// once the member leaves, its partition is reassigned and resumes from its checkpoint
+			appended, err = appendEvents(ctx)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			positions, err = recv(streamA, 2)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if !assert.Equal(t, appended, positions) {
+				return
+			}
+
+			// the member which left can no longer ack the partition it was assigned
+			_, err = client.Ack(ctx, &eventlogpb.AckRequest{Group: "projector", Position: appended[1], Partition: &partition, Generation: &staleGeneration})
+			if !assert.Equal(t, codes.FailedPrecondition, status.Code(err)) {
+				return
+			}
+		})
+	})
+}
+
+func TestService_Ack(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if the consumer group is not provided", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{},
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			_, err = client.Ack(ctx, &eventlogpb.AckRequest{Position: 1})
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.InvalidArgument, s.Code()) {
+				return
+			}
+		})
+
+		t.Run("if the partition is not less than the number of partitions", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{},
+					Listener:   ls,
+					Partitions: 4,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			partition := uint32(4)
+			_, err = client.Ack(ctx, &eventlogpb.AckRequest{Group: "projector", Position: 1, Partition: &partition})
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.InvalidArgument, s.Code()) {
+				return
+			}
+		})
+
+		t.Run("if a partition is acked without its generation", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{},
+					Listener:   ls,
+					Partitions: 4,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			partition := uint32(1)
+			_, err = client.Ack(ctx, &eventlogpb.AckRequest{Group: "projector", Position: 1, Partition: &partition})
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.InvalidArgument, s.Code()) {
+				return
+			}
+		})
+
+		t.Run("if the partitions of the group were reassigned since the event was sent", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						commit: func(ctx context.Context, group string, position uint64) error {
+							return errors.New("checkpoint should not have been committed")
+						},
+					},
+					Listener:       ls,
+					Partitions:     4,
+					SingleInstance: true,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			partition := uint32(1)
+			generation := uint64(1)
+			_, err = client.Ack(ctx, &eventlogpb.AckRequest{Group: "projector", Position: 1, Partition: &partition, Generation: &generation})
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.FailedPrecondition, s.Code()) {
+				return
+			}
+		})
+
+		t.Run("if the event store implementation fails to commit the checkpoint", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: mockEventStore{
+						commit: func(ctx context.Context, group string, position uint64) error {
+							return errors.New("commit failed")
+						},
+					},
+					Listener: ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			_, err = client.Ack(ctx, &eventlogpb.AckRequest{Group: "projector", Position: 1})
+			if !assert.Error(t, err) {
+				return
+			}
+
+			s, ok := status.FromError(err)
+			if !assert.True(t, ok) {
+				t.Log(err)
+				return
+			}
+			if !assert.Equal(t, codes.Unavailable, s.Code()) {
+				return
+			}
+		})
+	})
+
+	t.Run("will return the checkpoint of the group", func(t *testing.T) {
+		t.Run("if the checkpoint is committed", func(t *testing.T) {
+			ls, err := net.Listen("tcp", ":0")
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			errCh := make(chan error, 1)
+			defer func() {
+				err := <-errCh
+				if !assert.ErrorIs(t, err, context.Canceled) {
+					return
+				}
+			}()
+
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				defer close(errCh)
+				err := Serve(ctx, ServiceConfig{
+					EventStore: store,
+					Listener:   ls,
+				})
+				errCh <- err
+			}()
+
+			cc, err := grpc.Dial(ls.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer cc.Close()
+
+			client := eventlogpb.NewEventLogClient(cc)
+
+			var positions []uint64
+			for _, position := range []uint64{5, 3} {
+				resp, err := client.Ack(ctx, &eventlogpb.AckRequest{Group: "projector", Position: position})
+				if !assert.Nil(t, err) {
+					return
+				}
+				positions = append(positions, resp.Position)
+			}
+			if !assert.Equal(t, []uint64{5, 5}, positions) {
 				return
 			}
 		})
@@ -0,0 +1,153 @@
+// Copyright 2023 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relay
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+	"github.com/z5labs/evrys/lib/notifier"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+)
+
+// Config
+type Config struct {
+	Logger   *zap.Logger
+	Outbox   eventstore.Outbox
+	Notifier notifier.Notifier
+
+	// PollInterval is how long to wait before reading the outbox again once it has no
+	// more pending events, or after failing to dispatch them. It defaults to 1s.
+	PollInterval time.Duration
+
+	// BatchSize is the most pending events read from the outbox at once. It defaults to 100.
+	BatchSize int
+}
+
+// Run delivers the events pending dispatch in the outbox to the notifier, in the order they
+// were appended, and marks them as dispatched once the notifier has accepted them. It runs
+// until the context is cancelled.
+//
+// Delivery is at-least-once, since an event is notified of again if marking it as dispatched
+// fails. Failures are logged and retried after the poll interval, so they never stop the relay.
+// Events rejected with a *notifier.PermanentError are logged and skipped rather than retried.
+func Run(ctx context.Context, cfg Config) error {
+	if cfg.Outbox == nil {
+		return errors.New("outbox must be provided")
+	}
+	if cfg.Notifier == nil {
+		return errors.New("notifier must be provided")
+	}
+	r := &relay{
+		log:          cfg.Logger,
+		outbox:       cfg.Outbox,
+		notifier:     cfg.Notifier,
+		pollInterval: cfg.PollInterval,
+		batchSize:    cfg.BatchSize,
+	}
+	if r.log == nil {
+		r.log = zap.NewNop()
+	}
+	if r.pollInterval <= 0 {
+		r.pollInterval = defaultPollInterval
+	}
+	if r.batchSize <= 0 {
+		r.batchSize = defaultBatchSize
+	}
+	return r.run(ctx)
+}
+
+type relay struct {
+	log          *zap.Logger
+	outbox       eventstore.Outbox
+	notifier     notifier.Notifier
+	pollInterval time.Duration
+	batchSize    int
+}
+
+func (r *relay) run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		n, err := r.dispatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.log.Error("failed to dispatch pending events", zap.Int("dispatched", n), zap.Error(err))
+		}
+
+		// a full batch means there are likely more pending events, so read them straight away
+		wait := r.pollInterval
+		if err == nil && n == r.batchSize {
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
+
+// dispatch notifies of a batch of pending events and marks them as dispatched, returning how many
+// were dispatched. It stops at the first event which can not be notified of, so that events are
+// never notified of ahead of the events appended before them. Events the notifier can never accept
+// are logged and marked as dispatched instead, since retrying them would stop the relay for good.
+func (r *relay) dispatch(ctx context.Context) (int, error) {
+	records, err := r.outbox.Pending(ctx, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	notified := make([]*eventstore.Record, 0, len(records))
+	var notifyErr error
+	for _, rec := range records {
+		notifyErr = r.notifier.Notify(ctx, rec.Event)
+		var permanentErr *notifier.PermanentError
+		if errors.As(notifyErr, &permanentErr) {
+			r.log.Error(
+				"skipping event which can never be notified of",
+				zap.String("event_id", rec.Event.ID()),
+				zap.String("event_source", rec.Event.Source()),
+				zap.Uint64("position", rec.Position),
+				zap.Error(notifyErr),
+			)
+			notifyErr = nil
+		}
+		if notifyErr != nil {
+			break
+		}
+		notified = append(notified, rec)
+	}
+	if len(notified) == 0 {
+		return 0, notifyErr
+	}
+
+	err = r.outbox.Dispatched(ctx, notified...)
+	if err != nil {
+		return 0, err
+	}
+	r.log.Debug("dispatched pending events", zap.Int("events", len(notified)))
+	return len(notified), notifyErr
+}
@@ -0,0 +1,184 @@
+// Copyright 2023 Z5Labs and Contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/z5labs/evrys/lib/eventstore"
+	"github.com/z5labs/evrys/lib/notifier"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingNotifier struct {
+	mu       sync.Mutex
+	notified []string
+	failures int
+	poisoned map[string]bool
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, ev *event.Event) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failures > 0 {
+		n.failures--
+		return errors.New("unavailable")
+	}
+	if n.poisoned[ev.ID()] {
+		return notifier.NewNotifyError("test", ev.ID(), ev.Source(), notifier.NewPermanentError(errors.New("too large")))
+	}
+	n.notified = append(n.notified, ev.ID())
+	return nil
+}
+
+func (n *recordingNotifier) ids() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string(nil), n.notified...)
+}
+
+func newTestEvent(id string) *event.Event {
+	ev := event.New()
+	ev.SetID(id)
+	ev.SetSource("relay_test")
+	ev.SetType("test")
+	ev.SetSubject("test")
+	ev.SetTime(time.Now().UTC())
+	return &ev
+}
+
+func appendTestEvents(t *testing.T, store *eventstore.Memory, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprint(i + 1)
+		_, err := store.Append(context.Background(), newTestEvent(ids[i]), eventstore.AnyVersion)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+	}
+	return ids
+}
+
+// runRelay runs the relay until the outbox has no pending events
+func runRelay(t *testing.T, cfg Config) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(ctx, cfg)
+	}()
+
+	assert.Eventually(t, func() bool {
+		pending, err := cfg.Outbox.Pending(context.Background(), 1)
+		return err == nil && len(pending) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestRun(t *testing.T) {
+	t.Run("will return an error", func(t *testing.T) {
+		t.Run("if no outbox is provided", func(t *testing.T) {
+			err := Run(context.Background(), Config{Notifier: &recordingNotifier{}})
+			if !assert.Error(t, err) {
+				return
+			}
+		})
+
+		t.Run("if no notifier is provided", func(t *testing.T) {
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{Outbox: true})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = Run(context.Background(), Config{Outbox: store})
+			if !assert.Error(t, err) {
+				return
+			}
+		})
+
+		t.Run("only once the context is cancelled if the outbox is disabled", func(t *testing.T) {
+			store, err := eventstore.NewMemory(eventstore.MemoryConfig{})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			err = Run(ctx, Config{
+				Outbox:       store,
+				Notifier:     &recordingNotifier{},
+				PollInterval: 10 * time.Millisecond,
+			})
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		})
+	})
+
+	t.Run("will notify of every pending event in order", func(t *testing.T) {
+		store, err := eventstore.NewMemory(eventstore.MemoryConfig{Outbox: true})
+		if !assert.Nil(t, err) {
+			return
+		}
+		ids := appendTestEvents(t, store, 5)
+
+		n := &recordingNotifier{}
+		runRelay(t, Config{
+			Outbox:       store,
+			Notifier:     n,
+			PollInterval: 10 * time.Millisecond,
+			BatchSize:    2,
+		})
+		assert.Equal(t, ids, n.ids())
+	})
+
+	t.Run("will retry events which failed to be notified of", func(t *testing.T) {
+		store, err := eventstore.NewMemory(eventstore.MemoryConfig{Outbox: true})
+		if !assert.Nil(t, err) {
+			return
+		}
+		ids := appendTestEvents(t, store, 3)
+
+		n := &recordingNotifier{failures: 2}
+		runRelay(t, Config{
+			Outbox:       store,
+			Notifier:     n,
+			PollInterval: 10 * time.Millisecond,
+		})
+		assert.Equal(t, ids, n.ids())
+	})
+	t.Run("will skip events which can never be notified of", func(t *testing.T) {
+		store, err := eventstore.NewMemory(eventstore.MemoryConfig{Outbox: true})
+		if !assert.Nil(t, err) {
+			return
+		}
+		ids := appendTestEvents(t, store, 3)
+
+		n := &recordingNotifier{poisoned: map[string]bool{ids[1]: true}}
+		runRelay(t, Config{
+			Outbox:       store,
+			Notifier:     n,
+			PollInterval: 10 * time.Millisecond,
+		})
+		assert.Equal(t, []string{ids[0], ids[2]}, n.ids())
+	})
+}